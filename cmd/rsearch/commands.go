@@ -0,0 +1,429 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// paramsFlag is a repeatable name=value flag
+type paramsFlag map[string]interface{}
+
+func (p paramsFlag) String() string {
+	return ""
+}
+
+func (p paramsFlag) Set(value string) error {
+	pos := strings.IndexByte(value, '=')
+	if pos <= 0 {
+		return fmt.Errorf("expected name=value, got %q", value)
+	}
+	p[value[:pos]] = value[pos+1:]
+	return nil
+}
+
+// listFlag splits a comma separated flag, or returns nil if it is empty
+func listFlag(value string) []string {
+	if value == "" {
+		return nil
+	}
+	list := strings.Split(value, ",")
+	for ii := range list {
+		list[ii] = strings.TrimSpace(list[ii])
+	}
+	return list
+}
+
+// queryFlags declares the flags of the fields of redisearch.Query, and returns the function building the query
+func queryFlags(fs *flag.FlagSet) func(raw string) *redisearch.Query {
+	offset := fs.Int("offset", redisearch.DefaultOffset, "offset of the first result")
+	num := fs.Int("num", redisearch.DefaultNum, "number of results")
+	noContent := fs.Bool("nocontent", false, "return only the document ids")
+	verbatim := fs.Bool("verbatim", false, "do not expand the query terms with their stems")
+	withScores := fs.Bool("withscores", false, "return the scores of the documents")
+	withPayloads := fs.Bool("withpayloads", false, "return the payloads of the documents")
+	withStopWords := fs.Bool("nostopwords", false, "do not filter the stop words from the query")
+	inOrder := fs.Bool("inorder", false, "match the query terms in the order of the query")
+	slop := fs.Int("slop", -1, "number of intervening terms allowed between the query terms, -1 for any")
+	inKeys := fs.String("inkeys", "", "comma separated keys to search in")
+	inFields := fs.String("infields", "", "comma separated fields to search in")
+	returnFields := fs.String("return", "", "comma separated fields to return")
+	language := fs.String("language", "", "language of the query, for stemming")
+	expander := fs.String("expander", "", "query expander")
+	scorer := fs.String("scorer", "", "scoring function, e.g. BM25")
+	payload := fs.String("payload", "", "payload passed to the scoring function")
+	sortBy := fs.String("sortby", "", "sortable field to sort the results by")
+	desc := fs.Bool("desc", false, "sort in descending order")
+	highlight := fs.String("highlight", "", "comma separated fields to highlight, * for all")
+	summarize := fs.String("summarize", "", "comma separated fields to summarize, * for all")
+	dialect := fs.Int("dialect", 0, "query dialect, 0 for the server default")
+	params := paramsFlag{}
+	fs.Var(params, "param", "query parameter as name=value, can be repeated")
+
+	return func(raw string) *redisearch.Query {
+		q := redisearch.NewQuery(raw).Limit(*offset, *num).SetDialect(*dialect)
+		var flags redisearch.Flag
+		for flag, set := range map[redisearch.Flag]bool{
+			redisearch.QueryNoContent:     *noContent,
+			redisearch.QueryVerbatim:      *verbatim,
+			redisearch.QueryWithScores:    *withScores,
+			redisearch.QueryWithPayloads:  *withPayloads,
+			redisearch.QueryWithStopWords: *withStopWords,
+			redisearch.QueryInOrder:       *inOrder,
+		} {
+			if set {
+				flags |= flag
+			}
+		}
+		q.SetFlags(flags)
+		if *slop >= 0 {
+			q.Slop = slop
+		}
+		if keys := listFlag(*inKeys); keys != nil {
+			q.SetInKeys(keys...)
+		}
+		if fields := listFlag(*inFields); fields != nil {
+			q.SetInFields(fields...)
+		}
+		if fields := listFlag(*returnFields); fields != nil {
+			q.SetReturnFields(fields...)
+		}
+		q.SetLanguage(*language).SetExpander(*expander).SetScorer(*scorer)
+		if *payload != "" {
+			q.SetPayload([]byte(*payload))
+		}
+		if *sortBy != "" {
+			q.SetSortBy(*sortBy, !*desc)
+		}
+		if *highlight != "" {
+			fields := listFlag(*highlight)
+			if *highlight == "*" {
+				fields = []string{}
+			}
+			q.Highlight(fields, "<b>", "</b>")
+		}
+		if *summarize != "" {
+			fields := listFlag(*summarize)
+			if *summarize == "*" {
+				fields = []string{}
+			}
+			q.Summarize(fields...)
+		}
+		if len(params) > 0 {
+			q.SetParams(params)
+		}
+		return q
+	}
+}
+
+func indexesCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	return func(e *env, args []string) error {
+		infos, err := e.admin.Indexes()
+		if err != nil {
+			if _, ok := err.(redisearch.MultiError); !ok {
+				return err
+			}
+		}
+		if e.opts.json {
+			if jerr := e.out.JSON(infos); jerr != nil {
+				return jerr
+			}
+			return err
+		}
+		rows := make([][]string, 0, len(infos))
+		for _, info := range infos {
+			if info == nil {
+				continue
+			}
+			rows = append(rows, []string{
+				info.Name,
+				strconv.FormatUint(info.DocCount, 10),
+				strconv.FormatUint(info.TermCount, 10),
+				strconv.FormatUint(info.RecordCount, 10),
+				cell(info.TotalIndexMemorySizeMB),
+				indexingState(info),
+			})
+		}
+		if terr := e.out.Table([]string{"NAME", "DOCS", "TERMS", "RECORDS", "MEMORY_MB", "INDEXING"}, rows); terr != nil {
+			return terr
+		}
+		return err
+	}
+}
+
+// indexingState describes the indexing progress of an index
+func indexingState(info *redisearch.IndexInfo) string {
+	if !info.IsIndexing {
+		return "done"
+	}
+	return fmt.Sprintf("%.0f%%", info.PercentIndexed*100)
+}
+
+func infoCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	return func(e *env, args []string) error {
+		info, err := e.admin.Client(args[0]).Info()
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(info)
+		}
+		err = e.out.Fields([][2]string{
+			{"Name", info.Name},
+			{"Documents", strconv.FormatUint(info.DocCount, 10)},
+			{"Terms", strconv.FormatUint(info.TermCount, 10)},
+			{"Records", strconv.FormatUint(info.RecordCount, 10)},
+			{"Max doc id", strconv.FormatUint(info.MaxDocID, 10)},
+			{"Indexing", indexingState(info)},
+			{"Indexing failures", strconv.FormatUint(info.HashIndexingFailures, 10)},
+			{"Inverted index (MB)", cell(info.InvertedIndexSizeMB)},
+			{"Vector index (MB)", cell(info.VectorIndexSizeMB)},
+			{"Total memory (MB)", cell(info.TotalIndexMemorySizeMB)},
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.out.w)
+		rows := make([][]string, 0, len(info.Attributes))
+		for _, attr := range info.Attributes {
+			options := make([]string, 0, len(attr.Options)+len(attr.Flags))
+			for _, name := range sortedOptions(attr.Options) {
+				options = append(options, name+" "+attr.Options[name])
+			}
+			options = append(options, attr.Flags...)
+			rows = append(rows, []string{attr.Identifier, attr.Attribute, attr.Type, strings.Join(options, " ")})
+		}
+		return e.out.Table([]string{"FIELD", "AS", "TYPE", "OPTIONS"}, rows)
+	}
+}
+
+// sortedOptions returns the names of the options of an attribute in sorted order
+func sortedOptions(options map[string]string) []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// searchResult is the JSON output of search and profile
+type searchResult struct {
+	Total     int                   `json:"total"`
+	Documents []redisearch.Document `json:"documents"`
+	Profile   interface{}           `json:"profile,omitempty"`
+}
+
+// printDocuments prints the documents as a table, with a column per property
+func printDocuments(e *env, q *redisearch.Query, docs []redisearch.Document, total int) error {
+	properties := map[string]interface{}{}
+	for _, doc := range docs {
+		for name := range doc.Properties {
+			properties[name] = nil
+		}
+	}
+	names := sortedKeys(properties)
+	header := []string{"ID"}
+	if q.Flags&redisearch.QueryWithScores != 0 {
+		header = append(header, "SCORE")
+	}
+	if q.Flags&redisearch.QueryWithPayloads != 0 {
+		header = append(header, "PAYLOAD")
+	}
+	header = append(header, names...)
+	rows := make([][]string, len(docs))
+	for ii, doc := range docs {
+		row := []string{doc.Id}
+		if q.Flags&redisearch.QueryWithScores != 0 {
+			row = append(row, cell(doc.Score))
+		}
+		if q.Flags&redisearch.QueryWithPayloads != 0 {
+			row = append(row, string(doc.Payload))
+		}
+		for _, name := range names {
+			row = append(row, cell(doc.Properties[name]))
+		}
+		rows[ii] = row
+	}
+	if err := e.out.Table(header, rows); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out.w, "%d of %d results\n", len(docs), total)
+	return nil
+}
+
+func searchCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	query := queryFlags(fs)
+	return func(e *env, args []string) error {
+		q := query(args[1])
+		docs, total, err := e.admin.Client(args[0]).Search(q)
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(searchResult{Total: total, Documents: docs})
+		}
+		return printDocuments(e, q, docs, total)
+	}
+}
+
+func profileCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	query := queryFlags(fs)
+	limited := fs.Bool("limited", false, "do not detail the iterators of the reduced queries")
+	return func(e *env, args []string) error {
+		q := query(args[1])
+		docs, total, profile, err := e.admin.Client(args[0]).Profile(q, *limited)
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(searchResult{Total: total, Documents: docs, Profile: profile})
+		}
+		if err := printDocuments(e, q, docs, total); err != nil {
+			return err
+		}
+		fmt.Fprintln(e.out.w, "\nProfile:")
+		e.out.Tree(profile)
+		return nil
+	}
+}
+
+func explainCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	query := queryFlags(fs)
+	return func(e *env, args []string) error {
+		plan, err := e.admin.Client(args[0]).Explain(query(args[1]))
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(map[string]string{"plan": plan})
+		}
+		fmt.Fprintln(e.out.w, strings.TrimRight(plan, "\n"))
+		return nil
+	}
+}
+
+func aggregateCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	verbatim := fs.Bool("verbatim", false, "do not expand the query terms with their stems")
+	dialect := fs.Int("dialect", 0, "query dialect, 0 for the server default")
+	timeout := fs.Int("query-timeout", 0, "timeout of the query in milliseconds, 0 for the server default")
+	params := paramsFlag{}
+	fs.Var(params, "param", "query parameter as name=value, can be repeated")
+	return func(e *env, args []string) error {
+		q := redisearch.NewQuery(args[1])
+		if *verbatim {
+			q.SetFlags(redisearch.QueryVerbatim)
+		}
+		aq := redisearch.NewAggregateQuery().SetQuery(q).SetDialect(*dialect)
+		if *timeout > 0 {
+			aq.SetTimeout(*timeout)
+		}
+		if len(params) > 0 {
+			aq.SetParams(params)
+		}
+		if err := parsePipeline(aq, args[2]); err != nil {
+			return err
+		}
+		total, rows, err := e.admin.Client(args[0]).AggregateQuery(aq)
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(map[string]interface{}{"total": total, "rows": rows})
+		}
+		columns := map[string]interface{}{}
+		for _, row := range rows {
+			for name := range row {
+				columns[name] = nil
+			}
+		}
+		names := sortedKeys(columns)
+		cells := make([][]string, len(rows))
+		for ii, row := range rows {
+			cells[ii] = make([]string, len(names))
+			for jj, name := range names {
+				cells[ii][jj] = cell(row[name])
+			}
+		}
+		if err := e.out.Table(names, cells); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.out.w, "%d of %d rows\n", len(rows), total)
+		return nil
+	}
+}
+
+func suggestCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	num := fs.Int("num", redisearch.DefaultSuggestOptions.Num, "number of suggestions")
+	fuzzy := fs.Bool("fuzzy", false, "match the prefix with a Levenshtein distance of 1")
+	withScores := fs.Bool("withscores", false, "return the scores of the suggestions")
+	withPayloads := fs.Bool("withpayloads", false, "return the payloads of the suggestions")
+	return func(e *env, args []string) error {
+		a := redisearch.NewAutocompleterFromPool(e.pool, args[0])
+		suggestions, err := a.SuggestOpts(args[1], redisearch.SuggestOptions{
+			Num:          *num,
+			Fuzzy:        *fuzzy,
+			WithScores:   *withScores,
+			WithPayloads: *withPayloads,
+		})
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(suggestions)
+		}
+		rows := make([][]string, len(suggestions))
+		for ii, s := range suggestions {
+			rows[ii] = []string{s.Term, cell(s.Score), s.Payload}
+		}
+		return e.out.Table([]string{"TERM", "SCORE", "PAYLOAD"}, rows)
+	}
+}
+
+func dictCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	return func(e *env, args []string) error {
+		terms, err := e.admin.DictDump(args[0])
+		if err != nil {
+			return err
+		}
+		sort.Strings(terms)
+		if e.opts.json {
+			return e.out.JSON(terms)
+		}
+		rows := make([][]string, len(terms))
+		for ii, term := range terms {
+			rows[ii] = []string{term}
+		}
+		return e.out.Table([]string{"TERM"}, rows)
+	}
+}
+
+func synonymsCommand(fs *flag.FlagSet) func(e *env, args []string) error {
+	return func(e *env, args []string) error {
+		groups, err := e.admin.SynDump(args[0])
+		if err != nil {
+			return err
+		}
+		if e.opts.json {
+			return e.out.JSON(groups)
+		}
+		terms := make([]string, 0, len(groups))
+		for term := range groups {
+			terms = append(terms, term)
+		}
+		sort.Strings(terms)
+		rows := make([][]string, len(terms))
+		for ii, term := range terms {
+			ids := make([]string, len(groups[term]))
+			for jj, id := range groups[term] {
+				ids[jj] = strconv.FormatInt(id, 10)
+			}
+			rows[ii] = []string{term, strings.Join(ids, ", ")}
+		}
+		return e.out.Table([]string{"TERM", "GROUPS"}, rows)
+	}
+}
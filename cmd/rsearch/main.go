@@ -0,0 +1,178 @@
+// Command rsearch inspects RediSearch indexes and runs ad-hoc queries, printing the replies as tables or JSON.
+//
+// Usage:
+//
+//	rsearch [-addr host:port] [-password pwd] [-json] <command> [flags] [args]
+//
+// The commands are:
+//
+//	indexes                          list the indexes with their size
+//	info <index>                     show the statistics and the schema of an index
+//	search <index> <query>           search an index, see rsearch search -h for the flags
+//	aggregate <index> <query> <pipe> run an aggregation described by a pipeline, e.g.
+//	                                 'groupby @brand count() as n | sortby @n desc | limit 0 5'
+//	explain <index> <query>          show the execution plan of a query
+//	profile <index> <query>          run a query with FT.PROFILE and show its profile
+//	suggest <key> <prefix>           get the suggestions of an autocomplete dictionary
+//	dict <name>                      dump the terms of a spelling dictionary
+//	synonyms <index>                 dump the synonym groups of an index
+//
+// The address defaults to $REDISEARCH_ADDR, or localhost:6379. The flags can also be given after the arguments.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/gomodule/redigo/redis"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// options are the flags common to all the commands
+type options struct {
+	addr     string
+	password string
+	timeout  time.Duration
+	json     bool
+}
+
+// register declares the common flags on a flag set
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.addr, "addr", o.addr, "host:port of the redis server")
+	fs.StringVar(&o.password, "password", o.password, "password of the redis server")
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "timeout of the connection and of each command")
+	fs.BoolVar(&o.json, "json", o.json, "print the replies as JSON")
+}
+
+// pool creates the connection pool shared by the clients of a command
+func (o *options) pool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle: 1,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", o.addr,
+				redis.DialPassword(o.password),
+				redis.DialConnectTimeout(o.timeout),
+				redis.DialReadTimeout(o.timeout),
+				redis.DialWriteTimeout(o.timeout))
+		},
+	}
+}
+
+// env is the environment of a command
+type env struct {
+	opts  *options
+	pool  *redis.Pool
+	admin *redisearch.Admin
+	out   *output
+}
+
+// command is a subcommand: its flags are declared by flags, and run is called with the positional arguments
+type command struct {
+	usage string
+	nargs int
+	flags func(fs *flag.FlagSet) func(e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"indexes":   {"indexes", 0, indexesCommand},
+	"info":      {"info <index>", 1, infoCommand},
+	"search":    {"search <index> <query>", 2, searchCommand},
+	"aggregate": {"aggregate <index> <query> <pipeline>", 3, aggregateCommand},
+	"explain":   {"explain <index> <query>", 2, explainCommand},
+	"profile":   {"profile <index> <query>", 2, profileCommand},
+	"suggest":   {"suggest <key> <prefix>", 2, suggestCommand},
+	"dict":      {"dict <name>", 1, dictCommand},
+	"synonyms":  {"synonyms <index>", 1, synonymsCommand},
+}
+
+var errUsage = errors.New("usage")
+
+// run runs the command line and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	addr := os.Getenv("REDISEARCH_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	opts := &options{addr: addr, timeout: 5 * time.Second}
+
+	fs := flag.NewFlagSet("rsearch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: rsearch [-addr host:port] [-password pwd] [-json] <command> [flags] [args]")
+		fmt.Fprintln(stderr, "commands: indexes, info, search, aggregate, explain, profile, suggest, dict, synonyms")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "rsearch: unknown command %q\n", name)
+		fs.Usage()
+		return 2
+	}
+
+	cfs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfs.SetOutput(stderr)
+	opts.register(cfs)
+	exec := cmd.flags(cfs)
+	cfs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: rsearch %s [flags]\n", cmd.usage)
+		cfs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(cfs, fs.Args()[1:])
+	if err != nil {
+		return 2
+	}
+	if len(positional) != cmd.nargs {
+		cfs.Usage()
+		return 2
+	}
+
+	pool := opts.pool()
+	defer pool.Close()
+	e := &env{opts: opts, pool: pool, admin: redisearch.NewAdminFromPool(pool), out: newOutput(stdout, opts.json)}
+	if err := exec(e, positional); err != nil {
+		if err == errUsage {
+			cfs.Usage()
+			return 2
+		}
+		fmt.Fprintf(stderr, "rsearch %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses the flags found anywhere in args, and returns the positional arguments.
+// The arguments following "--" are all positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if len(args) > len(rest) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
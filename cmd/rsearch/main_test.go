@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer starts a server with a products index
+func newServer(t *testing.T) *redisearchtest.Server {
+	srv := redisearchtest.NewServer()
+	t.Cleanup(srv.Close)
+	c := redisearch.NewClient(srv.Addr, "products")
+	sc := redisearch.NewSchema(redisearch.DefaultOptions).
+		AddField(redisearch.NewTextFieldOptions("title", redisearch.TextFieldOptions{Sortable: true})).
+		AddField(redisearch.NewTagField("brand")).
+		AddField(redisearch.NewNumericField("price"))
+	assert.Nil(t, c.CreateIndex(sc))
+	assert.Nil(t, c.Index(
+		redisearch.NewDocument("p1", 1).Set("title", "red shoes").Set("brand", "acme").Set("price", 50),
+		redisearch.NewDocument("p2", 1).Set("title", "blue shoes").Set("brand", "globex").Set("price", 80),
+		redisearch.NewDocument("p3", 1).Set("title", "red hat").Set("brand", "acme").Set("price", 20),
+	))
+	a := redisearch.NewAutocompleter(srv.Addr, "titles")
+	assert.Nil(t, a.AddTerms(redisearch.Suggestion{Term: "red shoes", Score: 2}, redisearch.Suggestion{Term: "red hat", Score: 1}))
+	return srv
+}
+
+// rsearch runs a command line against the server, and returns the exit code and the outputs
+func rsearch(srv *redisearchtest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-addr", srv.Addr}, args...), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_usage(t *testing.T) {
+	srv := newServer(t)
+	code, _, stderr := rsearch(srv)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: rsearch")
+
+	code, _, stderr = rsearch(srv, "drop", "products")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "drop"`)
+
+	code, _, stderr = rsearch(srv, "search", "products")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: rsearch search <index> <query> [flags]")
+
+	code, _, stderr = rsearch(srv, "info", "missing")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "rsearch info: ")
+}
+
+func TestRun_indexesAndInfo(t *testing.T) {
+	srv := newServer(t)
+	code, stdout, _ := rsearch(srv, "indexes")
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, []string{"NAME", "DOCS", "TERMS", "RECORDS", "MEMORY_MB", "INDEXING"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"products", "3"}, strings.Fields(lines[1])[:2])
+
+	code, stdout, _ = rsearch(srv, "info", "products")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Documents:")
+	assert.Regexp(t, `title +title +TEXT +.*SORTABLE`, stdout)
+	assert.Regexp(t, `brand +brand +TAG`, stdout)
+
+	code, stdout, _ = rsearch(srv, "info", "products", "-json")
+	assert.Equal(t, 0, code)
+	var info redisearch.IndexInfo
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &info))
+	assert.Equal(t, "products", info.Name)
+	assert.Equal(t, uint64(3), info.DocCount)
+}
+
+func TestRun_search(t *testing.T) {
+	srv := newServer(t)
+	code, stdout, stderr := rsearch(srv, "search", "products", "red", "-sortby", "price", "-desc", "-return", "title,price")
+	assert.Equal(t, 0, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Equal(t, []string{
+		"ID  price  title",
+		"p1  50     red shoes",
+		"p3  20     red hat",
+		"2 of 2 results",
+	}, lines)
+
+	code, stdout, stderr = rsearch(srv, "-json", "search", "products", "@brand:{$brand}", "-param", "brand=globex", "-dialect", "2")
+	assert.Equal(t, 0, code, stderr)
+	var res struct {
+		Total     int
+		Documents []redisearch.Document
+	}
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "p2", res.Documents[0].Id)
+
+	code, stdout, _ = rsearch(srv, "search", "-nocontent", "-num", "1", "--", "products", "-blue")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "ID\np1\n1 of 2 results\n", stdout)
+}
+
+func TestRun_suggest(t *testing.T) {
+	srv := newServer(t)
+	code, stdout, stderr := rsearch(srv, "suggest", "titles", "red", "-withscores")
+	assert.Equal(t, 0, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"red", "shoes"}, strings.Fields(lines[1])[:2])
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxCellWidth is the number of runes after which the cells of the tables are truncated
+const maxCellWidth = 60
+
+// output prints the replies as tables or as JSON
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+func newOutput(w io.Writer, json bool) *output {
+	return &output{w: w, json: json}
+}
+
+// JSON prints a value as indented JSON
+func (o *output) JSON(v interface{}) error {
+	enc := json.NewEncoder(o.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Table prints rows aligned under the header
+func (o *output) Table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for ii, cell := range row {
+			cells[ii] = truncate(cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// Fields prints name: value lines, with the values aligned
+func (o *output) Fields(fields [][2]string) error {
+	tw := tabwriter.NewWriter(o.w, 0, 0, 1, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", field[0], field[1])
+	}
+	return tw.Flush()
+}
+
+// Tree prints a nested reply, e.g. a profile, with the key/value pairs of the lists on one line when possible
+func (o *output) Tree(v interface{}) {
+	o.tree(v, "")
+}
+
+func (o *output) tree(v interface{}, indent string) {
+	list, ok := v.([]interface{})
+	if !ok {
+		if m, isMap := v.(map[string]interface{}); isMap {
+			for _, key := range sortedKeys(m) {
+				o.entry(key, m[key], indent)
+			}
+			return
+		}
+		fmt.Fprintf(o.w, "%s%s\n", indent, cell(v))
+		return
+	}
+	// a list of key/value pairs, the keys being strings
+	if len(list)%2 == 0 && len(list) > 0 {
+		pairs := true
+		for ii := 0; ii < len(list); ii += 2 {
+			if _, isString := list[ii].(string); !isString {
+				pairs = false
+				break
+			}
+		}
+		if pairs {
+			for ii := 0; ii < len(list); ii += 2 {
+				o.entry(list[ii].(string), list[ii+1], indent)
+			}
+			return
+		}
+	}
+	for _, elem := range list {
+		if _, nested := elem.([]interface{}); nested {
+			fmt.Fprintf(o.w, "%s-\n", indent)
+			o.tree(elem, indent+"  ")
+		} else {
+			o.tree(elem, indent)
+		}
+	}
+}
+
+func (o *output) entry(key string, value interface{}, indent string) {
+	switch value.(type) {
+	case []interface{}, map[string]interface{}:
+		fmt.Fprintf(o.w, "%s%s:\n", indent, key)
+		o.tree(value, indent+"  ")
+	default:
+		fmt.Fprintf(o.w, "%s%s: %s\n", indent, key, cell(value))
+	}
+}
+
+// cell formats a value of a reply or of a document
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case []string:
+		return strings.Join(v, ", ")
+	case float32, float64:
+		return fmt.Sprintf("%g", v)
+	}
+	return fmt.Sprint(v)
+}
+
+// truncate truncates a cell to maxCellWidth runes, and replaces its line breaks and tabs
+func truncate(s string) string {
+	s = strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(s)
+	runes := []rune(s)
+	if len(runes) > maxCellWidth {
+		return string(runes[:maxCellWidth-1]) + "…"
+	}
+	return s
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// parsePipeline adds the steps of a pipeline to an aggregation. The steps are separated by |, e.g.
+//
+//	load @title | groupby @brand @year count() as n, avg(@price) as price | apply @price * 2 as double
+//	| filter @n > 1 | sortby @n desc @brand | limit 0 10
+//
+// The reducers of groupby are any FT.AGGREGATE reducer, with its arguments between the parentheses.
+func parsePipeline(q *redisearch.AggregateQuery, pipeline string) error {
+	for ii, step := range splitOutside(pipeline, '|') {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			return fmt.Errorf("pipeline step %d: empty step", ii+1)
+		}
+		keyword, rest := step, ""
+		if pos := strings.IndexAny(step, " \t"); pos >= 0 {
+			keyword, rest = step[:pos], strings.TrimSpace(step[pos+1:])
+		}
+		var err error
+		switch strings.ToLower(keyword) {
+		case "load":
+			err = parseLoad(q, rest)
+		case "groupby":
+			err = parseGroupBy(q, rest)
+		case "apply":
+			err = parseApply(q, rest)
+		case "filter":
+			if rest == "" {
+				err = fmt.Errorf("missing expression")
+			} else {
+				q.Filter(rest)
+			}
+		case "sortby":
+			err = parseSortBy(q, rest)
+		case "limit":
+			err = parseLimit(q, rest)
+		default:
+			err = fmt.Errorf("unknown step %q, expected load, groupby, apply, filter, sortby or limit", keyword)
+		}
+		if err != nil {
+			return fmt.Errorf("pipeline step %d (%s): %v", ii+1, step, err)
+		}
+	}
+	return nil
+}
+
+// splitOutside splits s on sep, except within quotes and parentheses, and where sep is doubled (e.g. ||)
+func splitOutside(s string, sep byte) []string {
+	parts := make([]string, 0)
+	depth := 0
+	var quote byte
+	start := 0
+	for ii := 0; ii < len(s); ii++ {
+		c := s[ii]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				ii++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			if ii+1 < len(s) && s[ii+1] == sep {
+				ii++
+				continue
+			}
+			parts = append(parts, s[start:ii])
+			start = ii + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseLoad(q *redisearch.AggregateQuery, rest string) error {
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return fmt.Errorf("missing fields, use load * to load all the fields")
+	}
+	if len(fields) == 1 && fields[0] == "*" {
+		q.LoadFields(nil)
+		return nil
+	}
+	loads := make([]redisearch.LoadField, len(fields))
+	for ii, field := range fields {
+		loads[ii] = redisearch.LoadField{Name: field}
+	}
+	q.LoadFields(loads)
+	return nil
+}
+
+func parseGroupBy(q *redisearch.AggregateQuery, rest string) error {
+	words := strings.Fields(rest)
+	fields := make([]string, 0)
+	for len(words) > 0 && strings.HasPrefix(words[0], "@") {
+		fields = append(fields, strings.TrimSuffix(words[0], ","))
+		words = words[1:]
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("missing @fields to group by")
+	}
+	group := redisearch.NewGroupBy().AddFields(fields)
+	reducers := strings.TrimSpace(strings.Join(words, " "))
+	if reducers == "" {
+		q.GroupBy(*group)
+		return nil
+	}
+	for _, text := range splitOutside(reducers, ',') {
+		reducer, err := parseReducer(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		group.Reduce(*reducer)
+	}
+	q.GroupBy(*group)
+	return nil
+}
+
+// parseReducer parses name(args) [as alias]
+func parseReducer(text string) (*redisearch.Reducer, error) {
+	open := strings.IndexByte(text, '(')
+	end := strings.LastIndexByte(text, ')')
+	if open <= 0 || end < open {
+		return nil, fmt.Errorf("invalid reducer %q, expected name(args) [as alias]", text)
+	}
+	name := strings.ToUpper(strings.TrimSpace(text[:open]))
+	args := make([]string, 0)
+	for _, arg := range splitOutside(text[open+1:end], ',') {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			args = append(args, arg)
+		}
+	}
+	alias := ""
+	if tail := strings.Fields(text[end+1:]); len(tail) > 0 {
+		if len(tail) != 2 || !strings.EqualFold(tail[0], "as") {
+			return nil, fmt.Errorf("invalid reducer %q, expected name(args) [as alias]", text)
+		}
+		alias = tail[1]
+	}
+	return redisearch.NewReducerAlias(redisearch.GroupByReducers(name), args, alias), nil
+}
+
+func parseApply(q *redisearch.AggregateQuery, rest string) error {
+	lower := strings.ToLower(rest)
+	pos := strings.LastIndex(lower, " as ")
+	if pos <= 0 {
+		return fmt.Errorf("expected apply <expression> as <alias>")
+	}
+	alias := strings.TrimSpace(rest[pos+4:])
+	if alias == "" || strings.ContainsAny(alias, " \t") {
+		return fmt.Errorf("invalid alias %q", alias)
+	}
+	q.Apply(*redisearch.NewProjection(strings.TrimSpace(rest[:pos]), alias))
+	return nil
+}
+
+func parseSortBy(q *redisearch.AggregateQuery, rest string) error {
+	keys := make([]redisearch.SortingKey, 0)
+	for _, word := range strings.Fields(rest) {
+		switch strings.ToLower(word) {
+		case "asc", "desc":
+			if len(keys) == 0 {
+				return fmt.Errorf("%s must follow a @field", word)
+			}
+			keys[len(keys)-1].Ascending = strings.EqualFold(word, "asc")
+		default:
+			keys = append(keys, redisearch.By(word, redisearch.Asc))
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("missing @fields to sort by")
+	}
+	q.SortBy(keys)
+	return nil
+}
+
+func parseLimit(q *redisearch.AggregateQuery, rest string) error {
+	words := strings.Fields(rest)
+	if len(words) != 2 {
+		return fmt.Errorf("expected limit <offset> <num>")
+	}
+	offset, err := strconv.Atoi(words[0])
+	if err != nil {
+		return fmt.Errorf("invalid offset %q", words[0])
+	}
+	num, err := strconv.Atoi(words[1])
+	if err != nil {
+		return fmt.Errorf("invalid num %q", words[1])
+	}
+	q.Limit(offset, num)
+	return nil
+}
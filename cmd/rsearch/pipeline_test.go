@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePipeline(t *testing.T) {
+	q := redisearch.NewAggregateQuery().SetQuery(redisearch.NewQuery("*"))
+	err := parsePipeline(q, `load @title | groupby @brand @year count() as n, quantile(@price, 0.5) as median, tolist(@title) `+
+		`| apply upper(@brand) as BRAND | filter @n > 1 || @brand == "a|b" | sortby @n desc @brand | limit 0 10`)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"*",
+		"LOAD", 1, "@title",
+		"GROUPBY", 2, "@brand", "@year",
+		"REDUCE", "COUNT", 0, "AS", "n",
+		"REDUCE", "QUANTILE", 2, "@price", "0.5", "AS", "median",
+		"REDUCE", "TOLIST", 1, "@title",
+		"APPLY", "upper(@brand)", "AS", "BRAND",
+		"FILTER", `@n > 1 || @brand == "a|b"`,
+		"SORTBY", 4, "@n", "DESC", "@brand", "ASC",
+		"LIMIT", 0, 10,
+	}, q.Serialize())
+
+	q = redisearch.NewAggregateQuery()
+	assert.Nil(t, parsePipeline(q, "load *|groupby @brand"))
+	assert.Equal(t, []interface{}{"*", "LOAD", "*", "GROUPBY", 1, "@brand"}, q.Serialize())
+}
+
+func TestParsePipeline_errors(t *testing.T) {
+	tests := []struct {
+		pipeline string
+		err      string
+	}{
+		{"groupby @a |", "pipeline step 2: empty step"},
+		{"select @a", `pipeline step 1 (select @a): unknown step "select", expected load, groupby, apply, filter, sortby or limit`},
+		{"groupby count()", "pipeline step 1 (groupby count()): missing @fields to group by"},
+		{"groupby @a count", `pipeline step 1 (groupby @a count): invalid reducer "count", expected name(args) [as alias]`},
+		{"groupby @a count() n", `pipeline step 1 (groupby @a count() n): invalid reducer "count() n", expected name(args) [as alias]`},
+		{"apply @a * 2", "pipeline step 1 (apply @a * 2): expected apply <expression> as <alias>"},
+		{"sortby desc", "pipeline step 1 (sortby desc): desc must follow a @field"},
+		{"limit 10", "pipeline step 1 (limit 10): expected limit <offset> <num>"},
+		{"filter", "pipeline step 1 (filter): missing expression"},
+	}
+	for _, tt := range tests {
+		err := parsePipeline(redisearch.NewAggregateQuery(), tt.pipeline)
+		assert.EqualError(t, err, tt.err, tt.pipeline)
+	}
+}
@@ -0,0 +1,218 @@
+package redisearch
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Admin is an interface to the server-level redisearch commands, that are not bound to a single index:
+// index listing, aliases, runtime configuration and dictionaries.
+// It shares its ConnPool with the index-scoped Clients it creates.
+type Admin struct {
+	pool ConnPool
+}
+
+// NewAdmin creates a new admin client connecting to the redis host.
+// Addr can be a single host:port pair, or a comma separated list of host:port,host:port...
+// In the case of multiple hosts we create a multi-pool and select connections at random
+func NewAdmin(addr string) *Admin {
+	addrs := strings.Split(addr, ",")
+	var pool ConnPool
+	if len(addrs) == 1 {
+		pool = NewSingleHostPool(addrs[0])
+	} else {
+		pool = NewMultiHostPool(addrs)
+	}
+	return &Admin{pool: pool}
+}
+
+// NewAdminFromPool creates a new admin client with the given pool
+func NewAdminFromPool(pool ConnPool) *Admin {
+	return &Admin{pool: pool}
+}
+
+// Client returns a Client bound to the given index (or alias) name, sharing the admin pool
+func (a *Admin) Client(name string) *Client {
+	return &Client{pool: a.pool, name: name}
+}
+
+// Close closes the pool shared by the admin and its clients
+func (a *Admin) Close() error {
+	return a.pool.Close()
+}
+
+// ListIndexes returns the names of all the existing indexes, sorted by name
+func (a *Admin) ListIndexes() ([]string, error) {
+	indexes, err := a.Client("").List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(indexes)
+	return indexes, nil
+}
+
+// Indexes returns the Info of all the existing indexes, sorted by index name. The FT.INFO commands are sent
+// in a single pipeline. If some of them failed (e.g. the index was dropped in the meantime), the matching
+// entries are nil and the error is a MultiError aligned with the returned slice.
+func (a *Admin) Indexes() ([]*IndexInfo, error) {
+	indexes, err := a.ListIndexes()
+	if err != nil {
+		return nil, err
+	}
+	return a.infos(indexes)
+}
+
+// ResolveAliases returns the index pointed by each of the given names that is an alias.
+// It does not list the aliases: RediSearch has no command enumerating them, and FT.INFO of an index
+// does not report its aliases, so the candidate names must be provided by the caller.
+// Names that are indexes or that do not exist are left out of the result.
+func (a *Admin) ResolveAliases(names ...string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	infos, err := a.infos(names)
+	if _, ok := err.(MultiError); err != nil && !ok {
+		return nil, err
+	}
+	for ii, info := range infos {
+		if info != nil && info.Name != names[ii] {
+			aliases[names[ii]] = info.Name
+		}
+	}
+	return aliases, nil
+}
+
+// internal method
+// infos pipelines a FT.INFO command per index name
+func (a *Admin) infos(names []string) ([]*IndexInfo, error) {
+	infos := make([]*IndexInfo, len(names))
+	if len(names) == 0 {
+		return infos, nil
+	}
+	conn := a.pool.Get()
+	defer conn.Close()
+
+	for _, name := range names {
+		if err := conn.Send("FT.INFO", name); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	var merr MultiError
+	for ii := range names {
+		res, err := replyValues(conn.Receive())
+		if err == nil {
+			infos[ii], err = loadIndexInfo(res)
+		}
+		if err != nil {
+			if merr == nil {
+				merr = NewMultiError(len(names))
+			}
+			merr[ii] = err
+		}
+	}
+	if merr != nil {
+		return infos, merr
+	}
+	return infos, nil
+}
+
+// AliasAdd adds an alias to an index
+func (a *Admin) AliasAdd(alias, index string) error {
+	return a.Client(index).AliasAdd(alias)
+}
+
+// AliasUpdate points an alias to an index, removing its association with a previous index if any
+func (a *Admin) AliasUpdate(alias, index string) error {
+	return a.Client(index).AliasUpdate(alias)
+}
+
+// AliasDel deletes an alias
+func (a *Admin) AliasDel(alias string) error {
+	return a.Client("").AliasDel(alias)
+}
+
+// DropIndexes drops all the indexes whose name matches the pattern, and optionally their documents.
+// The pattern syntax is the one of path.Match, e.g. "tmp-*". It returns the matching index names;
+// if some of the drops failed, the error is a MultiError aligned with the returned names.
+func (a *Admin) DropIndexes(pattern string, deleteDocuments bool) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	indexes, err := a.ListIndexes()
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]string, 0)
+	for _, index := range indexes {
+		if ok, _ := path.Match(pattern, index); ok {
+			matching = append(matching, index)
+		}
+	}
+
+	var merr MultiError
+	for ii, index := range matching {
+		if err := a.Client(index).DropIndex(deleteDocuments); err != nil {
+			if merr == nil {
+				merr = NewMultiError(len(matching))
+			}
+			merr[ii] = err
+		}
+	}
+	if merr != nil {
+		return matching, merr
+	}
+	return matching, nil
+}
+
+// SetConfig sets a runtime configuration option
+func (a *Admin) SetConfig(option string, value string) (string, error) {
+	return a.Client("").SetConfig(option, value)
+}
+
+// GetConfig returns the runtime configuration options matching the given option, or "*" for all of them
+func (a *Admin) GetConfig(option string) (map[string]string, error) {
+	return a.Client("").GetConfig(option)
+}
+
+// GetSearchConfig returns the full typed runtime configuration
+func (a *Admin) GetSearchConfig() (*SearchConfig, error) {
+	return a.Client("").GetSearchConfig()
+}
+
+// ApplySearchConfig sets all the options of desired that differ from the current configuration.
+// See Client.ApplySearchConfig
+func (a *Admin) ApplySearchConfig(desired *SearchConfig) ([]ConfigChange, error) {
+	return a.Client("").ApplySearchConfig(desired)
+}
+
+// DictAdd adds terms to a dictionary
+func (a *Admin) DictAdd(dictionaryName string, terms []string) (int, error) {
+	return a.Client("").DictAdd(dictionaryName, terms)
+}
+
+// DictDel deletes terms from a dictionary
+func (a *Admin) DictDel(dictionaryName string, terms []string) (int, error) {
+	return a.Client("").DictDel(dictionaryName, terms)
+}
+
+// DictDump dumps all terms in the given dictionary
+func (a *Admin) DictDump(dictionaryName string) ([]string, error) {
+	return a.Client("").DictDump(dictionaryName)
+}
+
+// TagVals returns the distinct tags indexed in a tag field of the given index
+func (a *Admin) TagVals(index string, fieldName string) ([]string, error) {
+	return a.Client(index).GetTagVals(index, fieldName)
+}
+
+// SynUpdate updates a synonym group of the given index, with additional terms
+func (a *Admin) SynUpdate(index string, synonymGroupId int64, terms []string) (string, error) {
+	return a.Client(index).SynUpdate(index, synonymGroupId, terms)
+}
+
+// SynDump dumps the synonym groups of the given index
+func (a *Admin) SynDump(index string) (map[string][]int64, error) {
+	return a.Client(index).SynDump(index)
+}
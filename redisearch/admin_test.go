@@ -0,0 +1,60 @@
+package redisearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createAdmin() *Admin {
+	return NewAdminFromPool(createClient("").pool)
+}
+
+func TestAdmin_Indexes(t *testing.T) {
+	a := createAdmin()
+	flush(a.Client(""))
+	for _, name := range []string{"admin-b", "admin-a", "other"} {
+		assert.Nil(t, a.Client(name).CreateIndex(NewSchema(DefaultOptions).AddField(NewTextField("foo"))))
+	}
+	assert.Nil(t, a.AliasAdd("admin-alias", "admin-b"))
+
+	indexes, err := a.ListIndexes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin-a", "admin-b", "other"}, indexes)
+
+	infos, err := a.Indexes()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(infos))
+	assert.Equal(t, "admin-a", infos[0].Name)
+	assert.Equal(t, 1, len(infos[0].Schema.Fields))
+
+	aliases, err := a.ResolveAliases("admin-alias", "admin-a", "missing")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"admin-alias": "admin-b"}, aliases)
+
+	dropped, err := a.DropIndexes("admin-*", false)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin-a", "admin-b"}, dropped)
+	indexes, err = a.ListIndexes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"other"}, indexes)
+
+	_, err = a.DropIndexes("[", false)
+	assert.NotNil(t, err)
+	teardown(a.Client(""))
+}
+
+func TestAdmin_Dict(t *testing.T) {
+	a := createAdmin()
+	flush(a.Client(""))
+	n, err := a.DictAdd("admin-dict", []string{"foo", "bar"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	terms, err := a.DictDump("admin-dict")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"foo", "bar"}, terms)
+	n, err = a.DictDel("admin-dict", []string{"foo"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	teardown(a.Client(""))
+}
@@ -2,9 +2,11 @@ package redisearch
 
 import (
 	"fmt"
-	"github.com/gomodule/redigo/redis"
 	"log"
 	"reflect"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
 )
 
 // Projection - Apply a 1-to-1 transformation on one or more properties,
@@ -25,7 +27,7 @@ func NewProjection(expression string, alias string) *Projection {
 	}
 }
 
-func (p Projection) Serialize() redis.Args {
+func (p Projection) Serialize() []interface{} {
 	args := redis.Args{"APPLY", p.Expression, "AS", p.Alias}
 	return args
 }
@@ -60,7 +62,7 @@ func (c *Cursor) SetMaxIdle(maxIdle int) *Cursor {
 	return c
 }
 
-func (c Cursor) Serialize() redis.Args {
+func (c Cursor) Serialize() []interface{} {
 	args := redis.Args{"WITHCURSOR"}
 	if c.Count > 0 {
 		args = args.Add("COUNT", c.Count)
@@ -71,9 +73,9 @@ func (c Cursor) Serialize() redis.Args {
 	return args
 }
 
-//GroupBy groups the results in the pipeline based on one or more properties.
-//Each group should have at least one reducer, a function that handles the group
-//entries, either counting them, or performing multiple aggregate operations.
+// GroupBy groups the results in the pipeline based on one or more properties.
+// Each group should have at least one reducer, a function that handles the group
+// entries, either counting them, or performing multiple aggregate operations.
 type GroupBy struct {
 	Fields   []string
 	Reducers []Reducer
@@ -114,7 +116,7 @@ func (g *GroupBy) Limit(offset int, num int) *GroupBy {
 	return g
 }
 
-func (g GroupBy) Serialize() redis.Args {
+func (g GroupBy) Serialize() []interface{} {
 	ret := len(g.Fields)
 	args := redis.Args{"GROUPBY", ret}.AddFlat(g.Fields)
 	for _, reducer := range g.Reducers {
@@ -129,15 +131,27 @@ func (g GroupBy) Serialize() redis.Args {
 // AggregateQuery
 type AggregateQuery struct {
 	Query         *Query
-	AggregatePlan redis.Args
+	AggregatePlan []interface{}
 	Paging        *Paging
 	Max           int
 	WithSchema    bool
 	Verbatim      bool
 	WithCursor    bool
 	Cursor        *Cursor
-	// TODO: add load fields
+	Params        map[string]interface{}
+	Dialect       int
+	Timeout       int
+	Scorer        string
+	AddScores     bool
 
+	// position in AggregatePlan right after the last SORTBY clause, where MAX is placed
+	sortByEnd int
+}
+
+// LoadField is a document field loaded by the aggregation, optionally renamed with an alias
+type LoadField struct {
+	Name string
+	As   string
 }
 
 func NewAggregateQuery() *AggregateQuery {
@@ -175,6 +189,45 @@ func (a *AggregateQuery) SetMax(value int) *AggregateQuery {
 	return a
 }
 
+// SetParams sets parameters that can be referenced in the query string by a $ , followed by the parameter name
+func (a *AggregateQuery) SetParams(params map[string]interface{}) *AggregateQuery {
+	a.Params = params
+	return a
+}
+
+// AddParam adds a new param to the parameters list
+func (a *AggregateQuery) AddParam(name string, value interface{}) *AggregateQuery {
+	if a.Params == nil {
+		a.Params = make(map[string]interface{})
+	}
+	a.Params[name] = value
+	return a
+}
+
+// SetDialect selects the dialect version under which to execute the query
+func (a *AggregateQuery) SetDialect(dialect int) *AggregateQuery {
+	a.Dialect = dialect
+	return a
+}
+
+// SetTimeout overrides the server's timeout for this aggregation, in milliseconds
+func (a *AggregateQuery) SetTimeout(timeout int) *AggregateQuery {
+	a.Timeout = timeout
+	return a
+}
+
+// SetScorer sets the scoring function used when ADDSCORES is set
+func (a *AggregateQuery) SetScorer(scorer string) *AggregateQuery {
+	a.Scorer = scorer
+	return a
+}
+
+// SetAddScores exposes the full-text score of each document as the @__score property
+func (a *AggregateQuery) SetAddScores(value bool) *AggregateQuery {
+	a.AddScores = value
+	return a
+}
+
 func (a *AggregateQuery) SetCursor(cursor *Cursor) *AggregateQuery {
 	a.WithCursor = true
 	a.Cursor = cursor
@@ -189,67 +242,85 @@ func (a *AggregateQuery) CursorHasResults() (res bool) {
 	return
 }
 
-//Apply a 1-to-1 transformation on some property
+// Apply a 1-to-1 transformation on some property
 func (a *AggregateQuery) Apply(expression Projection) *AggregateQuery {
-	a.AggregatePlan = a.AggregatePlan.AddFlat(expression.Serialize())
+	a.AggregatePlan = redis.Args(a.AggregatePlan).AddFlat(expression.Serialize())
 	return a
 }
 
-//Limit the number of results to return just num results starting at index offset (zero-based).
+// Limit the number of results to return just num results starting at index offset (zero-based).
 func (a *AggregateQuery) Limit(offset int, num int) *AggregateQuery {
 	a.Paging = NewPaging(offset, num)
 	return a
 }
 
-//Load document fields from the document HASH objects (if they are not in the sortables).
-//Empty array will load all properties.
+// Load document fields from the document HASH objects (if they are not in the sortables).
+// Empty array will load all properties.
 func (a *AggregateQuery) Load(Properties []string) *AggregateQuery {
 	nproperties := len(Properties)
 	if nproperties == 0 {
-		a.AggregatePlan = a.AggregatePlan.Add("LOAD", "*")
+		a.AggregatePlan = redis.Args(a.AggregatePlan).Add("LOAD", "*")
 	}
 	if nproperties > 0 {
-		a.AggregatePlan = a.AggregatePlan.Add("LOAD", nproperties)
+		a.AggregatePlan = redis.Args(a.AggregatePlan).Add("LOAD", nproperties)
 		for _, property := range Properties {
-			a.AggregatePlan = a.AggregatePlan.Add(fmt.Sprintf("@%s", property))
+			a.AggregatePlan = redis.Args(a.AggregatePlan).Add(fmt.Sprintf("@%s", property))
 		}
 	}
 	return a
 }
 
-//Adds a GROUPBY clause to the aggregate plan
+// LoadFields loads document fields from the document HASH objects, renaming the ones that have an alias.
+func (a *AggregateQuery) LoadFields(fields []LoadField) *AggregateQuery {
+	if len(fields) == 0 {
+		return a.Load(nil)
+	}
+	load := redis.Args{}
+	for _, field := range fields {
+		load = load.Add(toProperty(field.Name))
+		if field.As != "" {
+			load = load.Add("AS", field.As)
+		}
+	}
+	a.AggregatePlan = redis.Args(a.AggregatePlan).Add("LOAD", len(load)).AddFlat(load)
+	return a
+}
+
+// Adds a GROUPBY clause to the aggregate plan
 func (a *AggregateQuery) GroupBy(group GroupBy) *AggregateQuery {
-	a.AggregatePlan = a.AggregatePlan.AddFlat(group.Serialize())
+	a.AggregatePlan = redis.Args(a.AggregatePlan).AddFlat(group.Serialize())
 	return a
 }
 
-//Adds a SORTBY clause to the aggregate plan
+// Adds a SORTBY clause to the aggregate plan
 func (a *AggregateQuery) SortBy(SortByProperties []SortingKey) *AggregateQuery {
 	nsort := len(SortByProperties)
 	if nsort > 0 {
-		a.AggregatePlan = a.AggregatePlan.Add("SORTBY", nsort*2)
+		a.AggregatePlan = redis.Args(a.AggregatePlan).Add("SORTBY", nsort*2)
 		for _, sortby := range SortByProperties {
-			a.AggregatePlan = a.AggregatePlan.AddFlat(sortby.Serialize())
-		}
-		if a.Max > 0 {
-			a.AggregatePlan = a.AggregatePlan.Add("MAX", a.Max)
+			a.AggregatePlan = redis.Args(a.AggregatePlan).AddFlat(sortby.Serialize())
 		}
+		// MAX is added on serialization, so that SetMax can be called before or after SortBy
+		a.sortByEnd = len(a.AggregatePlan)
 	}
 	return a
 }
 
-//Filter the results using predicate expressions relating to values in each result.
-//They are is applied post-query and relate to the current state of the pipeline.
+// Filter the results using predicate expressions relating to values in each result.
+// They are is applied post-query and relate to the current state of the pipeline.
 func (a *AggregateQuery) Filter(expression string) *AggregateQuery {
-	a.AggregatePlan = a.AggregatePlan.Add("FILTER", expression)
+	a.AggregatePlan = redis.Args(a.AggregatePlan).Add("FILTER", expression)
 	//a.Filters = append(a.Filters, expression)
 	return a
 }
 
-func (q AggregateQuery) Serialize() redis.Args {
+// Serialize the aggregation into FT.AGGREGATE arguments.
+// Only the query string, the VERBATIM flag, PARAMS, SCORER and DIALECT are used from the underlying Query,
+// the remaining search options are rejected by Validate()
+func (q AggregateQuery) Serialize() []interface{} {
 	args := redis.Args{}
 	if q.Query != nil {
-		args = args.AddFlat(q.Query.serialize())
+		args = args.Add(q.Query.Raw)
 	} else {
 		args = args.Add("*")
 	}
@@ -258,27 +329,150 @@ func (q AggregateQuery) Serialize() redis.Args {
 		args = args.AddFlat("WITHSCHEMA")
 	}
 	// VERBATIM
-	if q.Verbatim {
+	if q.Verbatim || (q.Query != nil && q.Query.Flags&QueryVerbatim != 0) {
 		args = args.Add("VERBATIM")
 	}
+	// TIMEOUT
+	if q.Timeout > 0 {
+		args = args.Add("TIMEOUT", q.Timeout)
+	}
+	// ADDSCORES
+	if q.AddScores {
+		args = args.Add("ADDSCORES")
+	}
 	// WITHCURSOR
 	if q.WithCursor {
 		args = args.AddFlat(q.Cursor.Serialize())
 	}
 
-	// TODO: add load fields
-
-	//Add the aggregation plan with ( GROUPBY and REDUCE | SORTBY | APPLY | FILTER ).+ clauses
-	args = args.AddFlat(q.AggregatePlan)
+	//Add the aggregation plan with ( GROUPBY and REDUCE | SORTBY | APPLY | FILTER | LOAD ).+ clauses
+	if q.Max > 0 && q.sortByEnd > 0 && q.sortByEnd <= len(q.AggregatePlan) {
+		args = args.AddFlat(q.AggregatePlan[:q.sortByEnd])
+		args = args.Add("MAX", q.Max)
+		args = args.AddFlat(q.AggregatePlan[q.sortByEnd:])
+	} else {
+		args = args.AddFlat(q.AggregatePlan)
+	}
 
 	// LIMIT
 	if !reflect.ValueOf(q.Paging).IsNil() {
 		args = args.Add("LIMIT", q.Paging.Offset, q.Paging.Num)
 	}
 
+	params, scorer, dialect := q.Params, q.Scorer, q.Dialect
+	if q.Query != nil {
+		if params == nil {
+			params = q.Query.Params
+		}
+		if scorer == "" {
+			scorer = q.Query.Scorer
+		}
+		if dialect == 0 {
+			dialect = q.Query.Dialect
+		}
+	}
+	if params != nil {
+		args = args.Add("PARAMS", len(params)*2)
+		for _, name := range sortedKeys(params) {
+			args = args.Add(name, params[name])
+		}
+	}
+	if scorer != "" {
+		args = args.Add("SCORER", scorer)
+	}
+	if dialect != 0 {
+		args = args.Add("DIALECT", dialect)
+	}
+
 	return args
 }
 
+// Validate checks that the underlying Query does not use options that are only valid for FT.SEARCH
+func (q AggregateQuery) Validate() error {
+	if q.Query == nil {
+		return nil
+	}
+	var invalid []string
+	if q.Query.Paging.Offset != DefaultOffset || q.Query.Paging.Num != DefaultNum {
+		invalid = append(invalid, "LIMIT")
+	}
+	flags := []struct {
+		flag Flag
+		name string
+	}{
+		{QueryNoContent, "NOCONTENT"},
+		{QueryWithScores, "WITHSCORES"},
+		{QueryInOrder, "INORDER"},
+		{QueryWithPayloads, "WITHPAYLOADS"},
+		{QueryWithStopWords, "NOSTOPWORDS"},
+	}
+	for _, f := range flags {
+		if q.Query.Flags&f.flag != 0 {
+			invalid = append(invalid, f.name)
+		}
+	}
+	if q.Query.Slop != nil {
+		invalid = append(invalid, "SLOP")
+	}
+	if len(q.Query.Filters) > 0 {
+		invalid = append(invalid, "FILTER")
+	}
+	if q.Query.InKeys != nil {
+		invalid = append(invalid, "INKEYS")
+	}
+	if q.Query.InFields != nil {
+		invalid = append(invalid, "INFIELDS")
+	}
+	if q.Query.ReturnFields != nil {
+		invalid = append(invalid, "RETURN")
+	}
+	if q.Query.Language != "" {
+		invalid = append(invalid, "LANGUAGE")
+	}
+	if q.Query.Expander != "" {
+		invalid = append(invalid, "EXPANDER")
+	}
+	if q.Query.Payload != nil {
+		invalid = append(invalid, "PAYLOAD")
+	}
+	if q.Query.SortBy != nil {
+		invalid = append(invalid, "SORTBY")
+	}
+	if q.Query.HighlightOpts != nil {
+		invalid = append(invalid, "HIGHLIGHT")
+	}
+	if q.Query.SummarizeOpts != nil {
+		invalid = append(invalid, "SUMMARIZE")
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("FT.AGGREGATE does not support the search-only options: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// internal method
+// processReply converts a FT.AGGREGATE or FT.CURSOR reply, updating the cursor id if the query has a cursor
+func (q *AggregateQuery) processReply(reply interface{}) (total int, aggregateReply []map[string]interface{}, err error) {
+	// has no cursor
+	if !q.WithCursor {
+		return processAggQueryReply(reply)
+	}
+	// has cursor
+	res, err := redis.Values(reply, nil)
+	if err != nil {
+		return
+	}
+	if len(res) != 2 {
+		err = fmt.Errorf("Error parsing Aggregate Reply: expected a 2 elements cursor reply, got %d", len(res))
+		return
+	}
+	q.Cursor.Id, err = redis.Int(res[1], nil)
+	if err != nil {
+		return
+	}
+	return processAggQueryReply(res[0])
+}
+
 // Deprecated: Please use processAggReply() instead
 func ProcessAggResponse(res []interface{}) [][]string {
 	aggregateReply := make([][]string, len(res))
@@ -294,9 +488,13 @@ func ProcessAggResponse(res []interface{}) [][]string {
 }
 
 // Deprecated: Please use processAggQueryReply() instead
-func processAggReply(res []interface{}) (total int, aggregateReply [][]string, err error) {
+func processAggReply(reply interface{}) (total int, aggregateReply [][]string, err error) {
 	aggregateReply = [][]string{}
 	total = 0
+	res, err := aggregateRows(reply)
+	if err != nil {
+		return
+	}
 	aggregateResults := len(res) - 1
 	if aggregateResults > 0 {
 		total = aggregateResults
@@ -314,9 +512,13 @@ func processAggReply(res []interface{}) (total int, aggregateReply [][]string, e
 }
 
 // New Aggregate reply processor
-func processAggQueryReply(res []interface{}) (total int, aggregateReply []map[string]interface{}, err error) {
+func processAggQueryReply(reply interface{}) (total int, aggregateReply []map[string]interface{}, err error) {
 	aggregateReply = []map[string]interface{}{}
 	total = 0
+	res, err := aggregateRows(reply)
+	if err != nil {
+		return
+	}
 	aggregateResults := len(res) - 1
 	if aggregateResults > 0 {
 		total = aggregateResults
@@ -333,6 +535,37 @@ func processAggQueryReply(res []interface{}) (total int, aggregateReply []map[st
 	return
 }
 
+// internal function
+// aggregateRows returns the rows of a FT.AGGREGATE reply, preceded by the total as in RESP2.
+// The rows of a RESP3 reply are the extra_attributes maps of its results.
+func aggregateRows(reply interface{}) ([]interface{}, error) {
+	m, ok := reply.(RESP3Map)
+	if !ok {
+		return redis.Values(reply, nil)
+	}
+	total, _ := m.Get("total_results")
+	value, _ := m.Get("results")
+	results, err := redis.Values(value, nil)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]interface{}, 1, len(results)+1)
+	rows[0] = total
+	for _, result := range results {
+		row, err := replyValues(result, nil)
+		if err != nil {
+			return nil, err
+		}
+		attributes, _ := RESP3Map(row).Get("extra_attributes")
+		fields, err := replyValues(attributes, nil)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, fields)
+	}
+	return rows, nil
+}
+
 func ProcessAggResponseSS(res []interface{}) [][]string {
 	var lout = len(res)
 	aggregateReply := make([][]string, lout)
@@ -356,7 +589,7 @@ func ProcessAggResponseSS(res []interface{}) [][]string {
 // The value can be string or []string. Numbers will be treated as strings. Requires an even number of
 // values in result.
 func mapToStrings(result interface{}, err error) (map[string]interface{}, error) {
-	values, err := redis.Values(result, err)
+	values, err := replyValues(result, err)
 	if err != nil {
 		return nil, err
 	}
@@ -13,7 +13,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,6 +36,11 @@ func init() {
 	if exists && value != "" {
 		requiresDatagen = false
 	}
+	// the golden tests replay the server replies, they run without a server
+	switch os.Getenv("REDISEARCH_GOLDEN") {
+	case "replay", "diff":
+		requiresDatagen = false
+	}
 	if requiresDatagen {
 		c := createClient("bench.ft.aggregate")
 
@@ -151,8 +155,7 @@ func _init() {
 }
 
 func TestAggregateSortByMax(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().SetQuery(NewQuery("sony")).
 		SetMax(60).
@@ -174,8 +177,7 @@ func TestAggregateSortByMax(t *testing.T) {
 }
 
 func TestAggregateGroupBy(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -195,8 +197,7 @@ func TestAggregateGroupBy(t *testing.T) {
 }
 
 func TestAggregateMinMax(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().SetQuery(NewQuery("sony")).
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -240,8 +241,7 @@ func TestAggregateMinMax(t *testing.T) {
 }
 
 func TestAggregateCountDistinct(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -259,8 +259,7 @@ func TestAggregateCountDistinct(t *testing.T) {
 }
 
 func TestAggregateToList(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -276,8 +275,7 @@ func TestAggregateToList(t *testing.T) {
 }
 
 func TestAggregateFilter(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -300,8 +298,7 @@ func TestAggregateFilter(t *testing.T) {
 }
 
 func TestAggregateApply(t *testing.T) {
-	_init()
-	c := createClient("docs-games-idx1")
+	c := createGoldenClient(t, "docs-games-idx1", _init, "FT.AGGREGATE")
 
 	q1 := NewAggregateQuery().
 		GroupBy(*NewGroupBy().AddFields("@brand").
@@ -377,9 +374,9 @@ func TestProjection_Serialize(t *testing.T) {
 	tests := []struct {
 		name       string
 		projection Projection
-		want       redis.Args
+		want       []interface{}
 	}{
-		{"Test_Serialize_1", *NewProjection("sqrt(log(foo) * floor(@bar/baz)) + (3^@qaz % 6)", "sqrt"), redis.Args{"APPLY", "sqrt(log(foo) * floor(@bar/baz)) + (3^@qaz % 6)", "AS", "sqrt"}},
+		{"Test_Serialize_1", *NewProjection("sqrt(log(foo) * floor(@bar/baz)) + (3^@qaz % 6)", "sqrt"), []interface{}{"APPLY", "sqrt(log(foo) * floor(@bar/baz)) + (3^@qaz % 6)", "AS", "sqrt"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -394,11 +391,11 @@ func TestCursor_Serialize(t *testing.T) {
 	tests := []struct {
 		name   string
 		cursor Cursor
-		want   redis.Args
+		want   []interface{}
 	}{
-		{"TestCursor_Serialize_basic", *NewCursor().SetId(1), redis.Args{"WITHCURSOR"}},
-		{"TestCursor_Serialize_MAXIDLE", *NewCursor().SetId(1).SetMaxIdle(30000), redis.Args{"WITHCURSOR", "MAXIDLE", 30000}},
-		{"TestCursor_Serialize_COUNT_MAXIDLE", *NewCursor().SetId(1).SetMaxIdle(30000).SetCount(10), redis.Args{"WITHCURSOR", "COUNT", 10, "MAXIDLE", 30000}},
+		{"TestCursor_Serialize_basic", *NewCursor().SetId(1), []interface{}{"WITHCURSOR"}},
+		{"TestCursor_Serialize_MAXIDLE", *NewCursor().SetId(1).SetMaxIdle(30000), []interface{}{"WITHCURSOR", "MAXIDLE", 30000}},
+		{"TestCursor_Serialize_COUNT_MAXIDLE", *NewCursor().SetId(1).SetMaxIdle(30000).SetCount(10), []interface{}{"WITHCURSOR", "COUNT", 10, "MAXIDLE", 30000}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -413,12 +410,25 @@ func TestQuery_Serialize(t *testing.T) {
 	tests := []struct {
 		name  string
 		query AggregateQuery
-		want  redis.Args
+		want  []interface{}
 	}{
-		{"TestQuery_Serialize_basic", *NewAggregateQuery(), redis.Args{"*"}},
-		{"TestQuery_Serialize_WITHSCHEMA", *NewAggregateQuery().SetWithSchema(true), redis.Args{"*", "WITHSCHEMA"}},
-		{"TestQuery_Serialize_VERBATIM", *NewAggregateQuery().SetVerbatim(true), redis.Args{"*", "VERBATIM"}},
-		{"TestQuery_Serialize_WITHCURSOR", *NewAggregateQuery().SetCursor(NewCursor()), redis.Args{"*", "WITHCURSOR"}},
+		{"TestQuery_Serialize_basic", *NewAggregateQuery(), []interface{}{"*"}},
+		{"TestQuery_Serialize_WITHSCHEMA", *NewAggregateQuery().SetWithSchema(true), []interface{}{"*", "WITHSCHEMA"}},
+		{"TestQuery_Serialize_VERBATIM", *NewAggregateQuery().SetVerbatim(true), []interface{}{"*", "VERBATIM"}},
+		{"TestQuery_Serialize_WITHCURSOR", *NewAggregateQuery().SetCursor(NewCursor()), []interface{}{"*", "WITHCURSOR"}},
+		{"TestQuery_Serialize_TIMEOUT", *NewAggregateQuery().SetTimeout(500), []interface{}{"*", "TIMEOUT", 500}},
+		{"TestQuery_Serialize_ADDSCORES_SCORER", *NewAggregateQuery().SetAddScores(true).SetScorer("BM25"), []interface{}{"*", "ADDSCORES", "SCORER", "BM25"}},
+		{"TestQuery_Serialize_PARAMS_DIALECT", *NewAggregateQuery().SetQuery(NewQuery("@v:[$min $max]")).AddParam("min", 1).SetDialect(2), []interface{}{"@v:[$min $max]", "PARAMS", 2, "min", 1, "DIALECT", 2}},
+		{"TestQuery_Serialize_PARAMS_sorted", *NewAggregateQuery().SetQuery(NewQuery("@v:[$min $max] @t:{$tag}")).AddParam("tag", "a").AddParam("min", 1).AddParam("max", 10).SetDialect(2), []interface{}{"@v:[$min $max] @t:{$tag}", "PARAMS", 6, "max", 10, "min", 1, "tag", "a", "DIALECT", 2}},
+		{"TestQuery_Serialize_Query_PARAMS_DIALECT", *NewAggregateQuery().SetQuery(NewQuery("@v:[$min 10]").AddParam("min", 1).SetDialect(3)), []interface{}{"@v:[$min 10]", "PARAMS", 2, "min", 1, "DIALECT", 3}},
+		{"TestQuery_Serialize_Query_VERBATIM", *NewAggregateQuery().SetQuery(NewQuery("foo").SetFlags(QueryVerbatim)), []interface{}{"foo", "VERBATIM"}},
+		{"TestQuery_Serialize_Query_LIMIT_not_injected", *NewAggregateQuery().SetQuery(NewQuery("foo").Limit(0, 100)), []interface{}{"foo"}},
+		{"TestQuery_Serialize_SORTBY_MAX", *NewAggregateQuery().SetMax(5).SortBy([]SortingKey{*NewSortingKeyDir("@price", false)}), []interface{}{"*", "SORTBY", 2, "@price", "DESC", "MAX", 5}},
+		{"TestQuery_Serialize_MAX_after_SORTBY", *NewAggregateQuery().SortBy([]SortingKey{*NewSortingKeyDir("@price", false)}).SetMax(5).Limit(0, 5), []interface{}{"*", "SORTBY", 2, "@price", "DESC", "MAX", 5, "LIMIT", 0, 5}},
+		{"TestQuery_Serialize_MAX_before_APPLY", *NewAggregateQuery().SortBy([]SortingKey{*NewSortingKeyDir("@price", true)}).Apply(*NewProjection("@price*2", "double")).SetMax(5), []interface{}{"*", "SORTBY", 2, "@price", "ASC", "MAX", 5, "APPLY", "@price*2", "AS", "double"}},
+		{"TestQuery_Serialize_MAX_without_SORTBY", *NewAggregateQuery().SetMax(5), []interface{}{"*"}},
+		{"TestQuery_Serialize_LOAD_AS", *NewAggregateQuery().LoadFields([]LoadField{{Name: "title", As: "t"}, {Name: "@price"}}), []interface{}{"*", "LOAD", 4, "@title", "AS", "t", "@price"}},
+		{"TestQuery_Serialize_LOAD_AS_All", *NewAggregateQuery().LoadFields(nil), []interface{}{"*", "LOAD", "*"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -429,17 +439,46 @@ func TestQuery_Serialize(t *testing.T) {
 	}
 }
 
+func TestAggregateQuery_Validate(t *testing.T) {
+	slop := 1
+	tests := []struct {
+		name    string
+		query   *AggregateQuery
+		wantErr bool
+	}{
+		{"no-query", NewAggregateQuery(), false},
+		{"plain-query", NewAggregateQuery().SetQuery(NewQuery("foo")), false},
+		{"verbatim-params-dialect", NewAggregateQuery().SetQuery(NewQuery("$foo").SetFlags(QueryVerbatim).AddParam("foo", "bar").SetDialect(2)), false},
+		{"LIMIT", NewAggregateQuery().SetQuery(NewQuery("foo").Limit(0, 1)), true},
+		{"RETURN", NewAggregateQuery().SetQuery(NewQuery("foo").SetReturnFields("a")), true},
+		{"HIGHLIGHT", NewAggregateQuery().SetQuery(NewQuery("foo").Highlight(nil, "<b>", "</b>")), true},
+		{"SUMMARIZE", NewAggregateQuery().SetQuery(NewQuery("foo").Summarize("a")), true},
+		{"WITHSCORES", NewAggregateQuery().SetQuery(NewQuery("foo").SetFlags(QueryWithScores)), true},
+		{"NOCONTENT", NewAggregateQuery().SetQuery(NewQuery("foo").SetFlags(QueryNoContent)), true},
+		{"SORTBY", NewAggregateQuery().SetQuery(NewQuery("foo").SetSortBy("a", true)), true},
+		{"SLOP", NewAggregateQuery().SetQuery(&Query{Raw: "foo", Paging: Paging{DefaultOffset, DefaultNum}, Slop: &slop}), true},
+		{"FILTER", NewAggregateQuery().SetQuery(NewQuery("foo").AddFilter(Filter{Field: "a", Options: NumericFilterOptions{Min: 1, Max: 2}})), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGroupBy_Serialize(t *testing.T) {
 	testsSerialize := []struct {
 		name  string
 		group GroupBy
-		want  redis.Args
+		want  []interface{}
 	}{
-		{"TestGroupBy_Serialize_basic", *NewGroupBy(), redis.Args{"GROUPBY", 0}},
-		{"TestGroupBy_Serialize_FIELDS", *NewGroupBy().AddFields("a"), redis.Args{"GROUPBY", 1, "a"}},
-		{"TestGroupBy_Serialize_FIELDS_2", *NewGroupBy().AddFields([]string{"a", "b"}), redis.Args{"GROUPBY", 2, "a", "b"}},
-		{"TestGroupBy_Serialize_REDUCE", *NewGroupBy().Reduce(*NewReducerAlias(GroupByReducerCount, []string{}, "count")), redis.Args{"GROUPBY", 0, "REDUCE", "COUNT", 0, "AS", "count"}},
-		{"TestGroupBy_Serialize_LIMIT", *NewGroupBy().Limit(10, 20), redis.Args{"GROUPBY", 0, "LIMIT", 10, 20}},
+		{"TestGroupBy_Serialize_basic", *NewGroupBy(), []interface{}{"GROUPBY", 0}},
+		{"TestGroupBy_Serialize_FIELDS", *NewGroupBy().AddFields("a"), []interface{}{"GROUPBY", 1, "a"}},
+		{"TestGroupBy_Serialize_FIELDS_2", *NewGroupBy().AddFields([]string{"a", "b"}), []interface{}{"GROUPBY", 2, "a", "b"}},
+		{"TestGroupBy_Serialize_REDUCE", *NewGroupBy().Reduce(*NewReducerAlias(GroupByReducerCount, []string{}, "count")), []interface{}{"GROUPBY", 0, "REDUCE", "COUNT", 0, "AS", "count"}},
+		{"TestGroupBy_Serialize_LIMIT", *NewGroupBy().Limit(10, 20), []interface{}{"GROUPBY", 0, "LIMIT", 10, 20}},
 	}
 	for _, tt := range testsSerialize {
 		t.Run(tt.name, func(t *testing.T) {
@@ -536,7 +575,7 @@ func TestGroupBy_Limit(t *testing.T) {
 func TestAggregateQuery_SetMax(t *testing.T) {
 	type fields struct {
 		Query         *Query
-		AggregatePlan redis.Args
+		AggregatePlan []interface{}
 		Paging        *Paging
 		Max           int
 		WithSchema    bool
@@ -554,9 +593,9 @@ func TestAggregateQuery_SetMax(t *testing.T) {
 		want   *AggregateQuery
 	}{
 		{"TestAggregateQuery_SetMax_1",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{10},
-			&AggregateQuery{nil, redis.Args{}, nil, 10, false, false, false, nil},
+			&AggregateQuery{AggregatePlan: []interface{}{}, Max: 10},
 		},
 	}
 	for _, tt := range tests {
@@ -581,7 +620,7 @@ func TestAggregateQuery_SetMax(t *testing.T) {
 func TestAggregateQuery_SetVerbatim(t *testing.T) {
 	type fields struct {
 		Query         *Query
-		AggregatePlan redis.Args
+		AggregatePlan []interface{}
 		Paging        *Paging
 		Max           int
 		WithSchema    bool
@@ -599,9 +638,9 @@ func TestAggregateQuery_SetVerbatim(t *testing.T) {
 		want   *AggregateQuery
 	}{
 		{"TestAggregateQuery_SetVerbatim_1",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{true},
-			&AggregateQuery{nil, redis.Args{}, nil, 0, false, true, false, nil},
+			&AggregateQuery{AggregatePlan: []interface{}{}, Verbatim: true},
 		},
 	}
 	for _, tt := range tests {
@@ -626,7 +665,7 @@ func TestAggregateQuery_SetVerbatim(t *testing.T) {
 func TestAggregateQuery_SetWithSchema(t *testing.T) {
 	type fields struct {
 		Query         *Query
-		AggregatePlan redis.Args
+		AggregatePlan []interface{}
 		Paging        *Paging
 		Max           int
 		WithSchema    bool
@@ -644,9 +683,9 @@ func TestAggregateQuery_SetWithSchema(t *testing.T) {
 		want   *AggregateQuery
 	}{
 		{"TestAggregateQuery_SetWithSchema_1",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{true},
-			&AggregateQuery{nil, redis.Args{}, nil, 0, true, false, false, nil},
+			&AggregateQuery{AggregatePlan: []interface{}{}, WithSchema: true},
 		},
 	}
 	for _, tt := range tests {
@@ -671,7 +710,7 @@ func TestAggregateQuery_SetWithSchema(t *testing.T) {
 func TestAggregateQuery_CursorHasResults(t *testing.T) {
 	type fields struct {
 		Query         *Query
-		AggregatePlan redis.Args
+		AggregatePlan []interface{}
 		Paging        *Paging
 		Max           int
 		WithSchema    bool
@@ -685,11 +724,11 @@ func TestAggregateQuery_CursorHasResults(t *testing.T) {
 		wantRes bool
 	}{
 		{"TestAggregateQuery_CursorHasResults_1_false",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			false,
 		},
 		{"TestAggregateQuery_CursorHasResults_1_true",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, NewCursor().SetId(10)},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, NewCursor().SetId(10)},
 			true,
 		},
 	}
@@ -715,7 +754,7 @@ func TestAggregateQuery_CursorHasResults(t *testing.T) {
 func TestAggregateQuery_Load(t *testing.T) {
 	type fields struct {
 		Query         *Query
-		AggregatePlan redis.Args
+		AggregatePlan []interface{}
 		Paging        *Paging
 		Max           int
 		WithSchema    bool
@@ -730,22 +769,22 @@ func TestAggregateQuery_Load(t *testing.T) {
 		name   string
 		fields fields
 		args   args
-		want   redis.Args
+		want   []interface{}
 	}{
 		{"TestAggregateQuery_Load_1",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{[]string{"field1"}},
-			redis.Args{"*", "LOAD", 1, "@field1"},
+			[]interface{}{"*", "LOAD", 1, "@field1"},
 		},
 		{"TestAggregateQuery_Load_2",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{[]string{"field1", "field2", "field3", "field4"}},
-			redis.Args{"*", "LOAD", 4, "@field1", "@field2", "@field3", "@field4"},
+			[]interface{}{"*", "LOAD", 4, "@field1", "@field2", "@field3", "@field4"},
 		},
 		{"TestAggregateQuery_Load_All",
-			fields{nil, redis.Args{}, nil, 0, false, false, false, nil},
+			fields{nil, []interface{}{}, nil, 0, false, false, false, nil},
 			args{[]string{}},
-			redis.Args{"*", "LOAD", "*"},
+			[]interface{}{"*", "LOAD", "*"},
 		},
 	}
 	for _, tt := range tests {
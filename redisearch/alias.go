@@ -0,0 +1,232 @@
+package redisearch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrAliasNotFound is returned by AliasManager.Resolve when the alias does not exist
+var ErrAliasNotFound = errors.New("alias not found")
+
+// ErrAliasLocked is returned by AliasManager.Swap when another swap of the same alias is in progress
+var ErrAliasLocked = errors.New("alias swap already in progress")
+
+// AliasConflictError is returned by AliasManager.Swap when the alias does not point at the expected index
+type AliasConflictError struct {
+	Alias string
+	// Expected is the index the alias was expected to point at, "" if it was expected not to exist
+	Expected string
+	// Actual is the index the alias points at, "" if it does not exist
+	Actual string
+}
+
+func (e *AliasConflictError) Error() string {
+	return fmt.Sprintf("alias %s points at %q, expected %q", e.Alias, e.Actual, e.Expected)
+}
+
+// DefaultAliasLockTTL is the default expiration of the lock held during an alias swap
+const DefaultAliasLockTTL = 10 * time.Second
+
+// SwapOptions are the options of AliasManager.Swap
+type SwapOptions struct {
+	// DropOld drops the previous index once the alias has been swapped and the grace period has elapsed
+	DropOld bool
+	// GracePeriod is the time given to in-flight queries on the previous index before it is dropped
+	GracePeriod time.Duration
+	// DeleteDocuments deletes the documents of the previous index when it is dropped
+	DeleteDocuments bool
+}
+
+// AliasManager resolves aliases and switches them between indexes, e.g. for blue/green reindexing.
+// Swaps of the same alias are serialized with a lock key in redis, so that concurrent deployers using
+// an AliasManager cannot overwrite each other's changes.
+type AliasManager struct {
+	pool ConnPool
+	// LockTTL is the expiration of the swap lock, in case the deployer holding it dies
+	LockTTL time.Duration
+	// LockPrefix is the prefix of the lock keys, followed by the alias name
+	LockPrefix string
+}
+
+// NewAliasManager creates an alias manager using the given pool
+func NewAliasManager(pool ConnPool) *AliasManager {
+	return &AliasManager{pool: pool, LockTTL: DefaultAliasLockTTL, LockPrefix: "redisearch:aliaslock:"}
+}
+
+// AliasManager creates an alias manager sharing the admin pool
+func (a *Admin) AliasManager() *AliasManager {
+	return NewAliasManager(a.pool)
+}
+
+// Resolve returns the name of the index the alias points at.
+// It returns ErrAliasNotFound if the alias does not exist, or if the name is an index rather than an alias.
+func (m *AliasManager) Resolve(alias string) (string, error) {
+	conn := m.pool.Get()
+	defer conn.Close()
+	return resolveAlias(conn, alias)
+}
+
+// internal function
+// resolveAlias returns the name of the index the alias points at, using the index_name reported by FT.INFO
+func resolveAlias(conn redis.Conn, alias string) (string, error) {
+	res, err := replyValues(conn.Do("FT.INFO", alias))
+	if err != nil {
+		if isUnknownIndexError(err) {
+			return "", ErrAliasNotFound
+		}
+		return "", err
+	}
+	for ii := 0; ii+1 < len(res); ii += 2 {
+		key, _ := redis.String(res[ii], nil)
+		if key == "index_name" {
+			index, err := redis.String(res[ii+1], nil)
+			if err != nil {
+				return "", err
+			}
+			if index == alias {
+				return "", ErrAliasNotFound
+			}
+			return index, nil
+		}
+	}
+	return "", errors.New("Resolve: FT.INFO reply has no index_name")
+}
+
+func isUnknownIndexError(err error) bool {
+	if _, ok := err.(redis.Error); !ok {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown index") || strings.Contains(msg, "no such index")
+}
+
+// releaseAliasLock deletes the lock key only if it still holds the token of the caller
+var releaseAliasLock = redis.NewScript(1, `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Swap points the alias at newIndex, only if it still points at expectedOld ("" meaning that the alias must
+// not exist yet). Otherwise it returns an *AliasConflictError and leaves the alias unchanged.
+// If another swap of the alias is in progress, it returns ErrAliasLocked.
+// If opts.DropOld is set, Swap blocks for the grace period and then drops the previous index while holding
+// the alias lock again, unless the alias was pointed back at it in the meantime, in which case it returns an
+// *AliasConflictError, or another swap of the alias holds the lock, in which case it returns ErrAliasLocked.
+func (m *AliasManager) Swap(alias, expectedOld, newIndex string, opts *SwapOptions) error {
+	if opts == nil {
+		opts = &SwapOptions{}
+	}
+	if err := m.swap(alias, expectedOld, newIndex); err != nil {
+		return err
+	}
+	if !opts.DropOld || expectedOld == "" || expectedOld == newIndex {
+		return nil
+	}
+	time.Sleep(opts.GracePeriod)
+	return m.dropOld(alias, expectedOld, newIndex, opts.DeleteDocuments)
+}
+
+// internal method
+// lock acquires the swap lock of the alias, and returns the function releasing it
+func (m *AliasManager) lock(conn redis.Conn, alias string) (func() error, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+	lockKey := m.LockPrefix + alias
+	ttl := m.LockTTL
+	if ttl <= 0 {
+		ttl = DefaultAliasLockTTL
+	}
+	_, err = redis.String(conn.Do("SET", lockKey, token, "NX", "PX", ttl.Milliseconds()))
+	if err == redis.ErrNil {
+		return nil, ErrAliasLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		_, err := releaseAliasLock.Do(conn, lockKey, token)
+		return err
+	}, nil
+}
+
+// internal method
+// swap checks and updates the alias while holding the alias lock
+func (m *AliasManager) swap(alias, expectedOld, newIndex string) (err error) {
+	conn := m.pool.Get()
+	defer conn.Close()
+
+	release, err := m.lock(conn, alias)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	current, err := resolveAlias(conn, alias)
+	if err == ErrAliasNotFound {
+		current, err = "", nil
+	}
+	if err != nil {
+		return err
+	}
+	if current != expectedOld {
+		return &AliasConflictError{Alias: alias, Expected: expectedOld, Actual: current}
+	}
+	if current == newIndex {
+		return nil
+	}
+	_, err = redis.String(conn.Do("FT.ALIASUPDATE", alias, newIndex))
+	return err
+}
+
+// internal method
+// dropOld drops the previous index of a swap while holding the alias lock, unless the alias points at it again
+func (m *AliasManager) dropOld(alias, oldIndex, newIndex string, deleteDocuments bool) (err error) {
+	conn := m.pool.Get()
+	defer conn.Close()
+
+	release, err := m.lock(conn, alias)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	current, err := resolveAlias(conn, alias)
+	if err != nil && err != ErrAliasNotFound {
+		return err
+	}
+	if current == oldIndex {
+		return &AliasConflictError{Alias: alias, Expected: newIndex, Actual: current}
+	}
+	args := redis.Args{oldIndex}
+	if deleteDocuments {
+		args = args.Add("DD")
+	}
+	_, err = conn.Do("FT.DROPINDEX", args...)
+	return err
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
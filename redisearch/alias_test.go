@@ -0,0 +1,161 @@
+package redisearch
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasManager_Swap(t *testing.T) {
+	skipInMemory(t, "EVALSHA")
+	a := createAdmin()
+	flush(a.Client(""))
+	for _, name := range []string{"alias-blue", "alias-green"} {
+		assert.Nil(t, a.Client(name).CreateIndex(NewSchema(DefaultOptions).AddField(NewTextField("foo"))))
+	}
+	m := a.AliasManager()
+
+	_, err := m.Resolve("alias-live")
+	assert.Equal(t, ErrAliasNotFound, err)
+	_, err = m.Resolve("alias-blue")
+	assert.Equal(t, ErrAliasNotFound, err)
+
+	assert.Nil(t, m.Swap("alias-live", "", "alias-blue", nil))
+	index, err := m.Resolve("alias-live")
+	assert.Nil(t, err)
+	assert.Equal(t, "alias-blue", index)
+
+	err = m.Swap("alias-live", "alias-green", "alias-blue", nil)
+	assert.Equal(t, &AliasConflictError{Alias: "alias-live", Expected: "alias-green", Actual: "alias-blue"}, err)
+
+	assert.Nil(t, m.Swap("alias-live", "alias-blue", "alias-green", &SwapOptions{DropOld: true}))
+	index, err = m.Resolve("alias-live")
+	assert.Nil(t, err)
+	assert.Equal(t, "alias-green", index)
+	indexes, err := a.ListIndexes()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"alias-green"}, indexes)
+
+	conn := a.pool.Get()
+	_, err = conn.Do("SET", m.LockPrefix+"alias-live", "other-deployer")
+	conn.Close()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrAliasLocked, m.Swap("alias-live", "alias-green", "alias-blue", nil))
+	teardown(a.Client(""))
+}
+
+// aliasServer is a fake server with the commands used by AliasManager, whose FT.ALIASUPDATE can be paused
+type aliasServer struct {
+	mu      sync.Mutex
+	keys    map[string]string
+	aliases map[string]string
+	indexes map[string]bool
+	// paused, when set, is signaled by the next FT.ALIASUPDATE, which then waits for resume
+	paused chan struct{}
+	resume chan struct{}
+}
+
+func (s *aliasServer) Get() redis.Conn { return aliasConn{s} }
+func (s *aliasServer) Close() error    { return nil }
+
+func (s *aliasServer) alias(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.aliases[name]
+}
+
+type aliasConn struct{ s *aliasServer }
+
+func (c aliasConn) Close() error                      { return nil }
+func (c aliasConn) Err() error                        { return nil }
+func (c aliasConn) Send(string, ...interface{}) error { return nil }
+func (c aliasConn) Flush() error                      { return nil }
+func (c aliasConn) Receive() (interface{}, error)     { return nil, nil }
+func (c aliasConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	s := c.s
+	s.mu.Lock()
+	paused := s.paused
+	if cmd == "FT.ALIASUPDATE" {
+		s.paused = nil
+	}
+	s.mu.Unlock()
+	if cmd == "FT.ALIASUPDATE" && paused != nil {
+		paused <- struct{}{}
+		<-s.resume
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	str := func(i int) string { return fmt.Sprint(args[i]) }
+	switch cmd {
+	case "SET":
+		if _, ok := s.keys[str(0)]; ok {
+			return nil, nil
+		}
+		s.keys[str(0)] = str(1)
+		return "OK", nil
+	case "EVALSHA":
+		if s.keys[str(2)] != str(3) {
+			return int64(0), nil
+		}
+		delete(s.keys, str(2))
+		return int64(1), nil
+	case "FT.INFO":
+		name := str(0)
+		if index, ok := s.aliases[name]; ok {
+			name = index
+		}
+		if !s.indexes[name] {
+			return nil, redis.Error("Unknown Index name")
+		}
+		return []interface{}{[]byte("index_name"), []byte(name)}, nil
+	case "FT.ALIASUPDATE":
+		s.aliases[str(0)] = str(1)
+		return "OK", nil
+	case "FT.DROPINDEX":
+		delete(s.indexes, str(0))
+		return "OK", nil
+	}
+	return nil, fmt.Errorf("unexpected command %s", cmd)
+}
+
+func TestAliasManager_Swap_interleaved(t *testing.T) {
+	s := &aliasServer{
+		keys:    map[string]string{},
+		aliases: map[string]string{"live": "blue"},
+		indexes: map[string]bool{"blue": true, "green": true},
+		resume:  make(chan struct{}),
+	}
+	m := NewAliasManager(s)
+
+	// the first swap moves the alias to green, and drops blue after its grace period
+	first := make(chan error)
+	go func() {
+		first <- m.Swap("live", "blue", "green", &SwapOptions{DropOld: true, GracePeriod: 200 * time.Millisecond})
+	}()
+	for s.alias("live") != "green" {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a second swap rolls the alias back to blue, and is paused while holding the lock
+	paused := make(chan struct{})
+	s.mu.Lock()
+	s.paused = paused
+	s.mu.Unlock()
+	second := make(chan error)
+	go func() {
+		second <- m.Swap("live", "green", "blue", nil)
+	}()
+	<-paused
+
+	// the first swap cannot drop blue while the second one holds the lock
+	assert.Equal(t, ErrAliasLocked, <-first)
+	close(s.resume)
+	assert.Nil(t, <-second)
+	assert.Equal(t, "blue", s.alias("live"))
+	assert.True(t, s.indexes["blue"])
+	assert.Empty(t, s.keys)
+}
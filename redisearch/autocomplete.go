@@ -8,7 +8,7 @@ import (
 // Autocompleter implements a redisearch auto-completer API
 type Autocompleter struct {
 	name string
-	pool *redis.Pool
+	pool ConnPool
 }
 
 // NewAutocompleter creates a new Autocompleter with the given pool and key name
@@ -145,7 +145,7 @@ func (a *Autocompleter) SuggestOpts(prefix string, opts SuggestOptions) (ret []S
 	return
 }
 
-func (a *Autocompleter) Serialize(prefix string, opts SuggestOptions) (redis.Args, int) {
+func (a *Autocompleter) Serialize(prefix string, opts SuggestOptions) ([]interface{}, int) {
 	inc := 1
 	args := redis.Args{a.name, prefix, "MAX", opts.Num}
 	if opts.Fuzzy {
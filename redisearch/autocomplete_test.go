@@ -2,7 +2,6 @@ package redisearch
 
 import (
 	"fmt"
-	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
@@ -31,14 +30,14 @@ func TestAutocompleter_Serialize(t *testing.T) {
 		name   string
 		fields fields
 		args   args
-		want   redis.Args
+		want   []interface{}
 		want1  int
 	}{
-		{"default options", fields{"key1"}, args{"ab", DefaultSuggestOptions}, redis.Args{"key1", "ab", "MAX", 5}, 1},
-		{"FUZZY", fields{"key1"}, args{"ab", fuzzy}, redis.Args{"key1", "ab", "MAX", 5, "FUZZY"}, 1},
-		{"WITHSCORES", fields{"key1"}, args{"ab", withscores}, redis.Args{"key1", "ab", "MAX", 5, "WITHSCORES"}, 2},
-		{"WITHPAYLOADS", fields{"key1"}, args{"ab", withpayloads}, redis.Args{"key1", "ab", "MAX", 5, "WITHPAYLOADS"}, 2},
-		{"all", fields{"key1"}, args{"ab", all}, redis.Args{"key1", "ab", "MAX", 5, "FUZZY", "WITHSCORES", "WITHPAYLOADS"}, 3},
+		{"default options", fields{"key1"}, args{"ab", DefaultSuggestOptions}, []interface{}{"key1", "ab", "MAX", 5}, 1},
+		{"FUZZY", fields{"key1"}, args{"ab", fuzzy}, []interface{}{"key1", "ab", "MAX", 5, "FUZZY"}, 1},
+		{"WITHSCORES", fields{"key1"}, args{"ab", withscores}, []interface{}{"key1", "ab", "MAX", 5, "WITHSCORES"}, 2},
+		{"WITHPAYLOADS", fields{"key1"}, args{"ab", withpayloads}, []interface{}{"key1", "ab", "MAX", 5, "WITHPAYLOADS"}, 2},
+		{"all", fields{"key1"}, args{"ab", all}, []interface{}{"key1", "ab", "MAX", 5, "FUZZY", "WITHSCORES", "WITHPAYLOADS"}, 3},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
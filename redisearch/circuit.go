@@ -0,0 +1,462 @@
+package redisearch
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrCircuitOpen is returned without contacting redis while the circuit breaker is open
+var ErrCircuitOpen = errors.New("redisearch: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets all the commands through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all the commands with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe commands through, to check whether redis recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings configures a circuit breaker. Zero values are replaced by the defaults of
+// DefaultCircuitBreakerSettings.
+type CircuitBreakerSettings struct {
+	// Window is the period over which the error and slow call rates are measured
+	Window time.Duration
+	// MinRequests is the number of commands of the window below which the breaker does not trip
+	MinRequests int
+	// ErrorRate is the rate of failed commands of the window tripping the breaker, between 0 and 1
+	ErrorRate float64
+	// SlowCallDuration is the duration above which a command is slow, 0 to ignore the latency
+	SlowCallDuration time.Duration
+	// SlowCallRate is the rate of slow commands of the window tripping the breaker, between 0 and 1
+	SlowCallRate float64
+	// OpenTimeout is the time the breaker stays open before letting probe commands through
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of successful probe commands closing the breaker
+	HalfOpenProbes int
+	// IsFailure returns whether an error is a failure of redis. By default the network errors and the transient
+	// server errors (see IsRetryableError) are failures, but not the errors of the commands such as syntax errors.
+	IsFailure func(err error) bool
+	// OnStateChange is called on each state change, with the host of the breaker ("" for a single host pool)
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerSettings returns settings tripping the breaker when half of at least 20 commands
+// failed over 10 seconds, and probing redis after 5 seconds
+func DefaultCircuitBreakerSettings() *CircuitBreakerSettings {
+	return &CircuitBreakerSettings{
+		Window:         10 * time.Second,
+		MinRequests:    20,
+		ErrorRate:      0.5,
+		SlowCallRate:   0.5,
+		OpenTimeout:    5 * time.Second,
+		HalfOpenProbes: 1,
+		IsFailure:      isCircuitFailure,
+	}
+}
+
+func isCircuitFailure(err error) bool {
+	if err == nil || err == ErrCircuitOpen {
+		return false
+	}
+	if _, ok := err.(redis.Error); ok {
+		return IsRetryableError(err)
+	}
+	return true
+}
+
+// internal method
+// withDefaults returns a copy of the settings with the defaults instead of the zero values
+func (s CircuitBreakerSettings) withDefaults() CircuitBreakerSettings {
+	defaults := DefaultCircuitBreakerSettings()
+	if s.Window <= 0 {
+		s.Window = defaults.Window
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = defaults.MinRequests
+	}
+	if s.ErrorRate <= 0 {
+		s.ErrorRate = defaults.ErrorRate
+	}
+	if s.SlowCallRate <= 0 {
+		s.SlowCallRate = defaults.SlowCallRate
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = defaults.OpenTimeout
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = defaults.HalfOpenProbes
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = defaults.IsFailure
+	}
+	return s
+}
+
+// CircuitBreaker tracks the health of a redis host, and rejects the commands while the host is unhealthy
+type CircuitBreaker struct {
+	host     string
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	slow        int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	return newCircuitBreaker("", settings)
+}
+
+func newCircuitBreaker(host string, settings CircuitBreakerSettings) *CircuitBreaker {
+	return &CircuitBreaker{host: host, settings: settings.withDefaults(), now: time.Now}
+}
+
+// State returns the current state of the breaker
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.settings.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// Allow returns ErrCircuitOpen if the command must be rejected. Otherwise the outcome of the command
+// must be reported with Record.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitOpen {
+		if cb.now().Sub(cb.openedAt) < cb.settings.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		cb.setState(CircuitHalfOpen)
+	}
+	if cb.state == CircuitHalfOpen {
+		if cb.probes >= cb.settings.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		cb.probes++
+	}
+	return nil
+}
+
+// Record reports the outcome of a command allowed by Allow
+func (cb *CircuitBreaker) Record(err error, duration time.Duration) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	failed := cb.settings.IsFailure(err)
+	slow := cb.settings.SlowCallDuration > 0 && duration > cb.settings.SlowCallDuration
+
+	switch cb.state {
+	case CircuitHalfOpen:
+		if cb.probes > 0 {
+			cb.probes--
+		}
+		if failed || slow {
+			cb.setState(CircuitOpen)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.settings.HalfOpenProbes {
+			cb.setState(CircuitClosed)
+		}
+	case CircuitClosed:
+		now := cb.now()
+		if now.Sub(cb.windowStart) >= cb.settings.Window {
+			cb.windowStart = now
+			cb.requests, cb.failures, cb.slow = 0, 0, 0
+		}
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if slow {
+			cb.slow++
+		}
+		if cb.requests < cb.settings.MinRequests {
+			return
+		}
+		if float64(cb.failures) >= cb.settings.ErrorRate*float64(cb.requests) ||
+			(cb.settings.SlowCallDuration > 0 && float64(cb.slow) >= cb.settings.SlowCallRate*float64(cb.requests)) {
+			cb.setState(CircuitOpen)
+		}
+	}
+}
+
+// internal method
+// release gives back the probe taken by Allow for a command whose outcome will not be recorded
+func (cb *CircuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitHalfOpen && cb.probes > 0 {
+		cb.probes--
+	}
+}
+
+// internal method
+// setState switches the state and resets the counters, the mutex must be held
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	from := cb.state
+	cb.state = state
+	cb.probes, cb.successes = 0, 0
+	cb.requests, cb.failures, cb.slow = 0, 0, 0
+	cb.windowStart = cb.now()
+	if state == CircuitOpen {
+		cb.openedAt = cb.now()
+	}
+	if cb.settings.OnStateChange != nil && from != state {
+		cb.settings.OnStateChange(cb.host, from, state)
+	}
+}
+
+// SetCircuitBreaker adds a circuit breaker to the client: the commands fail fast with ErrCircuitOpen, without
+// taking a connection from the pool, while redis is unhealthy. With a MultiHostPool each host has its own breaker,
+// and the connections are only taken from the hosts whose breaker is not open.
+// A nil settings removes the circuit breaker. SetCircuitBreaker must not be called concurrently with other methods.
+func (i *Client) SetCircuitBreaker(settings *CircuitBreakerSettings) {
+	i.pool = wrapBasePool(i.pool, func(pool ConnPool) ConnPool {
+		if bp, ok := pool.(*breakerPool); ok {
+			pool = bp.pool
+		}
+		if settings == nil {
+			return pool
+		}
+		return newBreakerPool(pool, *settings)
+	})
+}
+
+// CircuitStates returns the state of the circuit breakers of the client, by host ("" for a single host pool).
+// It returns nil if the client has no circuit breaker.
+func (i *Client) CircuitStates() map[string]CircuitState {
+	var states map[string]CircuitState
+	wrapBasePool(i.pool, func(pool ConnPool) ConnPool {
+		if bp, ok := pool.(*breakerPool); ok {
+			states = bp.states()
+		}
+		return pool
+	})
+	return states
+}
+
+// internal function
+// wrapBasePool replaces the pool under the hook and retry wrappers of this package with wrap(pool)
+func wrapBasePool(pool ConnPool, wrap func(ConnPool) ConnPool) ConnPool {
+	switch p := pool.(type) {
+	case *hookPool:
+		return &hookPool{pool: wrapBasePool(p.pool, wrap), hooks: p.hooks}
+	case *retryPool:
+		return &retryPool{pool: wrapBasePool(p.pool, wrap), policy: p.policy}
+	}
+	return wrap(pool)
+}
+
+// internal function
+// replaceWrapper rebuilds the hook and retry wrappers of this package around the base pool, replacing the outermost
+// wrapper for which replace returns true. It returns false, and the pool unchanged, if no wrapper was replaced.
+func replaceWrapper(pool ConnPool, replace func(ConnPool) (ConnPool, bool)) (ConnPool, bool) {
+	if replaced, ok := replace(pool); ok {
+		return replaced, true
+	}
+	switch p := pool.(type) {
+	case *hookPool:
+		if inner, ok := replaceWrapper(p.pool, replace); ok {
+			return &hookPool{pool: inner, hooks: p.hooks}, true
+		}
+	case *retryPool:
+		if inner, ok := replaceWrapper(p.pool, replace); ok {
+			return &retryPool{pool: inner, policy: p.policy}, true
+		}
+	}
+	return pool, false
+}
+
+// internal struct
+// breakerPool checks the circuit breakers before taking connections from the pool
+type breakerPool struct {
+	pool     ConnPool
+	multi    *MultiHostPool
+	settings CircuitBreakerSettings
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+func newBreakerPool(pool ConnPool, settings CircuitBreakerSettings) *breakerPool {
+	multi, _ := pool.(*MultiHostPool)
+	return &breakerPool{pool: pool, multi: multi, settings: settings, breakers: make(map[string]*CircuitBreaker)}
+}
+
+func (p *breakerPool) breaker(host string) *CircuitBreaker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cb, ok := p.breakers[host]
+	if !ok {
+		cb = newCircuitBreaker(host, p.settings)
+		p.breakers[host] = cb
+	}
+	return cb
+}
+
+func (p *breakerPool) states() map[string]CircuitState {
+	p.mu.Lock()
+	breakers := make(map[string]*CircuitBreaker, len(p.breakers))
+	for host, cb := range p.breakers {
+		breakers[host] = cb
+	}
+	p.mu.Unlock()
+	states := make(map[string]CircuitState, len(breakers))
+	for host, cb := range breakers {
+		states[host] = cb.State()
+	}
+	return states
+}
+
+func (p *breakerPool) Get() redis.Conn {
+	if p.multi == nil {
+		cb := p.breaker("")
+		if cb.State() == CircuitOpen {
+			return errorConn{ErrCircuitOpen}
+		}
+		return &breakerConn{Conn: p.pool.Get(), breaker: cb}
+	}
+	conn, host := p.multi.getFrom(func(host string) bool {
+		return p.breaker(host).State() != CircuitOpen
+	})
+	if conn == nil {
+		return errorConn{ErrCircuitOpen}
+	}
+	return &breakerConn{Conn: conn, breaker: p.breaker(host)}
+}
+
+func (p *breakerPool) Close() error {
+	return p.pool.Close()
+}
+
+// internal struct
+// breakerConn reports the outcome of each command to the circuit breaker of its host
+type breakerConn struct {
+	redis.Conn
+	breaker *CircuitBreaker
+	pending []*breakerCommand
+}
+
+type breakerCommand struct {
+	start time.Time
+	err   error
+}
+
+func (c *breakerConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if len(c.pending) > 0 || commandName == "" {
+		if commandName != "" {
+			c.Send(commandName, args...)
+		}
+		if err := c.Flush(); err != nil {
+			return nil, err
+		}
+		var reply interface{}
+		var err error
+		for len(c.pending) > 0 {
+			reply, err = c.Receive()
+		}
+		return reply, err
+	}
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	reply, err := c.Conn.Do(commandName, args...)
+	c.breaker.Record(err, time.Since(start))
+	return reply, err
+}
+
+func (c *breakerConn) Send(commandName string, args ...interface{}) error {
+	cmd := &breakerCommand{start: time.Now()}
+	if cmd.err = c.breaker.Allow(); cmd.err == nil {
+		if err := c.Conn.Send(commandName, args...); err != nil {
+			c.breaker.Record(err, time.Since(cmd.start))
+			return err
+		}
+	}
+	c.pending = append(c.pending, cmd)
+	return nil
+}
+
+func (c *breakerConn) Receive() (interface{}, error) {
+	if len(c.pending) == 0 {
+		return c.Conn.Receive()
+	}
+	cmd := c.pending[0]
+	c.pending = c.pending[1:]
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	reply, err := c.Conn.Receive()
+	c.breaker.Record(err, time.Since(cmd.start))
+	return reply, err
+}
+
+// Flush records a failure for the pending commands if the pipeline could not be sent, as their replies will not
+// be received
+func (c *breakerConn) Flush() error {
+	err := c.Conn.Flush()
+	if err != nil {
+		for _, cmd := range c.pending {
+			if cmd.err == nil {
+				c.breaker.Record(err, time.Since(cmd.start))
+			}
+		}
+		c.pending = nil
+	}
+	return err
+}
+
+// Close releases the probes of the commands whose replies were not received
+func (c *breakerConn) Close() error {
+	for _, cmd := range c.pending {
+		if cmd.err == nil {
+			c.breaker.release()
+		}
+	}
+	c.pending = nil
+	return c.Conn.Close()
+}
+
+// internal struct
+// errorConn is a redis.Conn failing all the commands with the same error
+type errorConn struct {
+	err error
+}
+
+func (c errorConn) Close() error                                   { return nil }
+func (c errorConn) Err() error                                     { return c.err }
+func (c errorConn) Do(string, ...interface{}) (interface{}, error) { return nil, c.err }
+func (c errorConn) Send(string, ...interface{}) error              { return c.err }
+func (c errorConn) Flush() error                                   { return c.err }
+func (c errorConn) Receive() (interface{}, error)                  { return nil, c.err }
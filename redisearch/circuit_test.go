@@ -0,0 +1,135 @@
+package redisearch
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	var changes []CircuitState
+	cb := NewCircuitBreaker(CircuitBreakerSettings{
+		MinRequests:      4,
+		SlowCallDuration: time.Second,
+		OnStateChange:    func(host string, from, to CircuitState) { changes = append(changes, to) },
+	})
+	cb.now = clock.now
+
+	// command errors are not failures
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, cb.Allow())
+		cb.Record(redis.Error("Unknown index name"), time.Millisecond)
+	}
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	clock.t = clock.t.Add(time.Minute)
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, cb.Allow())
+		if i%2 == 0 {
+			cb.Record(io.EOF, time.Millisecond)
+		} else {
+			cb.Record(nil, time.Millisecond)
+		}
+	}
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.Equal(t, ErrCircuitOpen, cb.Allow())
+
+	// a single probe is let through after the open timeout
+	clock.t = clock.t.Add(5 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.Nil(t, cb.Allow())
+	assert.Equal(t, ErrCircuitOpen, cb.Allow())
+	// a slow probe reopens the breaker
+	cb.Record(nil, 2*time.Second)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	clock.t = clock.t.Add(5 * time.Second)
+	assert.Nil(t, cb.Allow())
+	cb.Record(nil, time.Millisecond)
+	assert.Equal(t, CircuitClosed, cb.State())
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, changes)
+}
+
+func TestClient_SetCircuitBreaker(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Err: io.EOF}, {Err: io.EOF}}}
+	c := NewClientFromExecutor(exec, "idx")
+	c.SetCircuitBreaker(&CircuitBreakerSettings{MinRequests: 2})
+	assert.Equal(t, map[string]CircuitState{}, c.CircuitStates())
+
+	for i := 0; i < 2; i++ {
+		_, _, err := c.Search(NewQuery("hello"))
+		assert.Equal(t, io.EOF, err)
+	}
+	_, _, err := c.Search(NewQuery("hello"))
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Len(t, exec.cmds, 2)
+	assert.Equal(t, map[string]CircuitState{"": CircuitOpen}, c.CircuitStates())
+
+	c.SetCircuitBreaker(nil)
+	assert.Nil(t, c.CircuitStates())
+}
+
+func TestClient_SetCircuitBreaker_multiHost(t *testing.T) {
+	c := &Client{pool: NewMultiHostPool([]string{"127.0.0.1:1", "127.0.0.1:2"}), name: "idx"}
+	c.SetCircuitBreaker(&CircuitBreakerSettings{MinRequests: 1})
+	c.AddHook(NewMetricsHook(NewMetricsRegistry()))
+
+	bp := c.pool.(*hookPool).pool.(*breakerPool)
+	cb := bp.breaker("127.0.0.1:1")
+	cb.Allow()
+	cb.Record(io.EOF, 0)
+	for i := 0; i < 10; i++ {
+		conn := bp.Get()
+		assert.Equal(t, "127.0.0.1:2", conn.(*breakerConn).breaker.host)
+		conn.Close()
+	}
+
+	bp.breaker("127.0.0.1:2").Record(io.EOF, 0)
+	_, err := c.pool.Get().Do("FT.INFO", "idx")
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, map[string]CircuitState{"127.0.0.1:1": CircuitOpen, "127.0.0.1:2": CircuitOpen}, c.CircuitStates())
+}
+
+// flushErrorConn accepts the commands, and fails to flush them
+type flushErrorConn struct {
+	errorConn
+}
+
+func (c flushErrorConn) Send(string, ...interface{}) error { return nil }
+
+func TestCircuitBreaker_pipelineProbe(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	cb := NewCircuitBreaker(CircuitBreakerSettings{MinRequests: 1})
+	cb.now = clock.now
+	assert.Nil(t, cb.Allow())
+	cb.Record(io.EOF, time.Millisecond)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// the probe of a pipeline that could not be flushed is a failure
+	clock.t = clock.t.Add(5 * time.Second)
+	conn := &breakerConn{Conn: flushErrorConn{errorConn{io.EOF}}, breaker: cb}
+	assert.Nil(t, conn.Send("FT.INFO", "idx"))
+	assert.Equal(t, io.EOF, conn.Flush())
+	assert.Empty(t, conn.pending)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// the probe of a pipeline closed before its reply is released
+	clock.t = clock.t.Add(5 * time.Second)
+	conn = &breakerConn{Conn: flushErrorConn{errorConn{io.EOF}}, breaker: cb}
+	assert.Nil(t, conn.Send("FT.INFO", "idx"))
+	assert.Nil(t, conn.Close())
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	assert.Nil(t, cb.Allow())
+	cb.Record(nil, time.Millisecond)
+	assert.Equal(t, CircuitClosed, cb.State())
+}
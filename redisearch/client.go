@@ -2,6 +2,7 @@ package redisearch
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"reflect"
 	"strconv"
@@ -95,7 +96,8 @@ func (i *Client) Index(docs ...Document) error {
 }
 
 // Search searches the index for the given query, and returns documents,
-// the total number of results, or an error if something went wrong
+// the total number of results, or an error if something went wrong.
+// The RESP3 warnings of the reply are discarded, use Run to get them.
 func (i *Client) Search(q *Query) (docs []Document, total int, err error) {
 	conn := i.pool.Get()
 	defer conn.Close()
@@ -103,11 +105,27 @@ func (i *Client) Search(q *Query) (docs []Document, total int, err error) {
 	args := redis.Args{i.name}
 	args = append(args, q.serialize()...)
 
-	res, err := redis.Values(conn.Do("FT.SEARCH", args...))
+	res, err := conn.Do("FT.SEARCH", args...)
 	if err != nil {
 		return
 	}
+	return processSearchReply(q, res)
+}
 
+// internal function
+// processSearchReply converts a FT.SEARCH reply to documents and the total number of results
+func processSearchReply(q *Query, reply interface{}) (docs []Document, total int, err error) {
+	if m, ok := reply.(RESP3Map); ok {
+		return processSearchMapReply(m)
+	}
+	res, err := redis.Values(reply, nil)
+	if err != nil {
+		return
+	}
+	if len(res) == 0 {
+		err = errors.New("processSearchReply: empty reply")
+		return
+	}
 	if total, err = redis.Int(res[0], nil); err != nil {
 		return
 	}
@@ -143,6 +161,29 @@ func (i *Client) Search(q *Query) (docs []Document, total int, err error) {
 	return
 }
 
+// internal function
+// processSearchMapReply converts a RESP3 FT.SEARCH reply
+func processSearchMapReply(m RESP3Map) (docs []Document, total int, err error) {
+	value, _ := m.Get("total_results")
+	if total, err = redis.Int(value, nil); err != nil {
+		return
+	}
+	value, _ = m.Get("results")
+	results, err := redis.Values(value, nil)
+	if err != nil {
+		return
+	}
+	docs = make([]Document, 0, len(results))
+	for _, result := range results {
+		if d, e := loadDocumentMap(result); e == nil {
+			docs = append(docs, d)
+		} else {
+			log.Print("Error parsing doc: ", e)
+		}
+	}
+	return
+}
+
 // AliasAdd adds an alias to an index.
 // Indexes can have more than one alias, though an alias cannot refer to another alias.
 func (i *Client) AliasAdd(name string) (err error) {
@@ -212,7 +253,20 @@ func (i *Client) SpellCheck(q *Query, s *SpellCheckOptions) (suggs []MisspelledT
 	args = append(args, q.serialize()...)
 	args = append(args, s.serialize()...)
 
-	res, err := redis.Values(conn.Do("FT.SPELLCHECK", args...))
+	res, err := conn.Do("FT.SPELLCHECK", args...)
+	if err != nil {
+		return
+	}
+	return processSpellCheckReply(res)
+}
+
+// internal function
+// processSpellCheckReply converts a FT.SPELLCHECK reply to the list of misspelled terms
+func processSpellCheckReply(reply interface{}) (suggs []MisspelledTerm, total int, err error) {
+	if m, ok := reply.(RESP3Map); ok {
+		return processSpellCheckMapReply(m)
+	}
+	res, err := redis.Values(reply, nil)
 	if err != nil {
 		return
 	}
@@ -245,66 +299,104 @@ func (i *Client) SpellCheck(q *Query, s *SpellCheckOptions) (suggs []MisspelledT
 	return
 }
 
+// internal function
+// processSpellCheckMapReply converts a RESP3 FT.SPELLCHECK reply, where each misspelled term maps
+// to an array of single entry suggestion -> score maps
+func processSpellCheckMapReply(m RESP3Map) (suggs []MisspelledTerm, total int, err error) {
+	suggs = make([]MisspelledTerm, 0)
+	value, _ := m.Get("results")
+	terms, err := replyValues(value, nil)
+	if err != nil {
+		return
+	}
+	for i := 0; i+1 < len(terms); i += 2 {
+		term, e := redis.String(terms[i], nil)
+		if e != nil {
+			log.Print("Error parsing misspelled suggestion: ", e)
+			continue
+		}
+		missT := NewMisspelledTerm(term)
+		lst, _ := redis.Values(terms[i+1], nil)
+		for _, sugg := range lst {
+			kv, e := replyValues(sugg, nil)
+			if e != nil || len(kv) != 2 {
+				log.Print("Error parsing misspelled suggestion of term ", term)
+				continue
+			}
+			suggestion, e1 := redis.String(kv[0], nil)
+			score, e2 := redis.Float64(kv[1], nil)
+			if e1 != nil || e2 != nil {
+				log.Print("Error parsing misspelled suggestion of term ", term)
+				continue
+			}
+			missT.MisspelledSuggestionList = append(missT.MisspelledSuggestionList, NewMisspelledSuggestion(suggestion, float32(score)))
+		}
+		suggs = append(suggs, missT)
+		if missT.Len() > 0 {
+			total++
+		}
+	}
+	return
+}
+
 // Deprecated: Use AggregateQuery() instead.
 func (i *Client) Aggregate(q *AggregateQuery) (aggregateReply [][]string, total int, err error) {
 	res, err := i.aggregate(q)
+	if err != nil {
+		return
+	}
 
 	// has no cursor
 	if !q.WithCursor {
 		total, aggregateReply, err = processAggReply(res)
 		// has cursor
 	} else {
-		var partialResults, err = redis.Values(res[0], nil)
-		if err != nil {
+		values, err := redis.Values(res, nil)
+		if err != nil || len(values) != 2 {
 			return aggregateReply, total, err
 		}
-		q.Cursor.Id, err = redis.Int(res[1], nil)
+		q.Cursor.Id, err = redis.Int(values[1], nil)
 		if err != nil {
 			return aggregateReply, total, err
 		}
-		total, aggregateReply, err = processAggReply(partialResults)
+		total, aggregateReply, err = processAggReply(values[0])
 	}
 	return
 }
 
 // AggregateQuery replaces the Aggregate() function. The reply is slice of maps, with values of either string or []string.
+// The RESP3 warnings of the reply are discarded, use Run with NewAggregateRequest to get them.
 func (i *Client) AggregateQuery(q *AggregateQuery) (total int, aggregateReply []map[string]interface{}, err error) {
 	res, err := i.aggregate(q)
-
-	// has no cursor
-	if !q.WithCursor {
-		total, aggregateReply, err = processAggQueryReply(res)
-		// has cursor
-	} else {
-		var partialResults, err = redis.Values(res[0], nil)
-		if err != nil {
-			return total, aggregateReply, err
-		}
-		q.Cursor.Id, err = redis.Int(res[1], nil)
-		if err != nil {
-			return total, aggregateReply, err
-		}
-		total, aggregateReply, err = processAggQueryReply(partialResults)
+	if err != nil {
+		return
 	}
-	return
+	return q.processReply(res)
 }
 
-func (i *Client) aggregate(q *AggregateQuery) (res []interface{}, err error) {
+func (i *Client) aggregate(q *AggregateQuery) (res interface{}, err error) {
 	conn := i.pool.Get()
 	defer conn.Close()
-	validCursor := q.CursorHasResults()
-	if !validCursor {
-		args := redis.Args{i.name}
-		args = append(args, q.Serialize()...)
-		res, err = redis.Values(conn.Do("FT.AGGREGATE", args...))
-	} else {
-		args := redis.Args{"READ", i.name, q.Cursor.Id}
-		res, err = redis.Values(conn.Do("FT.CURSOR", args...))
-	}
+	cmd, args, err := i.aggregateArgs(q)
 	if err != nil {
 		return
 	}
-	return
+	return conn.Do(cmd, args...)
+}
+
+// internal method
+// aggregateArgs returns the FT.AGGREGATE command for the query, or the FT.CURSOR READ command
+// if the query has a cursor with pending results
+func (i *Client) aggregateArgs(q *AggregateQuery) (cmd string, args []interface{}, err error) {
+	if q.CursorHasResults() {
+		return "FT.CURSOR", redis.Args{"READ", i.name, q.Cursor.Id}, nil
+	}
+	if err = q.Validate(); err != nil {
+		return
+	}
+	args = redis.Args{i.name}
+	args = append(args, q.Serialize()...)
+	return "FT.AGGREGATE", args, nil
 }
 
 // Get - Returns the full contents of a document
@@ -317,7 +409,7 @@ func (i *Client) Get(docId string) (doc *Document, err error) {
 	reply, err = conn.Do("FT.GET", args...)
 	if reply != nil {
 		var array_reply []interface{}
-		array_reply, err = redis.Values(reply, err)
+		array_reply, err = replyValues(reply, err)
 		if err != nil {
 			return
 		}
@@ -342,7 +434,7 @@ func (i *Client) MultiGet(documentIds []string) (docs []*Document, err error) {
 	reply, err = conn.Do("FT.MGET", args...)
 	if reply != nil {
 		var array_reply []interface{}
-		array_reply, err = redis.Values(reply, err)
+		array_reply, err = replyValues(reply, err)
 		if err != nil {
 			return
 		}
@@ -350,7 +442,7 @@ func (i *Client) MultiGet(documentIds []string) (docs []*Document, err error) {
 
 			if array_reply[i] != nil {
 				var innerArray []interface{}
-				innerArray, err = redis.Values(array_reply[i], nil)
+				innerArray, err = replyValues(array_reply[i], nil)
 				if err != nil {
 					return
 				}
@@ -378,6 +470,44 @@ func (i *Client) Explain(q *Query) (string, error) {
 	return redis.String(conn.Do("FT.EXPLAIN", args...))
 }
 
+// Profile runs the query with FT.PROFILE, and returns its documents and total number of results like Search, along
+// with the profile of the execution, whose bulk strings are converted to strings. If limited is true, the
+// profile does not detail the iterators of the reduced queries.
+func (i *Client) Profile(q *Query, limited bool) (docs []Document, total int, profile interface{}, err error) {
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	args := redis.Args{i.name, "SEARCH"}
+	if limited {
+		args = append(args, "LIMITED")
+	}
+	args = append(args, "QUERY")
+	args = append(args, q.serialize()...)
+
+	res, err := conn.Do("FT.PROFILE", args...)
+	if err != nil {
+		return
+	}
+	var results interface{}
+	if m, ok := res.(RESP3Map); ok {
+		results, _ = m.Get("Results")
+		profile, _ = m.Get("Profile")
+	} else {
+		var values []interface{}
+		if values, err = redis.Values(res, nil); err != nil {
+			return
+		}
+		if len(values) != 2 {
+			err = fmt.Errorf("FT.PROFILE: unexpected reply of %d elements", len(values))
+			return
+		}
+		results, profile = values[0], values[1]
+	}
+	profile = rawValue(profile)
+	docs, total, err = processSearchReply(q, results)
+	return
+}
+
 // Drop deletes the index and all the keys associated with it.
 func (i *Client) Drop() error {
 	conn := i.pool.Get()
@@ -431,7 +561,13 @@ func (i *Client) delDoc(docId string, deleteDocument bool) (err error) {
 
 // Internal method to be used by Info()
 func (info *IndexInfo) setTarget(key string, value interface{}) error {
-	v := reflect.ValueOf(info).Elem()
+	return setStructTarget(info, key, value)
+}
+
+// internal function
+// setStructTarget sets the field of the struct pointed by target whose redis tag matches the key
+func setStructTarget(target interface{}, key string, value interface{}) error {
+	v := reflect.ValueOf(target).Elem()
 	for i := 0; i < v.NumField(); i++ {
 		tag := v.Type().Field(i).Tag.Get("redis")
 		if tag == key {
@@ -462,6 +598,133 @@ func (info *IndexInfo) setTarget(key string, value interface{}) error {
 	return errors.New("setTarget: No handler defined for :" + key)
 }
 
+// internal function
+// loadStruct sets the fields of the struct pointed by target from a flat key/value array
+func loadStruct(target interface{}, value interface{}) error {
+	values, err := replyValues(value, nil)
+	if err != nil {
+		return err
+	}
+	for ii := 0; ii+1 < len(values); ii += 2 {
+		key, _ := redis.String(values[ii], nil)
+		if err := setStructTarget(target, key, values[ii+1]); err != nil {
+			setRawTarget(target, key, values[ii+1])
+		}
+	}
+	return nil
+}
+
+// internal function
+// setRawTarget keeps a key without a matching field in the Raw map of the struct pointed by target
+func setRawTarget(target interface{}, key string, value interface{}) {
+	raw := reflect.ValueOf(target).Elem().FieldByName("Raw")
+	if !raw.IsValid() {
+		return
+	}
+	if raw.IsNil() {
+		raw.Set(reflect.ValueOf(map[string]interface{}{}))
+	}
+	v := reflect.ValueOf(rawValue(value))
+	if !v.IsValid() {
+		// nil reply
+		v = reflect.Zero(raw.Type().Elem())
+	}
+	raw.SetMapIndex(reflect.ValueOf(key), v)
+}
+
+// internal function
+// loadFieldStatistics converts the "field statistics" section of FT.INFO
+func loadFieldStatistics(value interface{}) ([]FieldStatistics, error) {
+	entries, err := redis.Values(value, nil)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]FieldStatistics, 0, len(entries))
+	for _, entry := range entries {
+		values, err := replyValues(entry, nil)
+		if err != nil {
+			return nil, err
+		}
+		fs := FieldStatistics{}
+		for ii := 0; ii+1 < len(values); ii += 2 {
+			key, _ := redis.String(values[ii], nil)
+			if key == "Index Errors" {
+				if err := loadStruct(&fs.IndexErrors, values[ii+1]); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := setStructTarget(&fs, key, values[ii+1]); err != nil {
+				setRawTarget(&fs, key, values[ii+1])
+			}
+		}
+		stats = append(stats, fs)
+	}
+	return stats, nil
+}
+
+// attributeFlags are the attribute options reported by FT.INFO without a value
+var attributeFlags = []string{"SORTABLE", "UNF", "NOSTEM", "NOINDEX", "CASESENSITIVE", "WITHSUFFIXTRIE", "INDEXEMPTY", "INDEXMISSING"}
+
+// internal function
+// loadAttributes converts the "attributes" section of FT.INFO
+func loadAttributes(value interface{}) ([]AttributeInfo, error) {
+	entries, err := redis.Values(value, nil)
+	if err != nil {
+		return nil, err
+	}
+	attributes := make([]AttributeInfo, 0, len(entries))
+	for _, entry := range entries {
+		values, err := attributeSpec(entry)
+		if err != nil {
+			return nil, err
+		}
+		attr := AttributeInfo{Options: map[string]string{}, Flags: []string{}}
+		for ii := 0; ii < len(values); ii++ {
+			key, err := redis.String(values[ii], nil)
+			if err != nil {
+				continue
+			}
+			if sliceIndex(attributeFlags, strings.ToUpper(key)) != -1 || ii+1 == len(values) {
+				attr.Flags = append(attr.Flags, key)
+				continue
+			}
+			if setStructTarget(&attr, key, values[ii+1]) != nil {
+				if s, err := redis.String(values[ii+1], nil); err == nil {
+					attr.Options[key] = s
+				}
+			}
+			ii++
+		}
+		attributes = append(attributes, attr)
+	}
+	return attributes, nil
+}
+
+// internal function
+// rawValue converts the bulk strings of a reply to strings, recursively
+func rawValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case RESP3Map:
+		ret := make(map[string]interface{}, len(v)/2)
+		for i := 0; i+1 < len(v); i += 2 {
+			key, _ := redis.String(v[i], nil)
+			ret[key] = rawValue(v[i+1])
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, elem := range v {
+			ret[i] = rawValue(elem)
+		}
+		return ret
+	default:
+		return v
+	}
+}
+
 func sliceIndex(haystack []string, needle string) int {
 	for pos, elem := range haystack {
 		if elem == needle {
@@ -471,6 +734,26 @@ func sliceIndex(haystack []string, needle string) int {
 	return -1
 }
 
+// internal function
+// attributeSpec returns the flat array describing an attribute of FT.INFO.
+// With RESP3 the attribute is a map whose flags are grouped in a "flags" array, they are appended to the array.
+func attributeSpec(value interface{}) ([]interface{}, error) {
+	m, ok := value.(RESP3Map)
+	if !ok {
+		return redis.Values(value, nil)
+	}
+	spec := make([]interface{}, 0, len(m))
+	var flags []interface{}
+	for i := 0; i+1 < len(m); i += 2 {
+		if key, _ := redis.String(m[i], nil); key == "flags" {
+			flags, _ = redis.Values(m[i+1], nil)
+			continue
+		}
+		spec = append(spec, m[i], m[i+1])
+	}
+	return append(spec, flags...), nil
+}
+
 func (info *IndexInfo) loadSchema(values []interface{}, options []string) {
 	// Values are a list of fields
 	scOptions := Options{}
@@ -490,7 +773,7 @@ func (info *IndexInfo) loadSchema(values []interface{}, options []string) {
 		// if !isArr {
 		// 	panic("Value is not an array of strings!")
 		// }
-		rawSpec, err := redis.Values(specTmp, nil)
+		rawSpec, err := attributeSpec(specTmp)
 		if err != nil {
 			log.Printf("Warning: Couldn't read schema. %s\n", err.Error())
 			continue
@@ -584,54 +867,152 @@ func (info *IndexInfo) loadSchema(values []interface{}, options []string) {
 				weight64, _ := strconv.ParseFloat(weightString, 32)
 				tfOptions.Weight = float32(weight64)
 			}
+			if pIdx := sliceIndex(options, "PHONETIC"); pIdx != -1 && pIdx+1 < len(options) {
+				tfOptions.PhoneticMatcher = PhoneticMatcherType(options[pIdx+1])
+			}
 			f.Options = tfOptions
 			f.Sortable = tfOptions.Sortable
 		case "VECTOR":
 			f.Type = VectorField
-			f.Options = VectorFieldOptions{}
+			f.Options = loadVectorFieldOptions(options[3:])
 		}
 		sc = sc.AddField(f)
 	}
 	info.Schema = *sc
 }
 
+// internal function
+// loadVectorFieldOptions converts the attributes of a vector field reported by FT.INFO, e.g.
+// algorithm FLAT data_type FLOAT32 dim 2 distance_metric L2, to the options of FT.CREATE
+func loadVectorFieldOptions(attributes []string) VectorFieldOptions {
+	opts := VectorFieldOptions{Attributes: map[string]interface{}{}}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		key, value := strings.ToUpper(attributes[i]), attributes[i+1]
+		switch key {
+		case "ALGORITHM":
+			opts.Algorithm = algorithm(strings.ToUpper(value))
+		case "DATA_TYPE":
+			opts.Attributes["TYPE"] = value
+		default:
+			opts.Attributes[key] = value
+		}
+	}
+	return opts
+}
+
 // Info - Get information about the index. This can also be used to check if the
 // index exists
 func (i *Client) Info() (*IndexInfo, error) {
 	conn := i.pool.Get()
 	defer conn.Close()
 
-	res, err := redis.Values(conn.Do("FT.INFO", i.name))
+	res, err := replyValues(conn.Do("FT.INFO", i.name))
 	if err != nil {
 		return nil, err
 	}
+	return loadIndexInfo(res)
+}
 
-	ret := IndexInfo{}
+// internal function
+// loadIndexInfo converts a FT.INFO reply
+func loadIndexInfo(res []interface{}) (*IndexInfo, error) {
+	ret := IndexInfo{Raw: map[string]interface{}{}}
 	var schemaAttributes []interface{}
 	var indexOptions []string
+	var stopwords []string
 
 	// Iterate over the values
-	for ii := 0; ii < len(res); ii += 2 {
+	for ii := 0; ii+1 < len(res); ii += 2 {
 		key, _ := redis.String(res[ii], nil)
-		if err := ret.setTarget(key, res[ii+1]); err == nil {
+		value := res[ii+1]
+		if err := ret.setTarget(key, value); err == nil {
 			continue
 		}
 
+		var err error
 		switch key {
 		case "index_options":
-			indexOptions, _ = redis.Strings(res[ii+1], nil)
+			indexOptions, _ = redis.Strings(value, nil)
 		case "fields", "attributes":
-			schemaAttributes, _ = redis.Values(res[ii+1], nil)
+			schemaAttributes, _ = replyValues(value, nil)
+			if key == "attributes" {
+				ret.Attributes, err = loadAttributes(value)
+			}
+		case "gc_stats":
+			err = loadStruct(&ret.GCStats, value)
+		case "cursor_stats":
+			err = loadStruct(&ret.CursorStats, value)
+		case "dialect_stats":
+			err = loadStruct(&ret.DialectStats, value)
+		case "Index Errors":
+			err = loadStruct(&ret.IndexErrors, value)
+		case "field statistics":
+			ret.FieldStatistics, err = loadFieldStatistics(value)
+		case "index_definition":
+			ret.Raw[key] = rawValue(value)
+			ret.Definition = loadIndexDefinition(ret.Raw[key])
+		case "stopwords_list":
+			stopwords, _ = redis.Strings(value, nil)
+		default:
+			ret.Raw[key] = rawValue(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Info: could not parse %s: %v", key, err)
 		}
 	}
 
 	if schemaAttributes != nil {
 		ret.loadSchema(schemaAttributes, indexOptions)
+		if stopwords != nil {
+			ret.Schema.Options.Stopwords = stopwords
+		}
 	}
 
 	return &ret, nil
 }
 
+// internal function
+// loadIndexDefinition converts the index_definition section of FT.INFO, whose bulk strings are already converted
+func loadIndexDefinition(value interface{}) *IndexDefinition {
+	values := make(map[string]interface{})
+	switch v := value.(type) {
+	case map[string]interface{}:
+		values = v
+	case []interface{}:
+		for i := 0; i+1 < len(v); i += 2 {
+			if key, ok := v[i].(string); ok {
+				values[key] = v[i+1]
+			}
+		}
+	default:
+		return nil
+	}
+	str := func(key string) string {
+		s, _ := redis.String(values[key], nil)
+		return s
+	}
+	def := NewIndexDefinition()
+	if on := str("key_type"); on != "" {
+		def.IndexOn = on
+	}
+	if prefixes, ok := values["prefixes"].([]interface{}); ok {
+		for _, prefix := range prefixes {
+			if p, ok := prefix.(string); ok {
+				def.Prefix = append(def.Prefix, p)
+			}
+		}
+	}
+	def.FilterExpression = str("filter")
+	def.Language = str("default_language")
+	def.LanguageField = str("language_field")
+	if score, err := redis.Float64(values["default_score"], nil); err == nil {
+		def.Score = score
+	}
+	def.ScoreField = str("score_field")
+	def.PayloadField = str("payload_field")
+	return def
+}
+
 // Set runtime configuration option
 func (i *Client) SetConfig(option string, value string) (string, error) {
 	conn := i.pool.Get()
@@ -647,12 +1028,23 @@ func (i *Client) GetConfig(option string) (map[string]string, error) {
 	defer conn.Close()
 
 	args := redis.Args{"GET", option}
-	values, err := redis.Values(conn.Do("FT.CONFIG", args...))
+	reply, err := conn.Do("FT.CONFIG", args...)
 	if err != nil {
 		return nil, err
 	}
-
 	m := make(map[string]string)
+	if kv, ok := reply.(RESP3Map); ok {
+		for i := 0; i+1 < len(kv); i += 2 {
+			key, _ := redis.String(kv[i], nil)
+			m[key], _ = redis.String(kv[i+1], nil)
+		}
+		return m, nil
+	}
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	valLen := len(values)
 	for i := 0; i < valLen; i++ {
 		kvs, _ := redis.Strings(values[i], nil)
@@ -697,7 +1089,7 @@ func (i *Client) SynDump(indexName string) (map[string][]int64, error) {
 	defer conn.Close()
 
 	args := redis.Args{indexName}
-	values, err := redis.Values(conn.Do("FT.SYNDUMP", args...))
+	values, err := replyValues(conn.Do("FT.SYNDUMP", args...))
 	if err != nil {
 		return nil, err
 	}
@@ -709,12 +1101,15 @@ func (i *Client) SynDump(indexName string) (map[string][]int64, error) {
 
 	m := make(map[string][]int64, valLen/2)
 	for i := 0; i < valLen; i += 2 {
-		key := values[i].([]byte)
+		key, err := redis.String(values[i], nil)
+		if err != nil {
+			return nil, err
+		}
 		gids, err := redis.Int64s(values[i+1], nil)
 		if err != nil {
 			return nil, err
 		}
-		m[string(key)] = gids
+		m[key] = gids
 	}
 	return m, nil
 }
@@ -623,6 +623,7 @@ func TestClient_GetRediSearchVersion(t *testing.T) {
 }
 
 func TestClient_CreateIndexWithIndexDefinitionJSON(t *testing.T) {
+	skipInMemory(t, "ON JSON")
 	c := createClient("index-definition-test")
 	version, err := c.getRediSearchVersion()
 	assert.Nil(t, err)
@@ -972,6 +973,7 @@ func TestClient_CreateIndex(t *testing.T) {
 }
 
 func TestClient_CreateJsonIndex(t *testing.T) {
+	skipInMemory(t, "ON JSON")
 	c := createClient("create-json-index")
 	flush(c)
 	version, _ := c.getRediSearchVersion()
@@ -1212,8 +1214,12 @@ func TestClient_InfoSchemaFields(t *testing.T) {
 		Type:     VectorField,
 		Sortable: false,
 		Options: VectorFieldOptions{
-			Algorithm:  "",
-			Attributes: nil,
+			Algorithm: Flat,
+			Attributes: map[string]interface{}{
+				"TYPE":            "FLOAT32",
+				"DIM":             "2",
+				"DISTANCE_METRIC": "L2",
+			},
 		},
 	}
 	assert.True(t, reflect.DeepEqual(expVectorField, info.Schema.Fields[1]))
@@ -1241,7 +1247,7 @@ func TestClient_InfoFieldsTest(t *testing.T) {
 	assert.Equal(t,
 		[]Field(
 			[]Field{
-				Field{Name: "text", Type: 0, Sortable: true, Options: TextFieldOptions{Weight: 1, Sortable: true, NoStem: false, NoIndex: false, PhoneticMatcher: "", As: "text"}},
+				Field{Name: "text", Type: 0, Sortable: true, Options: TextFieldOptions{Weight: 1, Sortable: true, NoStem: false, NoIndex: false, PhoneticMatcher: PhoneticDoubleMetaphoneEnglish, As: "text"}},
 				Field{Name: "geo", Type: 2, Sortable: false, Options: GeoFieldOptions{As: "geo", NoIndex: false}},
 				Field{Name: "numeric", Type: 1, Sortable: false, Options: NumericFieldOptions{Sortable: false, NoIndex: false, As: "numeric"}},
 				Field{Name: "alias_type", Type: 0, Sortable: true, Options: TextFieldOptions{Weight: 1, Sortable: true, NoStem: true, NoIndex: true, PhoneticMatcher: "", As: "type"}},
@@ -1250,3 +1256,67 @@ func TestClient_InfoFieldsTest(t *testing.T) {
 			}),
 		info.Schema.Fields)
 }
+
+func Test_loadIndexInfo(t *testing.T) {
+	b := func(s string) []byte { return []byte(s) }
+	reply := []interface{}{
+		b("index_name"), b("idx"),
+		b("index_options"), []interface{}{},
+		b("attributes"), []interface{}{
+			[]interface{}{b("identifier"), b("title"), b("attribute"), b("title"), b("type"), b("TEXT"), b("WEIGHT"), b("1"), b("SORTABLE"), b("NOSTEM")},
+			[]interface{}{b("identifier"), b("$.tags"), b("attribute"), b("tags"), b("type"), b("TAG"), b("SEPARATOR"), b(",")},
+		},
+		b("num_docs"), b("3"),
+		b("vector_index_sz_mb"), b("0.5"),
+		b("total_indexing_time"), b("1.25"),
+		b("number_of_uses"), int64(7),
+		b("cleaning"), int64(0),
+		b("gc_stats"), []interface{}{b("bytes_collected"), b("12"), b("total_cycles"), b("2"), b("average_cycle_time_ms"), b("0.5"), b("gc_future_stat"), b("3")},
+		b("cursor_stats"), []interface{}{b("global_idle"), int64(1), b("global_total"), int64(2), b("index_capacity"), int64(128), b("index_total"), int64(0), b("index_future"), nil},
+		b("dialect_stats"), []interface{}{b("dialect_1"), int64(4), b("dialect_2"), int64(1), b("dialect_3"), int64(0), b("dialect_4"), int64(0), b("dialect_5"), int64(2)},
+		b("Index Errors"), []interface{}{b("indexing failures"), int64(1), b("last indexing error"), b("Invalid numeric value"), b("last indexing error key"), b("doc:1"), b("background indexing status"), b("OK")},
+		b("field statistics"), []interface{}{
+			[]interface{}{b("identifier"), b("title"), b("attribute"), b("title"), b("field_future"), []interface{}{b("x")}, b("Index Errors"),
+				[]interface{}{b("indexing failures"), int64(0), b("last indexing error"), b("N/A"), b("last indexing error key"), b("N/A")}},
+		},
+		b("future_section"), []interface{}{b("a"), int64(1)},
+	}
+	info, err := loadIndexInfo(reply)
+	assert.Nil(t, err)
+	assert.Equal(t, "idx", info.Name)
+	assert.Equal(t, uint64(3), info.DocCount)
+	assert.Equal(t, 0.5, info.VectorIndexSizeMB)
+	assert.Equal(t, 1.25, info.TotalIndexingTime)
+	assert.Equal(t, uint64(7), info.NumberOfUses)
+	assert.False(t, info.IsCleaning)
+	assert.Equal(t, GCStats{BytesCollected: 12, TotalCycles: 2, AverageCycleTimeMs: 0.5, Raw: map[string]interface{}{"gc_future_stat": "3"}}, info.GCStats)
+	assert.Equal(t, CursorStats{GlobalIdle: 1, GlobalTotal: 2, IndexCapacity: 128, Raw: map[string]interface{}{"index_future": nil}}, info.CursorStats)
+	assert.Equal(t, DialectStats{Dialect1: 4, Dialect2: 1, Raw: map[string]interface{}{"dialect_5": int64(2)}}, info.DialectStats)
+	assert.Equal(t, IndexErrors{IndexingFailures: 1, LastIndexingError: "Invalid numeric value", LastIndexingErrorKey: "doc:1",
+		Raw: map[string]interface{}{"background indexing status": "OK"}}, info.IndexErrors)
+	// unknown keys of the nested sections are kept in their Raw map
+	assert.Equal(t, []FieldStatistics{{Identifier: "title", Attribute: "title",
+		IndexErrors: IndexErrors{LastIndexingError: "N/A", LastIndexingErrorKey: "N/A"},
+		Raw:         map[string]interface{}{"field_future": []interface{}{"x"}}}}, info.FieldStatistics)
+	assert.Equal(t, []AttributeInfo{
+		{Identifier: "title", Attribute: "title", Type: "TEXT", Options: map[string]string{"WEIGHT": "1"}, Flags: []string{"SORTABLE", "NOSTEM"}},
+		{Identifier: "$.tags", Attribute: "tags", Type: "TAG", Options: map[string]string{"SEPARATOR": ","}, Flags: []string{}},
+	}, info.Attributes)
+	assert.Equal(t, 2, len(info.Schema.Fields))
+	assert.Equal(t, []interface{}{"a", int64(1)}, info.Raw["future_section"])
+}
+
+func TestClient_Profile(t *testing.T) {
+	b := func(s string) []byte { return []byte(s) }
+	exec := &fakeExecutor{replies: []Reply{{Value: []interface{}{
+		[]interface{}{int64(1), b("doc1"), []interface{}{b("title"), b("hello")}},
+		[]interface{}{[]interface{}{b("Total profile time"), b("0.5")}},
+	}}}}
+	c := NewClientFromExecutor(exec, "idx")
+	docs, total, profile, err := c.Profile(NewQuery("hello").SetReturnFields("title"), true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []Document{{Id: "doc1", Score: 1, Properties: map[string]interface{}{"title": "hello"}}}, docs)
+	assert.Equal(t, []interface{}{[]interface{}{"Total profile time", "0.5"}}, profile)
+	assert.Equal(t, []interface{}{"idx", "SEARCH", "LIMITED", "QUERY", "hello", "RETURN", 1, "title"}, exec.cmds[0].Args)
+}
@@ -0,0 +1,221 @@
+package redisearch
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SearchConfig is the typed runtime configuration of RediSearch, as managed by FT.CONFIG.
+// A nil field means the option was not reported by the server, or, in a desired configuration,
+// that the option should be left as it is.
+type SearchConfig struct {
+	Timeout                      *int64  `redis:"TIMEOUT"`
+	OnTimeout                    *string `redis:"ON_TIMEOUT"`
+	MinPrefix                    *int64  `redis:"MINPREFIX"`
+	MinStemLen                   *int64  `redis:"MINSTEMLEN"`
+	MaxExpansions                *int64  `redis:"MAXEXPANSIONS"`
+	MaxPrefixExpansions          *int64  `redis:"MAXPREFIXEXPANSIONS"`
+	MaxDocTableSize              *int64  `redis:"MAXDOCTABLESIZE"`
+	MaxSearchResults             *int64  `redis:"MAXSEARCHRESULTS"`
+	MaxAggregateResults          *int64  `redis:"MAXAGGREGATERESULTS"`
+	DefaultDialect               *int64  `redis:"DEFAULT_DIALECT"`
+	UnionIteratorHeap            *int64  `redis:"UNION_ITERATOR_HEAP"`
+	MinPhoneticTermLen           *int64  `redis:"MIN_PHONETIC_TERM_LEN"`
+	GCPolicy                     *string `redis:"GC_POLICY"`
+	GCScanSize                   *int64  `redis:"GCSCANSIZE"`
+	ForkGCRunInterval            *int64  `redis:"FORK_GC_RUN_INTERVAL"`
+	ForkGCRetryInterval          *int64  `redis:"FORK_GC_RETRY_INTERVAL"`
+	ForkGCCleanThreshold         *int64  `redis:"FORK_GC_CLEAN_THRESHOLD"`
+	ForkGCCleanNumericEmptyNodes *bool   `redis:"FORK_GC_CLEAN_NUMERIC_EMPTY_NODES"`
+	CursorMaxIdle                *int64  `redis:"CURSOR_MAX_IDLE"`
+	NoGC                         *bool   `redis:"NOGC"`
+	// Other holds the options that are not mapped to a field, or whose value could not be parsed
+	Other map[string]string
+}
+
+// ConfigInt returns a pointer to the given value, to be used as a SearchConfig numeric option
+func ConfigInt(value int64) *int64 {
+	return &value
+}
+
+// ConfigString returns a pointer to the given value, to be used as a SearchConfig string option
+func ConfigString(value string) *string {
+	return &value
+}
+
+// ConfigBool returns a pointer to the given value, to be used as a SearchConfig boolean option
+func ConfigBool(value bool) *bool {
+	return &value
+}
+
+// ConfigChange is a single option that differs between the current and the desired configuration
+type ConfigChange struct {
+	Option  string
+	Current string
+	Desired string
+}
+
+// ConfigError maps each option rejected by FT.CONFIG SET to the server error
+type ConfigError map[string]error
+
+// Error returns the rejected options and their errors, sorted by option name
+func (e ConfigError) Error() string {
+	options := make([]string, 0, len(e))
+	for option := range e {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	msgs := make([]string, len(options))
+	for i, option := range options {
+		msgs[i] = fmt.Sprintf("%s: %s", option, e[option].Error())
+	}
+	return "FT.CONFIG SET rejected " + strings.Join(msgs, "; ")
+}
+
+// NewSearchConfig creates a SearchConfig from the option/value pairs returned by GetConfig
+func NewSearchConfig(values map[string]string) *SearchConfig {
+	c := &SearchConfig{}
+	for option, value := range values {
+		if !c.set(option, value) {
+			if c.Other == nil {
+				c.Other = make(map[string]string)
+			}
+			c.Other[option] = value
+		}
+	}
+	return c
+}
+
+// Internal method to be used by NewSearchConfig()
+func (c *SearchConfig) set(option string, value string) bool {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("redis") != strings.ToUpper(option) {
+			continue
+		}
+		target := v.Field(i)
+		switch target.Type().Elem().Kind() {
+		case reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return false
+			}
+			target.Set(reflect.ValueOf(&n))
+		case reflect.String:
+			s := value
+			target.Set(reflect.ValueOf(&s))
+		case reflect.Bool:
+			b, err := parseConfigBool(value)
+			if err != nil {
+				return false
+			}
+			target.Set(reflect.ValueOf(&b))
+		default:
+			panic("Tag set without handler")
+		}
+		return true
+	}
+	return false
+}
+
+func parseConfigBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean value %s", value)
+}
+
+// Values returns the options that are set, formatted as FT.CONFIG values
+func (c *SearchConfig) Values() map[string]string {
+	values := make(map[string]string, len(c.Other))
+	for option, value := range c.Other {
+		values[option] = value
+	}
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("redis")
+		field := v.Field(i)
+		if tag == "" || field.IsNil() {
+			continue
+		}
+		values[tag] = fmt.Sprint(field.Elem().Interface())
+	}
+	return values
+}
+
+// Diff returns the options set in desired that have a different value in c, sorted by option name
+func (c *SearchConfig) Diff(desired *SearchConfig) []ConfigChange {
+	current := c.Values()
+	wanted := desired.Values()
+	changes := make([]ConfigChange, 0)
+	for option, value := range wanted {
+		if cur, ok := current[option]; !ok || cur != value {
+			changes = append(changes, ConfigChange{Option: option, Current: cur, Desired: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Option < changes[j].Option })
+	return changes
+}
+
+// GetSearchConfig returns the full runtime configuration, loaded with FT.CONFIG GET *
+func (i *Client) GetSearchConfig() (*SearchConfig, error) {
+	values, err := i.GetConfig("*")
+	if err != nil {
+		return nil, err
+	}
+	return NewSearchConfig(values), nil
+}
+
+// ApplySearchConfig sets all the options of desired that differ from the current configuration, with a single
+// pipeline of FT.CONFIG SET commands. It returns the changes that were applied. If the server rejected some
+// of the options, the error is a ConfigError with the rejected options.
+func (i *Client) ApplySearchConfig(desired *SearchConfig) (applied []ConfigChange, err error) {
+	current, err := i.GetSearchConfig()
+	if err != nil {
+		return nil, err
+	}
+	return applySearchConfig(i.pool, current.Diff(desired))
+}
+
+// internal function
+// applySearchConfig pipelines a FT.CONFIG SET command per change
+func applySearchConfig(pool ConnPool, changes []ConfigChange) (applied []ConfigChange, err error) {
+	applied = make([]ConfigChange, 0, len(changes))
+	if len(changes) == 0 {
+		return
+	}
+	conn := pool.Get()
+	defer conn.Close()
+
+	for _, change := range changes {
+		if err = conn.Send("FT.CONFIG", "SET", change.Option, change.Desired); err != nil {
+			return nil, err
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		return nil, err
+	}
+	var rejected ConfigError
+	for _, change := range changes {
+		if _, e := redis.String(conn.Receive()); e != nil {
+			if rejected == nil {
+				rejected = make(ConfigError)
+			}
+			rejected[change.Option] = e
+			continue
+		}
+		applied = append(applied, change)
+	}
+	if rejected != nil {
+		err = rejected
+	}
+	return
+}
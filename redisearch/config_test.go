@@ -0,0 +1,78 @@
+package redisearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSearchConfig(t *testing.T) {
+	c := NewSearchConfig(map[string]string{
+		"TIMEOUT":                           "500",
+		"ON_TIMEOUT":                        "return",
+		"DEFAULT_DIALECT":                   "1",
+		"FORK_GC_CLEAN_NUMERIC_EMPTY_NODES": "true",
+		"MAXSEARCHRESULTS":                  "unlimited",
+		"EXTLOAD":                           "",
+	})
+	assert.Equal(t, int64(500), *c.Timeout)
+	assert.Equal(t, "return", *c.OnTimeout)
+	assert.Equal(t, int64(1), *c.DefaultDialect)
+	assert.True(t, *c.ForkGCCleanNumericEmptyNodes)
+	assert.Nil(t, c.MaxSearchResults)
+	assert.Nil(t, c.GCPolicy)
+	assert.Equal(t, map[string]string{"MAXSEARCHRESULTS": "unlimited", "EXTLOAD": ""}, c.Other)
+}
+
+func TestSearchConfig_Values(t *testing.T) {
+	c := &SearchConfig{
+		Timeout:   ConfigInt(100),
+		GCPolicy:  ConfigString("fork"),
+		NoGC:      ConfigBool(false),
+		Other:     map[string]string{"WORKERS": "4"},
+		OnTimeout: nil,
+	}
+	assert.Equal(t, map[string]string{"TIMEOUT": "100", "GC_POLICY": "fork", "NOGC": "false", "WORKERS": "4"}, c.Values())
+}
+
+func TestSearchConfig_Diff(t *testing.T) {
+	current := NewSearchConfig(map[string]string{"TIMEOUT": "500", "ON_TIMEOUT": "return", "DEFAULT_DIALECT": "1"})
+	desired := &SearchConfig{
+		Timeout:        ConfigInt(500),
+		OnTimeout:      ConfigString("fail"),
+		DefaultDialect: ConfigInt(2),
+		CursorMaxIdle:  ConfigInt(1000),
+	}
+	assert.Equal(t, []ConfigChange{
+		{Option: "CURSOR_MAX_IDLE", Current: "", Desired: "1000"},
+		{Option: "DEFAULT_DIALECT", Current: "1", Desired: "2"},
+		{Option: "ON_TIMEOUT", Current: "return", Desired: "fail"},
+	}, current.Diff(desired))
+	assert.Equal(t, []ConfigChange{}, current.Diff(&SearchConfig{}))
+}
+
+func TestConfigError_Error(t *testing.T) {
+	err := ConfigError{"TIMEOUT": errors.New("Invalid value"), "NOGC": errors.New("Not modifiable at runtime")}
+	assert.Equal(t, "FT.CONFIG SET rejected NOGC: Not modifiable at runtime; TIMEOUT: Invalid value", err.Error())
+}
+
+func TestClient_ApplySearchConfig(t *testing.T) {
+	c := createClient("testapplyconfig")
+	_, err := c.SetConfig("TIMEOUT", "100")
+	assert.Nil(t, err)
+
+	current, err := c.GetSearchConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), *current.Timeout)
+
+	applied, err := c.ApplySearchConfig(&SearchConfig{Timeout: ConfigInt(200), MinPrefix: current.MinPrefix})
+	assert.Nil(t, err)
+	assert.Equal(t, []ConfigChange{{Option: "TIMEOUT", Current: "100", Desired: "200"}}, applied)
+
+	applied, err = c.ApplySearchConfig(&SearchConfig{Timeout: ConfigInt(200), Other: map[string]string{"NOT_AN_OPTION": "1"}})
+	assert.Equal(t, 0, len(applied))
+	assert.IsType(t, ConfigError{}, err)
+	assert.Contains(t, err.(ConfigError), "NOT_AN_OPTION")
+	teardown(c)
+}
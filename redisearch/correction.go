@@ -0,0 +1,220 @@
+package redisearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// QueryCorrection is a single misspelled term replaced by SuggestCorrectedQuery
+type QueryCorrection struct {
+	// Term is the term as written in the original query
+	Term string
+	// Suggestion is the top scored suggestion replacing the term
+	Suggestion string
+	Score      float32
+	// Offset is the byte offset of the term in the original query string
+	Offset int
+}
+
+// CorrectedQuery is the result of SuggestCorrectedQuery: a copy of the original query with
+// the misspelled terms replaced, and the list of the replacements that were made
+type CorrectedQuery struct {
+	Query       *Query
+	Corrections []QueryCorrection
+}
+
+// Changed returns true if at least one term of the query was corrected
+func (c CorrectedQuery) Changed() bool {
+	return len(c.Corrections) > 0
+}
+
+// SuggestCorrectedQuery runs FT.SPELLCHECK on the query string and replaces each misspelled term by its top scored
+// suggestion. Field modifiers, operators, quotes, tag lists, numeric ranges, parameters, prefix and fuzzy terms
+// are kept as they are. The dictionaries and distance of opts, and the dialect of the query, are used by the
+// spelling correction. If opts is nil, the default spellcheck options are used.
+func (i *Client) SuggestCorrectedQuery(q *Query, opts *SpellCheckOptions) (*CorrectedQuery, error) {
+	if opts == nil {
+		opts = NewSpellCheckOptionsDefaults()
+	}
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	args := redis.Args{i.name, q.Raw}
+	args = append(args, opts.serialize()...)
+	if q.Dialect != 0 {
+		args = args.Add("DIALECT", q.Dialect)
+	}
+	res, err := conn.Do("FT.SPELLCHECK", args...)
+	if err != nil {
+		return nil, err
+	}
+	misspelled, _, err := processSpellCheckReply(res)
+	if err != nil {
+		return nil, err
+	}
+	return correctQuery(q, misspelled), nil
+}
+
+// SearchWithCorrection searches the index for the given query. If the query has no results, it is corrected
+// with SuggestCorrectedQuery and the search is retried with the corrected query.
+// The returned correction is nil if the original query was used.
+func (i *Client) SearchWithCorrection(q *Query, opts *SpellCheckOptions) (docs []Document, total int, correction *CorrectedQuery, err error) {
+	docs, total, err = i.Search(q)
+	if err != nil || total > 0 {
+		return
+	}
+	corrected, err := i.SuggestCorrectedQuery(q, opts)
+	if err != nil || !corrected.Changed() {
+		return
+	}
+	docs, total, err = i.Search(corrected.Query)
+	if err != nil {
+		return
+	}
+	correction = corrected
+	return
+}
+
+// internal function
+// correctQuery replaces the misspelled terms of the query by their top scored suggestion
+func correctQuery(q *Query, misspelled []MisspelledTerm) *CorrectedQuery {
+	best := make(map[string]MisspelledSuggestion, len(misspelled))
+	for _, term := range misspelled {
+		key := strings.ToLower(term.Term)
+		for _, sugg := range term.MisspelledSuggestionList {
+			if current, ok := best[key]; !ok || sugg.Score > current.Score {
+				best[key] = sugg
+			}
+		}
+	}
+
+	corrected := *q
+	ret := &CorrectedQuery{Query: &corrected}
+	if len(best) == 0 {
+		return ret
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, token := range queryTerms(q.Raw) {
+		sugg, ok := best[strings.ToLower(token.text)]
+		if !ok {
+			continue
+		}
+		sb.WriteString(q.Raw[last:token.start])
+		sb.WriteString(sugg.Suggestion)
+		last = token.end
+		ret.Corrections = append(ret.Corrections, QueryCorrection{
+			Term:       token.text,
+			Suggestion: sugg.Suggestion,
+			Score:      sugg.Score,
+			Offset:     token.start,
+		})
+	}
+	sb.WriteString(q.Raw[last:])
+	corrected.Raw = sb.String()
+	return ret
+}
+
+// queryTerm is a plain term of a query string, that can be replaced by a spelling correction
+type queryTerm struct {
+	start int
+	end   int
+	text  string
+}
+
+// internal function
+// queryTerms returns the plain terms of a query string, skipping field names, tag lists, numeric and geo ranges,
+// attributes, parameters, prefix/suffix terms and fuzzy terms
+func queryTerms(raw string) (terms []queryTerm) {
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		switch {
+		case c == '\\':
+			i += 2
+		case c == '{':
+			i = skipBlock(raw, i, '{', '}')
+		case c == '[':
+			i = skipBlock(raw, i, '[', ']')
+		case c == '@' || c == '$':
+			// field name or parameter
+			i = skipWord(raw, i+1)
+		case c == '%':
+			// fuzzy term
+			for i < len(raw) && raw[i] == '%' {
+				i++
+			}
+			i = skipWord(raw, i)
+		default:
+			r, size := utf8.DecodeRuneInString(raw[i:])
+			if !isTermRune(r) {
+				i += size
+				continue
+			}
+			start := i
+			end := skipWord(raw, i)
+			i = end
+			text := raw[start:end]
+			if start > 0 && raw[start-1] == '*' || end < len(raw) && raw[end] == '*' {
+				// prefix, suffix or infix term
+				continue
+			}
+			if isNumber(text) {
+				continue
+			}
+			terms = append(terms, queryTerm{start: start, end: end, text: text})
+		}
+	}
+	return
+}
+
+// skipBlock returns the position after the closing character matching the opening one at position i
+func skipBlock(raw string, i int, open, close byte) int {
+	depth := 0
+	for ; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(raw)
+}
+
+// skipWord returns the position after the word starting at position i, including escaped characters
+func skipWord(raw string, i int) int {
+	for i < len(raw) {
+		if raw[i] == '\\' && i+1 < len(raw) {
+			i += 2
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(raw[i:])
+		if !isTermRune(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func isTermRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package redisearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_queryTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{"plain", "hello world", []string{"hello", "world"}},
+		{"field-modifier", "@title:helo @body:(wrld|foo)", []string{"helo", "wrld", "foo"}},
+		{"phrase", `"helo wrld" -bar ~baz`, []string{"helo", "wrld", "bar", "baz"}},
+		{"tags", "@tags:{helo world} foo", []string{"foo"}},
+		{"numeric", "@price:[10 (100] foo", []string{"foo"}},
+		{"params", "@price:[$min $max] $term foo", []string{"foo"}},
+		{"prefix-fuzzy", "hel* *orld %%fuzy%% foo", []string{"foo"}},
+		{"attributes", "(foo bar)=>{$weight: 2.0; $inorder: true}", []string{"foo", "bar"}},
+		{"escaped", "hello\\-world foo", []string{"hello\\-world", "foo"}},
+		{"numbers", "2020 foo", []string{"foo"}},
+		{"unicode", "café naïve", []string{"café", "naïve"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, term := range queryTerms(tt.raw) {
+				assert.Equal(t, term.text, tt.raw[term.start:term.end])
+				got = append(got, term.text)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_correctQuery(t *testing.T) {
+	misspelled := []MisspelledTerm{
+		{Term: "helo", MisspelledSuggestionList: []MisspelledSuggestion{{"help", 0.2}, {"hello", 0.8}}},
+		{Term: "wrld", MisspelledSuggestionList: []MisspelledSuggestion{{"world", 0.5}}},
+		{Term: "zzz", MisspelledSuggestionList: []MisspelledSuggestion{}},
+	}
+	q := NewQuery(`@title:Helo "helo wrld" @tags:{helo} zzz`).SetDialect(2)
+	corrected := correctQuery(q, misspelled)
+	assert.True(t, corrected.Changed())
+	assert.Equal(t, `@title:hello "hello world" @tags:{helo} zzz`, corrected.Query.Raw)
+	assert.Equal(t, 2, corrected.Query.Dialect)
+	assert.Equal(t, `@title:Helo "helo wrld" @tags:{helo} zzz`, q.Raw)
+	assert.Equal(t, []QueryCorrection{
+		{Term: "Helo", Suggestion: "hello", Score: 0.8, Offset: 7},
+		{Term: "helo", Suggestion: "hello", Score: 0.8, Offset: 13},
+		{Term: "wrld", Suggestion: "world", Score: 0.5, Offset: 18},
+	}, corrected.Corrections)
+
+	unchanged := correctQuery(NewQuery("hello"), nil)
+	assert.False(t, unchanged.Changed())
+	assert.Equal(t, "hello", unchanged.Query.Raw)
+}
+
+func TestClient_SearchWithCorrection(t *testing.T) {
+	skipInMemory(t, "FT.SPELLCHECK")
+	c := createClient("testcorrection")
+	countries := []string{"Spain", "Israel", "Portugal", "France", "England", "Angola"}
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("country"))
+	c.Drop()
+	assert.Nil(t, c.CreateIndex(sc))
+
+	docs := make([]Document, len(countries))
+	for i := 0; i < len(countries); i++ {
+		docs[i] = NewDocument(fmt.Sprintf("TestSearchWithCorrection-doc%d", i), 1).Set("country", countries[i])
+	}
+	assert.Nil(t, c.Index(docs...))
+
+	corrected, err := c.SuggestCorrectedQuery(NewQuery("@country:Portuga"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "@country:portugal", corrected.Query.Raw)
+
+	_, total, correction, err := c.SearchWithCorrection(NewQuery("Portuga"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.NotNil(t, correction)
+
+	_, total, correction, err = c.SearchWithCorrection(NewQuery("Portugal"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Nil(t, correction)
+	teardown(c)
+}
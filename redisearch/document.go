@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/gomodule/redigo/redis"
 )
 
 const (
@@ -79,6 +81,9 @@ func (d Document) Set(name string, value interface{}) Document {
 // to signify an actual backslash, so the actual text in redis-cli for example, will be entered as `hello\\-world`.
 // Underscores (`_`) are not used as separators in either document or query.
 // So the text `hello_world` will remain as is after tokenization.
+//
+// Deprecated: EscapeTextFileString does not escape the whitespace, `|`, `?`, `/` and `\`, use EscapeTerm or the
+// other escaping functions for the context of the value.
 func EscapeTextFileString(value string) string {
 	for _, char := range field_tokenization {
 		value = strings.Replace(value, string(char), ("\\" + string(char)), -1)
@@ -112,6 +117,39 @@ func loadDocument(arr []interface{}, idIdx, scoreIdx, payloadIdx, fieldsIdx int)
 	return doc, nil
 }
 
+// internal function
+// loadDocumentMap loads a document from a result of a RESP3 FT.SEARCH reply
+func loadDocumentMap(result interface{}) (Document, error) {
+	values, err := replyValues(result, nil)
+	if err != nil {
+		return Document{}, err
+	}
+	m := RESP3Map(values)
+	value, _ := m.Get("id")
+	id, err := redis.String(value, nil)
+	if err != nil {
+		return Document{}, fmt.Errorf("Could not parse id: %s", err)
+	}
+	score := 1.0
+	if value, ok := m.Get("score"); ok {
+		if score, err = redis.Float64(value, nil); err != nil {
+			return Document{}, fmt.Errorf("Could not parse score: %s", err)
+		}
+	}
+	doc := NewDocument(id, float32(score))
+	if value, ok := m.Get("payload"); ok {
+		doc.Payload, _ = value.([]byte)
+	}
+	if value, ok := m.Get("extra_attributes"); ok {
+		lst, err := replyValues(value, nil)
+		if err != nil {
+			return Document{}, err
+		}
+		doc.loadFields(lst)
+	}
+	return doc, nil
+}
+
 // internal function used by loadDocument()
 // loadFields loads the fields of the document
 func (d *Document) loadFields(lst []interface{}) *Document {
@@ -136,6 +174,17 @@ func (d *Document) loadFields(lst []interface{}) *Document {
 	return d
 }
 
+// internal function
+// sortedKeys returns the keys of a map of properties sorted, so that the commands are serialized the same way on each run
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // DocumentList is used to sort documents by descending score
 type DocumentList []Document
 
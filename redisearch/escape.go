@@ -0,0 +1,72 @@
+package redisearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The query strings are tokenized like the documents: every rune but the letters, the digits and the underscore
+// may separate the terms or have a meaning in the query syntax, e.g. `-` for a negation or `|` for a union.
+// The functions below escape the values built into query strings according to their context, by prepending a
+// backslash to the runes that would otherwise be interpreted. Escaping a rune that needs no escaping is harmless.
+
+// EscapeTerm escapes a value to be matched as a single term, e.g. `foo-bar baz` is escaped as `foo\-bar\ baz`
+func EscapeTerm(value string) string {
+	return escapeRunes(value, func(r rune) bool { return !isTermRune(r) })
+}
+
+// EscapePhrase escapes a value to be used between double quotes as an exact phrase, e.g. `"` + EscapePhrase(v) + `"`.
+// The words of the phrase are separated as in the documents, only the double quotes and backslashes are escaped.
+func EscapePhrase(value string) string {
+	return escapeRunes(value, func(r rune) bool { return r == '"' || r == '\\' })
+}
+
+// EscapeTagValue escapes a value to be used inside a tag filter, e.g. `@tags:{` + EscapeTagValue(v) + `}`.
+// The tag values are not tokenized, but the query parser reads them with the same rule as the terms: the
+// punctuation and the whitespace end a value (or, for `|`, separate the values) unless they are escaped,
+// so the escaping of EscapeTerm applies as is. The value should be trimmed, as the tags are when indexed.
+func EscapeTagValue(value string) string {
+	return EscapeTerm(value)
+}
+
+// EscapePrefix escapes a value to be matched as the prefix of a term, and appends the `*` of the prefix query
+func EscapePrefix(value string) string {
+	return EscapeTerm(value) + "*"
+}
+
+// EscapeFieldName escapes the name of a field to be used after the @ of a field modifier, e.g. a JSON path used
+// as a field name without an AS alias
+func EscapeFieldName(name string) string {
+	return EscapeTerm(name)
+}
+
+// EscapeExpressionString escapes a value to be used between quotes as a string literal of the expressions of
+// FT.AGGREGATE APPLY and FILTER steps, e.g. `@brand == "` + EscapeExpressionString(v) + `"`
+func EscapeExpressionString(value string) string {
+	return escapeRunes(value, func(r rune) bool { return r == '"' || r == '\'' || r == '\\' })
+}
+
+// internal function
+// escapeRunes prepends a backslash to the runes of value matching escape
+func escapeRunes(value string, escape func(r rune) bool) string {
+	var sb strings.Builder
+	sb.Grow(len(value))
+	for _, r := range value {
+		if escape(r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// internal function
+// isQuerySeparator returns true for the runes separating the terms of the documents and of the query strings:
+// the ASCII punctuation and whitespace, and the unicode whitespace
+func isQuerySeparator(r rune) bool {
+	if r < utf8.RuneSelf {
+		return !isTermRune(r)
+	}
+	return unicode.IsSpace(r)
+}
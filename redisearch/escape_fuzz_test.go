@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package redisearch
+
+import "testing"
+
+func FuzzEscape(f *testing.F) {
+	for _, seed := range []string{"hello world", `a|b\c`, "{tag}", `"phrase"`, "$param", "-not", "@field:x", "w'x'", "10 +inf]"} {
+		f.Add(seed)
+	}
+	// with REDISEARCH_TEST_HOST, the escaped values are also checked against the server
+	s := newEscapeServer(f)
+	f.Fuzz(func(t *testing.T, value string) {
+		checkEscaped(t, value)
+		s.check(t, value)
+	})
+}
@@ -0,0 +1,195 @@
+package redisearch
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `foo\-bar\ baz`, EscapeTerm("foo-bar baz"))
+	assert.Equal(t, `hello_world`, EscapeTerm("hello_world"))
+	assert.Equal(t, `https\:\/\/en\.wikipedia\.org\/wiki\?a\|b\\c`, EscapeTerm(`https://en.wikipedia.org/wiki?a|b\c`))
+	assert.Equal(t, `héllo\ wörld`, EscapeTerm("héllo wörld"))
+	assert.Equal(t, `say \"hi\" \\o/`, EscapePhrase(`say "hi" \o/`))
+	assert.Equal(t, `new\ york\ \|\ paris`, EscapeTagValue("new york | paris"))
+	assert.Equal(t, `\$tag`, EscapeTagValue("$tag"))
+	assert.Equal(t, `hel\-lo*`, EscapePrefix("hel-lo"))
+	assert.Equal(t, `\$\.title`, EscapeFieldName("$.title"))
+	assert.Equal(t, `it\'s \"sony\" \\`, EscapeExpressionString(`it's "sony" \`))
+}
+
+// unescape removes the backslashes of an escaped value
+func unescape(value string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// checkEscaped checks that the escaped value parses back to the value in each context with ParseQuery.
+// escapeServer checks the same values against the server.
+func checkEscaped(t *testing.T, value string) {
+	if value == "" || !utf8.ValidString(value) {
+		return
+	}
+	for _, dialect := range []int{1, 2} {
+		parsed, err := ParseQuery(EscapeTerm(value), dialect)
+		if assert.Nil(t, err, value) {
+			assert.Equal(t, &TermNode{Term: EscapeTerm(value)}, parsed.Root, value)
+			assert.Equal(t, value, unescape(parsed.Root.(*TermNode).Term), value)
+		}
+
+		parsed, err = ParseQuery(EscapePrefix(value), dialect)
+		if assert.Nil(t, err, value) {
+			assert.Equal(t, &TermNode{Term: EscapeTerm(value), Prefix: true}, parsed.Root, value)
+		}
+
+		// a phrase of whitespace is an empty phrase
+		if strings.TrimSpace(value) != "" {
+			parsed, err = ParseQuery(`"`+EscapePhrase(value)+`"`, dialect)
+			if assert.Nil(t, err, value) {
+				assert.Equal(t, &PhraseNode{Text: EscapePhrase(value)}, parsed.Root, value)
+				assert.Equal(t, value, unescape(parsed.Root.(*PhraseNode).Text), value)
+			}
+		}
+
+		raw := "@" + EscapeFieldName(value) + ":{" + EscapeTagValue(value) + "}"
+		parsed, err = ParseQuery(raw, dialect)
+		if assert.Nil(t, err, raw) {
+			field := parsed.Root.(*FieldNode)
+			assert.Equal(t, []string{EscapeFieldName(value)}, field.Fields, raw)
+			assert.Equal(t, []string{EscapeTagValue(value)}, field.Child.(*TagNode).Values, raw)
+		}
+	}
+}
+
+func TestEscape_parse(t *testing.T) {
+	// every printable ASCII rune alone and within words
+	for r := rune(' '); r < 0x7f; r++ {
+		checkEscaped(t, string(r))
+		checkEscaped(t, "a"+string(r)+"b")
+		checkEscaped(t, string(r)+"ab"+string(r))
+	}
+	// random strings of the runes having a meaning in the query syntax
+	runes := []rune(" \t\n\\|{}[]()\"'@$%*:;,.<>!#^&+-=~?/`_aw0é€")
+	rnd := rand.New(rand.NewSource(0))
+	for ii := 0; ii < 1000; ii++ {
+		value := make([]rune, 1+rnd.Intn(12))
+		for jj := range value {
+			value[jj] = runes[rnd.Intn(len(runes))]
+		}
+		checkEscaped(t, string(value))
+	}
+}
+
+// escapeServer checks the escaped values against the test server, whose query parser and tokenizer are independent
+// of ParseQuery: the terms and prefixes must not be syntax errors, and the phrases and tags must match a document
+// holding the value. Its indexes differ by the separator of their tag field, so that a value with a comma can be
+// indexed as a single tag.
+type escapeServer struct {
+	clients map[rune]*Client
+}
+
+// newEscapeServer creates the indexes of the checks, or returns nil without REDISEARCH_TEST_HOST
+func newEscapeServer(t testing.TB) *escapeServer {
+	if os.Getenv("REDISEARCH_TEST_HOST") == "" {
+		return nil
+	}
+	s := &escapeServer{clients: make(map[rune]*Client)}
+	for _, separator := range []rune{',', ';'} {
+		c := createClient("escape-idx-" + string(separator))
+		c.DropIndex(false)
+		sc := NewSchema(*NewOptions().SetStopWords([]string{})).
+			AddField(NewTextField("title")).
+			AddField(NewTagFieldOptions("tags", TagFieldOptions{Separator: byte(separator)}))
+		if !assert.Nil(t, c.CreateIndexWithIndexDefinition(sc, NewIndexDefinition().AddPrefix("escape:"))) {
+			t.FailNow()
+		}
+		s.clients[separator] = c
+	}
+	t.Cleanup(func() {
+		for _, c := range s.clients {
+			c.DropIndex(true)
+		}
+	})
+	return s
+}
+
+// check indexes a document holding the value, and queries it with the escaped value in each context
+func (s *escapeServer) check(t testing.TB, value string) {
+	if s == nil || value == "" || !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+		return
+	}
+	separator := ','
+	if strings.ContainsRune(value, separator) {
+		separator = ';'
+	}
+	c := s.clients[separator]
+	conn := c.pool.Get()
+	_, err := conn.Do("HSET", "escape:doc", "title", value, "tags", value)
+	conn.Close()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the phrase matches when the value has at least one term
+	hasTerm := strings.IndexFunc(value, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' }) != -1
+	// the tags are trimmed when indexed
+	tag := strings.TrimSpace(value)
+	for _, dialect := range []int{1, 2} {
+		search := func(raw string) (int, error) {
+			q := NewQuery(raw).SetFlags(QueryNoContent | QueryVerbatim).SetDialect(dialect)
+			_, total, err := c.Search(q)
+			return total, err
+		}
+		_, err := search("@title:" + EscapeTerm(value))
+		assert.Nil(t, err, "term %q", value)
+		if utf8.RuneCountInString(value) >= 2 {
+			_, err = search("@title:" + EscapePrefix(value))
+			assert.Nil(t, err, "prefix %q", value)
+		}
+		if hasTerm {
+			total, err := search(`@title:"` + EscapePhrase(value) + `"`)
+			assert.Nil(t, err, "phrase %q", value)
+			assert.Equal(t, 1, total, "phrase %q", value)
+		}
+		if tag != "" && !strings.ContainsRune(tag, separator) {
+			total, err := search("@tags:{" + EscapeTagValue(tag) + "}")
+			assert.Nil(t, err, "tag %q", tag)
+			assert.Equal(t, 1, total, "tag %q", tag)
+		}
+	}
+}
+
+func TestEscape_server(t *testing.T) {
+	s := newEscapeServer(t)
+	if s == nil {
+		t.Skip("REDISEARCH_TEST_HOST is not set")
+	}
+	for r := rune(' '); r < 0x7f; r++ {
+		s.check(t, "a"+string(r)+"b")
+		s.check(t, string(r)+"ab"+string(r))
+	}
+	runes := []rune(" \t\n\\|{}[]()\"'@$%*:;,.<>!#^&+-=~?/`_aw0é€")
+	rnd := rand.New(rand.NewSource(0))
+	for ii := 0; ii < 200; ii++ {
+		value := make([]rune, 1+rnd.Intn(12))
+		for jj := range value {
+			value[jj] = runes[rnd.Intn(len(runes))]
+		}
+		s.check(t, string(value))
+	}
+}
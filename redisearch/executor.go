@@ -0,0 +1,301 @@
+package redisearch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Executor runs redis commands on behalf of the Client, Autocompleter and Admin, so that they can run on
+// any redis driver. Replies use the redigo conventions: bulk strings are []byte, integers int64, arrays
+// []interface{}, RESP3 maps RESP3Map, nil replies nil, and server errors are redis.Error values.
+type Executor interface {
+	// Do runs a single command and returns its reply
+	Do(cmd string, args ...interface{}) (interface{}, error)
+
+	// Pipeline runs the commands in a single round trip, and returns a reply per command.
+	// The returned error is only set if the pipeline itself failed.
+	Pipeline(cmds []Command) ([]Reply, error)
+}
+
+// Command is a single command of a pipeline
+type Command struct {
+	Name string
+	Args []interface{}
+}
+
+// Reply is the reply of a single pipelined command, or its error
+type Reply struct {
+	Value interface{}
+	Err   error
+}
+
+// NewRedigoExecutor creates an Executor running the commands on connections of the redigo pool
+func NewRedigoExecutor(pool ConnPool) Executor {
+	return &redigoExecutor{pool: pool}
+}
+
+type redigoExecutor struct {
+	pool ConnPool
+}
+
+func (e *redigoExecutor) Do(cmd string, args ...interface{}) (interface{}, error) {
+	conn := e.pool.Get()
+	defer conn.Close()
+	return conn.Do(cmd, args...)
+}
+
+func (e *redigoExecutor) Pipeline(cmds []Command) ([]Reply, error) {
+	conn := e.pool.Get()
+	defer conn.Close()
+	for _, cmd := range cmds {
+		if err := conn.Send(cmd.Name, cmd.Args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+	replies := make([]Reply, len(cmds))
+	for ii := range cmds {
+		value, err := conn.Receive()
+		if _, isServerErr := err.(redis.Error); err != nil && !isServerErr {
+			return nil, err
+		}
+		replies[ii] = Reply{Value: value, Err: err}
+	}
+	return replies, nil
+}
+
+func (e *redigoExecutor) Close() error {
+	return e.pool.Close()
+}
+
+// GoRedisDoFunc runs a single command with a go-redis client, e.g.
+//
+//	func(args ...interface{}) (interface{}, error) { return rdb.Do(ctx, args...).Result() }
+type GoRedisDoFunc func(args ...interface{}) (interface{}, error)
+
+// GoRedisPipelineFunc runs commands in a go-redis pipeline, returning the value and the error of each command, e.g.
+//
+//	func(cmds [][]interface{}) ([]interface{}, []error) {
+//		pipe := rdb.Pipeline()
+//		results := make([]*redis.Cmd, len(cmds))
+//		for i, args := range cmds {
+//			results[i] = pipe.Do(ctx, args...)
+//		}
+//		pipe.Exec(ctx)
+//		values, errs := make([]interface{}, len(cmds)), make([]error, len(cmds))
+//		for i, cmd := range results {
+//			values[i], errs[i] = cmd.Result()
+//		}
+//		return values, errs
+//	}
+type GoRedisPipelineFunc func(cmds [][]interface{}) ([]interface{}, []error)
+
+// NewGoRedisExecutor creates an Executor running the commands with a go-redis client, through the given functions.
+// The go-redis replies are converted to the redigo conventions of Executor. If pipeline is nil, pipelined
+// commands are run one after the other.
+func NewGoRedisExecutor(do GoRedisDoFunc, pipeline GoRedisPipelineFunc) Executor {
+	return &goRedisExecutor{do: do, pipeline: pipeline}
+}
+
+type goRedisExecutor struct {
+	do       GoRedisDoFunc
+	pipeline GoRedisPipelineFunc
+}
+
+func (e *goRedisExecutor) Do(cmd string, args ...interface{}) (interface{}, error) {
+	value, err := e.do(append([]interface{}{cmd}, args...)...)
+	return fromGoRedis(value, err)
+}
+
+func (e *goRedisExecutor) Pipeline(cmds []Command) ([]Reply, error) {
+	replies := make([]Reply, len(cmds))
+	if e.pipeline == nil {
+		for ii, cmd := range cmds {
+			replies[ii].Value, replies[ii].Err = e.Do(cmd.Name, cmd.Args...)
+		}
+		return replies, nil
+	}
+	args := make([][]interface{}, len(cmds))
+	for ii, cmd := range cmds {
+		args[ii] = append([]interface{}{cmd.Name}, cmd.Args...)
+	}
+	values, errs := e.pipeline(args)
+	if len(values) != len(cmds) || len(errs) != len(cmds) {
+		return nil, fmt.Errorf("go-redis pipeline returned %d values and %d errors for %d commands", len(values), len(errs), len(cmds))
+	}
+	for ii := range cmds {
+		replies[ii].Value, replies[ii].Err = fromGoRedis(values[ii], errs[ii])
+	}
+	return replies, nil
+}
+
+// internal function
+// fromGoRedis converts a go-redis reply and error to the redigo conventions
+func fromGoRedis(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		if err.Error() == "redis: nil" {
+			return nil, nil
+		}
+		// go-redis server errors implement the RedisError() marker method
+		if _, isServerErr := err.(interface{ RedisError() }); isServerErr {
+			return nil, redis.Error(err.Error())
+		}
+		return nil, err
+	}
+	return fromGoRedisValue(value), nil
+}
+
+func fromGoRedisValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case int:
+		return int64(v)
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, elem := range v {
+			ret[i] = fromGoRedisValue(elem)
+		}
+		return ret
+	case map[interface{}]interface{}:
+		// go-redis does not keep the order of the keys, sort them for stable replies
+		keys := make([]interface{}, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		ret := make(RESP3Map, 0, 2*len(v))
+		for _, key := range keys {
+			ret = append(ret, fromGoRedisValue(key), fromGoRedisValue(v[key]))
+		}
+		return ret
+	case map[string]interface{}:
+		generic := make(map[interface{}]interface{}, len(v))
+		for key, elem := range v {
+			generic[key] = elem
+		}
+		return fromGoRedisValue(generic)
+	case error:
+		return redis.Error(v.Error())
+	default:
+		return v
+	}
+}
+
+// internal struct
+// executorPool adapts an Executor to the ConnPool used by the clients
+type executorPool struct {
+	exec Executor
+}
+
+func (p *executorPool) Get() redis.Conn {
+	return &executorConn{exec: p.exec}
+}
+
+func (p *executorPool) Close() error {
+	if closer, ok := p.exec.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// internal struct
+// executorConn adapts an Executor to a redis.Conn: sent commands are buffered and run as a pipeline on Flush
+type executorConn struct {
+	exec    Executor
+	pending []Command
+	replies []Reply
+	err     error
+}
+
+func (c *executorConn) Close() error {
+	c.pending, c.replies = nil, nil
+	return nil
+}
+
+func (c *executorConn) Err() error {
+	return c.err
+}
+
+func (c *executorConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if len(c.pending) == 0 && len(c.replies) == 0 {
+		if cmd == "" {
+			return nil, nil
+		}
+		return c.exec.Do(cmd, args...)
+	}
+	if cmd != "" {
+		c.Send(cmd, args...)
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	var reply interface{}
+	var err error
+	for len(c.replies) > 0 {
+		reply, err = c.Receive()
+	}
+	return reply, err
+}
+
+func (c *executorConn) Send(cmd string, args ...interface{}) error {
+	c.pending = append(c.pending, Command{Name: cmd, Args: args})
+	return nil
+}
+
+func (c *executorConn) Flush() error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	replies, err := c.exec.Pipeline(c.pending)
+	c.pending = nil
+	if err != nil {
+		c.err = err
+		return err
+	}
+	c.replies = append(c.replies, replies...)
+	return nil
+}
+
+func (c *executorConn) Receive() (interface{}, error) {
+	if len(c.replies) == 0 {
+		if err := c.Flush(); err != nil {
+			return nil, err
+		}
+		if len(c.replies) == 0 {
+			return nil, errors.New("redisearch: Receive without a pending command")
+		}
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply.Value, reply.Err
+}
+
+// NewClientFromExecutor creates a new Client running its commands with the given executor
+func NewClientFromExecutor(exec Executor, name string) *Client {
+	return &Client{pool: &executorPool{exec: exec}, name: name}
+}
+
+// NewAutocompleterFromExecutor creates a new Autocompleter running its commands with the given executor
+func NewAutocompleterFromExecutor(exec Executor, name string) *Autocompleter {
+	return &Autocompleter{pool: &executorPool{exec: exec}, name: name}
+}
+
+// NewAdminFromExecutor creates a new admin client running its commands with the given executor
+func NewAdminFromExecutor(exec Executor) *Admin {
+	return &Admin{pool: &executorPool{exec: exec}}
+}
@@ -0,0 +1,120 @@
+package redisearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeExecutor replies to each command with the next canned reply
+type fakeExecutor struct {
+	cmds      []Command
+	replies   []Reply
+	pipelines int
+}
+
+func (e *fakeExecutor) next(cmd string, args []interface{}) Reply {
+	e.cmds = append(e.cmds, Command{Name: cmd, Args: args})
+	reply := e.replies[0]
+	e.replies = e.replies[1:]
+	return reply
+}
+
+func (e *fakeExecutor) Do(cmd string, args ...interface{}) (interface{}, error) {
+	reply := e.next(cmd, args)
+	return reply.Value, reply.Err
+}
+
+func (e *fakeExecutor) Pipeline(cmds []Command) ([]Reply, error) {
+	e.pipelines++
+	replies := make([]Reply, len(cmds))
+	for ii, cmd := range cmds {
+		replies[ii] = e.next(cmd.Name, cmd.Args)
+	}
+	return replies, nil
+}
+
+func Test_executorConn(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{
+		{Value: int64(1)},
+		{Err: redis.Error("ERR bad")},
+		{Value: []byte("OK")},
+	}}
+	conn := (&executorPool{exec: exec}).Get()
+	defer conn.Close()
+
+	assert.Nil(t, conn.Send("INCR", "a"))
+	assert.Nil(t, conn.Send("FOO"))
+	assert.Nil(t, conn.Flush())
+	assert.Equal(t, 1, exec.pipelines)
+	value, err := redis.Int(conn.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, value)
+	_, err = conn.Receive()
+	assert.Equal(t, redis.Error("ERR bad"), err)
+
+	res, err := redis.String(conn.Do("SET", "a", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", res)
+	assert.Equal(t, Command{Name: "SET", Args: []interface{}{"a", 1}}, exec.cmds[2])
+
+	_, err = conn.Receive()
+	assert.NotNil(t, err)
+}
+
+type goRedisError string
+
+func (e goRedisError) Error() string { return string(e) }
+func (goRedisError) RedisError()     {}
+
+func Test_fromGoRedis(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		err     error
+		want    interface{}
+		wantErr error
+	}{
+		{"string", "foo", nil, []byte("foo"), nil},
+		{"int64", int64(3), nil, int64(3), nil},
+		{"float", 1.5, nil, []byte("1.5"), nil},
+		{"bool", true, nil, int64(1), nil},
+		{"array", []interface{}{"a", int64(1)}, nil, []interface{}{[]byte("a"), int64(1)}, nil},
+		{"map", map[interface{}]interface{}{"b": int64(2), "a": "x"}, nil, RESP3Map{[]byte("a"), []byte("x"), []byte("b"), int64(2)}, nil},
+		{"nil", nil, errors.New("redis: nil"), nil, nil},
+		{"server-error", nil, goRedisError("ERR unknown index"), nil, redis.Error("ERR unknown index")},
+		{"network-error", nil, errors.New("i/o timeout"), nil, errors.New("i/o timeout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fromGoRedis(tt.value, tt.err)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewGoRedisExecutor(t *testing.T) {
+	var sent [][]interface{}
+	do := func(args ...interface{}) (interface{}, error) {
+		sent = append(sent, args)
+		return []interface{}{int64(1), "doc1", []interface{}{"title", "hello"}}, nil
+	}
+	c := NewClientFromExecutor(NewGoRedisExecutor(do, nil), "idx")
+	docs, total, err := c.Search(NewQuery("hello").Limit(0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "doc1", docs[0].Id)
+	assert.Equal(t, "hello", docs[0].Properties["title"])
+	assert.Equal(t, "FT.SEARCH", sent[0][0])
+	assert.Equal(t, "idx", sent[0][1])
+
+	pipeline := func(cmds [][]interface{}) ([]interface{}, []error) {
+		return []interface{}{"OK", nil}, []error{nil, goRedisError("ERR bad")}
+	}
+	replies, err := NewGoRedisExecutor(do, pipeline).Pipeline([]Command{{Name: "SET"}, {Name: "FOO"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Reply{{Value: []byte("OK")}, {Err: redis.Error("ERR bad")}}, replies)
+}
@@ -0,0 +1,628 @@
+package redisearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ExportDocuments selects how ExportIndex finds the documents of the index
+type ExportDocuments int
+
+const (
+	// ExportScan scans the keys matching the prefixes of the index definition. Keys of the indexed type that are
+	// under the prefixes are exported even if the index filter rejects them.
+	ExportScan ExportDocuments = iota
+
+	// ExportSearch pages through a FT.SEARCH of all the documents, so only the indexed documents are exported.
+	// Documents written while the export runs may be missed or exported twice.
+	ExportSearch
+
+	// ExportNoDocuments exports the index without its documents
+	ExportNoDocuments
+)
+
+// ExportOptions are the options of ExportIndexOptions
+type ExportOptions struct {
+	// Documents selects how the documents are found
+	Documents ExportDocuments
+
+	// BatchSize is the number of documents read by SCAN/FT.SEARCH page and fetched in a single pipeline
+	BatchSize int
+
+	// Aliases are the candidate alias names of the index. RediSearch has no command enumerating the aliases,
+	// so the export cannot discover them: only the names listed here that turn out to be aliases of the
+	// exported index are exported. See Admin.ResolveAliases.
+	Aliases []string
+
+	// Dictionaries are the names of the dictionaries to export with the index, e.g. the ones used by SpellCheck.
+	Dictionaries []string
+}
+
+// DefaultExportOptions are the default options of ExportIndex
+var DefaultExportOptions = ExportOptions{
+	Documents: ExportScan,
+	BatchSize: 500,
+}
+
+// ImportOptions are the options of ImportIndexOptions
+type ImportOptions struct {
+	// BatchSize is the number of document writes sent in a single pipeline
+	BatchSize int
+
+	// SkipAliases does not point the exported aliases to the target index
+	SkipAliases bool
+}
+
+// DefaultImportOptions are the default options of ImportIndex
+var DefaultImportOptions = ImportOptions{
+	BatchSize: 500,
+}
+
+// exportVersion is the version of the export format, written in the index record
+const exportVersion = 1
+
+// Record types of an export
+const (
+	exportIndexRecord    = "index"
+	exportAliasRecord    = "alias"
+	exportSynonymsRecord = "synonyms"
+	exportDictRecord     = "dict"
+	exportDocRecord      = "doc"
+)
+
+// internal struct
+// exportRecord is a line of an export. The index record comes first, followed by the aliases,
+// the synonym groups, the dictionaries and the documents.
+type exportRecord struct {
+	Type string `json:"type"`
+
+	// index record
+	Version    int              `json:"version,omitempty"`
+	Options    *Options         `json:"options,omitempty"`
+	Definition *IndexDefinition `json:"definition,omitempty"`
+	Schema     []exportField    `json:"schema,omitempty"`
+
+	// name of the index, alias or dictionary
+	Name string `json:"name,omitempty"`
+
+	// synonyms and dict records
+	Group string   `json:"group,omitempty"`
+	Terms []string `json:"terms,omitempty"`
+
+	// doc record. Hash values that are not valid UTF-8, e.g. vectors, are in Binary (base64 encoded)
+	Key    string            `json:"key,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Binary map[string][]byte `json:"binary,omitempty"`
+	JSON   json.RawMessage   `json:"json,omitempty"`
+}
+
+// internal struct
+// exportField is a schema field, whose options are typed by the field type
+type exportField struct {
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	Sortable bool            `json:"sortable,omitempty"`
+	Options  json.RawMessage `json:"options,omitempty"`
+}
+
+// internal function
+// newExportField converts a schema field
+func newExportField(f Field) (exportField, error) {
+	ef := exportField{Name: f.Name, Type: fieldTypeNames[f.Type], Sortable: f.Sortable}
+	if ef.Type == "" {
+		return ef, fmt.Errorf("unrecognized type %v of field %s", f.Type, f.Name)
+	}
+	if f.Options != nil {
+		options, err := json.Marshal(f.Options)
+		if err != nil {
+			return ef, err
+		}
+		ef.Options = options
+	}
+	return ef, nil
+}
+
+// internal method
+// field converts an exported field back to a schema field
+func (ef exportField) field() (Field, error) {
+	f := Field{Name: ef.Name, Sortable: ef.Sortable}
+	var options interface{}
+	switch ef.Type {
+	case "text":
+		f.Type = TextField
+		options = &TextFieldOptions{}
+	case "numeric":
+		f.Type = NumericField
+		options = &NumericFieldOptions{}
+	case "geo":
+		f.Type = GeoField
+		options = &GeoFieldOptions{}
+	case "tag":
+		f.Type = TagField
+		options = &TagFieldOptions{}
+	case "vector":
+		f.Type = VectorField
+		options = &VectorFieldOptions{}
+	default:
+		return f, fmt.Errorf("unrecognized type %q of field %s", ef.Type, ef.Name)
+	}
+	if ef.Options == nil {
+		return f, nil
+	}
+	if err := json.Unmarshal(ef.Options, options); err != nil {
+		return f, fmt.Errorf("invalid options of field %s: %v", ef.Name, err)
+	}
+	// dereference, serializeField expects the options by value
+	switch o := options.(type) {
+	case *TextFieldOptions:
+		f.Options = *o
+	case *NumericFieldOptions:
+		f.Options = *o
+	case *GeoFieldOptions:
+		f.Options = *o
+	case *TagFieldOptions:
+		f.Options = *o
+	case *VectorFieldOptions:
+		f.Options = *o
+	}
+	return f, nil
+}
+
+// ExportIndex writes the index with the default options as JSON Lines: its schema and definition,
+// its synonyms and its documents. See ExportIndexOptions.
+func (i *Client) ExportIndex(w io.Writer) error {
+	return i.ExportIndexOptions(DefaultExportOptions, w)
+}
+
+// ExportIndexOptions writes the index as JSON Lines, one record per line: its schema and definition,
+// the aliases and dictionaries listed in the options, its synonym groups and its documents.
+// The documents are the hashes (or JSON values) themselves, not their indexed form, so that the export
+// can be imported with ImportIndex by another version of RediSearch.
+// The aliases of the index are exported only when they are named in opts.Aliases.
+func (i *Client) ExportIndexOptions(opts ExportOptions, w io.Writer) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultExportOptions.BatchSize
+	}
+	info, err := i.Info()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+
+	index := exportRecord{
+		Type:       exportIndexRecord,
+		Version:    exportVersion,
+		Name:       info.Name,
+		Options:    &info.Schema.Options,
+		Definition: info.Definition,
+		Schema:     make([]exportField, 0, len(info.Schema.Fields)),
+	}
+	for _, f := range info.Schema.Fields {
+		ef, err := newExportField(f)
+		if err != nil {
+			return fmt.Errorf("ExportIndex: %v", err)
+		}
+		index.Schema = append(index.Schema, ef)
+	}
+	if err := enc.Encode(index); err != nil {
+		return err
+	}
+
+	aliases, err := NewAdminFromPool(i.pool).ResolveAliases(opts.Aliases...)
+	if err != nil {
+		return err
+	}
+	for _, alias := range opts.Aliases {
+		if aliases[alias] == info.Name {
+			if err := enc.Encode(exportRecord{Type: exportAliasRecord, Name: alias}); err != nil {
+				return err
+			}
+		}
+	}
+
+	groups, err := i.synonymGroups(info.Name)
+	if err != nil {
+		return err
+	}
+	groupIds := make([]string, 0, len(groups))
+	for group := range groups {
+		groupIds = append(groupIds, group)
+	}
+	sort.Strings(groupIds)
+	for _, group := range groupIds {
+		if err := enc.Encode(exportRecord{Type: exportSynonymsRecord, Group: group, Terms: groups[group]}); err != nil {
+			return err
+		}
+	}
+
+	for _, dict := range opts.Dictionaries {
+		terms, err := i.DictDump(dict)
+		if err != nil {
+			return fmt.Errorf("ExportIndex: could not dump dictionary %s: %v", dict, err)
+		}
+		if err := enc.Encode(exportRecord{Type: exportDictRecord, Name: dict, Terms: terms}); err != nil {
+			return err
+		}
+	}
+
+	onJSON := info.Definition != nil && strings.EqualFold(info.Definition.IndexOn, JSON.String())
+	switch opts.Documents {
+	case ExportScan:
+		var prefixes []string
+		if info.Definition != nil {
+			prefixes = info.Definition.Prefix
+		}
+		return i.exportScan(prefixes, onJSON, opts.BatchSize, enc)
+	case ExportSearch:
+		return i.exportSearch(onJSON, opts.BatchSize, enc)
+	}
+	return nil
+}
+
+// internal method
+// synonymGroups returns the terms of each synonym group of the index.
+// Unlike SynDump, the group ids are kept as strings since RediSearch 2.0 accepts any group id.
+func (i *Client) synonymGroups(index string) (map[string][]string, error) {
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	values, err := replyValues(conn.Do("FT.SYNDUMP", index))
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]string)
+	for ii := 0; ii+1 < len(values); ii += 2 {
+		term, err := redis.String(values[ii], nil)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := redis.Strings(values[ii+1], nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			groups[id] = append(groups[id], term)
+		}
+	}
+	for _, terms := range groups {
+		sort.Strings(terms)
+	}
+	return groups, nil
+}
+
+// internal method
+// exportScan exports the keys matching the prefixes, SCANned by batches
+func (i *Client) exportScan(prefixes []string, onJSON bool, batchSize int, enc *json.Encoder) error {
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	keyType := "hash"
+	if onJSON {
+		keyType = "ReJSON-RL"
+	}
+	patterns := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			patterns = nil
+			break
+		}
+		patterns = append(patterns, escapeGlob(prefix)+"*")
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	for _, pattern := range patterns {
+		cursor := "0"
+		for {
+			values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", batchSize, "TYPE", keyType))
+			if err != nil {
+				return err
+			}
+			if len(values) != 2 {
+				return fmt.Errorf("ExportIndex: unexpected SCAN reply %v", values)
+			}
+			cursor, _ = redis.String(values[0], nil)
+			keys, err := redis.Strings(values[1], nil)
+			if err != nil {
+				return err
+			}
+			if err := exportDocuments(conn, keys, onJSON, enc); err != nil {
+				return err
+			}
+			if cursor == "0" {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// internal method
+// exportSearch exports the documents of the index, searched by pages
+func (i *Client) exportSearch(onJSON bool, batchSize int, enc *json.Encoder) error {
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	q := NewQuery("*").SetFlags(QueryNoContent)
+	for offset := 0; ; offset += batchSize {
+		docs, total, err := i.Search(q.Limit(offset, batchSize))
+		if err != nil {
+			return err
+		}
+		keys := make([]string, len(docs))
+		for ii, doc := range docs {
+			keys[ii] = doc.Id
+		}
+		if err := exportDocuments(conn, keys, onJSON, enc); err != nil {
+			return err
+		}
+		if len(docs) == 0 || offset+batchSize >= total {
+			return nil
+		}
+	}
+}
+
+// internal function
+// exportDocuments fetches the keys in a single pipeline and writes them as doc records.
+// Keys that were deleted or that hold another type in the meantime are skipped.
+func exportDocuments(conn redis.Conn, keys []string, onJSON bool, enc *json.Encoder) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	for _, key := range keys {
+		var err error
+		if onJSON {
+			err = conn.Send("JSON.GET", key, "$")
+		} else {
+			err = conn.Send("HGETALL", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+	records := make([]*exportRecord, 0, len(keys))
+	for _, key := range keys {
+		reply, err := conn.Receive()
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "WRONGTYPE") {
+				continue
+			}
+			return err
+		}
+		var rec *exportRecord
+		if onJSON {
+			rec, err = jsonDocumentRecord(key, reply)
+		} else {
+			rec, err = hashDocumentRecord(key, reply)
+		}
+		if err != nil {
+			return fmt.Errorf("ExportIndex: could not read %s: %v", key, err)
+		}
+		if rec != nil {
+			records = append(records, rec)
+		}
+	}
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// internal function
+// hashDocumentRecord converts a HGETALL reply, nil if the hash does not exist
+func hashDocumentRecord(key string, reply interface{}) (*exportRecord, error) {
+	values, err := replyValues(reply, nil)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	rec := &exportRecord{Type: exportDocRecord, Key: key}
+	for ii := 0; ii+1 < len(values); ii += 2 {
+		name, err := redis.String(values[ii], nil)
+		if err != nil {
+			return nil, err
+		}
+		value, err := redis.Bytes(values[ii+1], nil)
+		if err != nil {
+			return nil, err
+		}
+		if utf8.Valid(value) {
+			if rec.Fields == nil {
+				rec.Fields = make(map[string]string)
+			}
+			rec.Fields[name] = string(value)
+		} else {
+			if rec.Binary == nil {
+				rec.Binary = make(map[string][]byte)
+			}
+			rec.Binary[name] = value
+		}
+	}
+	return rec, nil
+}
+
+// internal function
+// jsonDocumentRecord converts a JSON.GET key $ reply, nil if the key does not exist
+func jsonDocumentRecord(key string, reply interface{}) (*exportRecord, error) {
+	if reply == nil {
+		return nil, nil
+	}
+	value, err := redis.Bytes(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	var roots []json.RawMessage
+	if err := json.Unmarshal(value, &roots); err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+	return &exportRecord{Type: exportDocRecord, Key: key, JSON: roots[0]}, nil
+}
+
+// internal function
+// escapeGlob escapes the special characters of a SCAN MATCH pattern
+func escapeGlob(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// ImportIndex recreates an index exported by ExportIndex under the name of the target client,
+// with the default options. See ImportIndexOptions.
+func ImportIndex(r io.Reader, target *Client) error {
+	return ImportIndexOptions(DefaultImportOptions, r, target)
+}
+
+// ImportIndexOptions recreates an index exported by ExportIndex under the name of the target client:
+// it creates the index, then applies the aliases, synonym groups and dictionaries, and writes the documents
+// by pipelined batches. The documents keep their keys, existing keys are overwritten.
+// The target index must not exist.
+func ImportIndexOptions(opts ImportOptions, r io.Reader, target *Client) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultImportOptions.BatchSize
+	}
+	conn := target.pool.Get()
+	defer conn.Close()
+
+	dec := json.NewDecoder(r)
+	var pending []string
+	// flush receives the replies of the pending document writes
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := conn.Flush(); err != nil {
+			return err
+		}
+		var err error
+		for _, key := range pending {
+			if _, rerr := conn.Receive(); rerr != nil && err == nil {
+				err = fmt.Errorf("ImportIndex: could not write %s: %v", key, rerr)
+			}
+		}
+		pending = pending[:0]
+		return err
+	}
+
+	for line := 1; ; line++ {
+		var rec exportRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			if line == 1 {
+				return fmt.Errorf("ImportIndex: empty export")
+			}
+			break
+		} else if err != nil {
+			return fmt.Errorf("ImportIndex: record %d: %v", line, err)
+		}
+		if (line == 1) != (rec.Type == exportIndexRecord) {
+			return fmt.Errorf("ImportIndex: record %d: the index record must be the first one", line)
+		}
+		if rec.Type != exportDocRecord {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch rec.Type {
+		case exportIndexRecord:
+			err = importIndex(&rec, target)
+		case exportAliasRecord:
+			if !opts.SkipAliases {
+				err = target.AliasUpdate(rec.Name)
+			}
+		case exportSynonymsRecord:
+			_, err = conn.Do("FT.SYNUPDATE", redis.Args{target.name, rec.Group}.AddFlat(rec.Terms)...)
+		case exportDictRecord:
+			if len(rec.Terms) > 0 {
+				_, err = target.DictAdd(rec.Name, rec.Terms)
+			}
+		case exportDocRecord:
+			var sent bool
+			if sent, err = sendDocument(conn, &rec); sent {
+				pending = append(pending, rec.Key)
+				if len(pending) >= opts.BatchSize {
+					err = flush()
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown record type %q", rec.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("ImportIndex: record %d: %v", line, err)
+		}
+	}
+	return flush()
+}
+
+// internal function
+// importIndex creates the target index from the index record
+func importIndex(rec *exportRecord, target *Client) error {
+	if rec.Version > exportVersion {
+		return fmt.Errorf("unsupported export version %d", rec.Version)
+	}
+	sc := NewSchema(DefaultOptions)
+	if rec.Options != nil {
+		sc.Options = *rec.Options
+	}
+	for _, ef := range rec.Schema {
+		f, err := ef.field()
+		if err != nil {
+			return err
+		}
+		sc.AddField(f)
+	}
+	if rec.Definition == nil {
+		return target.CreateIndex(sc)
+	}
+	return target.CreateIndexWithIndexDefinition(sc, rec.Definition)
+}
+
+// internal function
+// sendDocument sends the write of a doc record, and returns whether a command was sent
+func sendDocument(conn redis.Conn, rec *exportRecord) (bool, error) {
+	if rec.Key == "" {
+		return false, fmt.Errorf("document without key")
+	}
+	if rec.JSON != nil {
+		return true, conn.Send("JSON.SET", rec.Key, "$", []byte(rec.JSON))
+	}
+	if len(rec.Fields)+len(rec.Binary) == 0 {
+		return false, nil
+	}
+	args := redis.Args{rec.Key}
+	names := make([]string, 0, len(rec.Fields)+len(rec.Binary))
+	for name := range rec.Fields {
+		names = append(names, name)
+	}
+	for name := range rec.Binary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := rec.Fields[name]; ok {
+			args = append(args, name, value)
+		} else {
+			args = append(args, name, rec.Binary[name])
+		}
+	}
+	return true, conn.Send("HSET", args...)
+}
@@ -0,0 +1,144 @@
+package redisearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// createExportIndex creates an index with documents, synonyms, a dictionary and an alias
+func createExportIndex(t *testing.T, c *Client) {
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextFieldOptions("title", TextFieldOptions{Weight: 2, Sortable: true})).
+		AddField(NewTagFieldOptions("brand", TagFieldOptions{Separator: ';', As: "maker"})).
+		AddField(NewNumericField("price")).
+		AddField(NewVectorFieldOptions("vec", VectorFieldOptions{Algorithm: Flat, Attributes: map[string]interface{}{
+			"TYPE":            "FLOAT32",
+			"DIM":             2,
+			"DISTANCE_METRIC": "L2",
+		}}))
+	assert.Nil(t, c.CreateIndexWithIndexDefinition(sc, NewIndexDefinition().AddPrefix("export:")))
+
+	conn := c.pool.Get()
+	defer conn.Close()
+	for _, args := range [][]interface{}{
+		{"export:1", "title", "red shoes", "brand", "acme", "price", 50, "vec", []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0x40}},
+		{"export:2", "title", "blue shoes", "brand", "globex", "price", 80},
+		{"export:3", "title", "red hat", "brand", "acme;globex", "price", 20},
+		{"other:1", "title", "red socks"},
+	} {
+		_, err := conn.Do("HSET", args...)
+		assert.Nil(t, err)
+	}
+	_, err := conn.Do("SET", "export:string", "not a hash")
+	assert.Nil(t, err)
+
+	_, err = c.SynUpdate(c.name, 1, []string{"shoes", "sneakers"})
+	assert.Nil(t, err)
+	_, err = c.DictAdd("export-brands", []string{"acme", "globex"})
+	assert.Nil(t, err)
+	assert.Nil(t, c.AliasAdd("export-alias"))
+}
+
+// exportTypes returns the record types of an export
+func exportTypes(t *testing.T, export string) []string {
+	var types []string
+	for _, line := range strings.Split(strings.TrimSpace(export), "\n") {
+		var rec exportRecord
+		assert.Nil(t, json.Unmarshal([]byte(line), &rec), line)
+		types = append(types, rec.Type)
+	}
+	return types
+}
+
+func TestClient_ExportIndex(t *testing.T) {
+	c := createClient("export-test")
+	flush(c)
+	defer teardown(c)
+	createExportIndex(t, c)
+	source, err := c.Info()
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	opts := DefaultExportOptions
+	opts.Aliases = []string{"export-alias", "missing-alias"}
+	opts.Dictionaries = []string{"export-brands"}
+	opts.BatchSize = 2
+	assert.Nil(t, c.ExportIndexOptions(opts, &buf))
+	export := buf.String()
+	assert.Equal(t, []string{"index", "alias", "synonyms", "dict", "doc", "doc", "doc"}, exportTypes(t, export))
+	assert.Contains(t, export, `{"type":"synonyms","group":"1","terms":["shoes","sneakers"]}`)
+	assert.Contains(t, export, `{"type":"doc","key":"export:1","fields":{"brand":"acme","price":"50","title":"red shoes"},"binary":{"vec":"AACAPwAAAEA="}}`)
+
+	// restore the export on an empty database, under another name
+	flush(c)
+	target := createClient("export-test-copy")
+	assert.Nil(t, ImportIndexOptions(ImportOptions{BatchSize: 2}, strings.NewReader(export), target))
+
+	info, err := target.Info()
+	assert.Nil(t, err)
+	assert.Equal(t, "export-test-copy", info.Name)
+	assert.Equal(t, uint64(3), info.DocCount)
+	assert.Equal(t, source.Schema, info.Schema)
+	assert.Equal(t, source.Definition, info.Definition)
+
+	groups, err := target.SynDump(target.name)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]int64{"shoes": {1}, "sneakers": {1}}, groups)
+	terms, err := target.DictDump("export-brands")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"acme", "globex"}, terms)
+	aliased, err := createClient("export-alias").Info()
+	assert.Nil(t, err)
+	assert.Equal(t, "export-test-copy", aliased.Name)
+
+	conn := target.pool.Get()
+	defer conn.Close()
+	vec, err := redis.Bytes(conn.Do("HGET", "export:1", "vec"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0x40}, vec)
+	exists, err := redis.Int(conn.Do("EXISTS", "other:1"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, exists)
+}
+
+func TestClient_ExportIndex_documents(t *testing.T) {
+	c := createClient("export-test")
+	flush(c)
+	defer teardown(c)
+	createExportIndex(t, c)
+
+	var buf bytes.Buffer
+	assert.Nil(t, c.ExportIndexOptions(ExportOptions{Documents: ExportSearch, BatchSize: 2}, &buf))
+	assert.Equal(t, []string{"index", "synonyms", "doc", "doc", "doc"}, exportTypes(t, buf.String()))
+
+	buf.Reset()
+	assert.Nil(t, c.ExportIndexOptions(ExportOptions{Documents: ExportNoDocuments}, &buf))
+	assert.Equal(t, []string{"index", "synonyms"}, exportTypes(t, buf.String()))
+}
+
+func TestImportIndex_errors(t *testing.T) {
+	c := createClient("import-test")
+	flush(c)
+	defer teardown(c)
+	tests := []struct {
+		export string
+		err    string
+	}{
+		{"", "ImportIndex: empty export"},
+		{`{"type":"doc","key":"k","fields":{"a":"b"}}`, "ImportIndex: record 1: the index record must be the first one"},
+		{`{"type":"index","version":99}`, "ImportIndex: record 1: unsupported export version 99"},
+		{`{"type":"index","version":1,"schema":[{"name":"a","type":"blob"}]}`, `ImportIndex: record 1: unrecognized type "blob" of field a`},
+		{`{"type":"index","version":1,"schema":[{"name":"a","type":"text"}]}` + "\n" + `{"type":"view"}`, `ImportIndex: record 2: unknown record type "view"`},
+		{`{"type":"index","version":1,"schema":[{"name":"a","type":"text"}]}` + "\n" + `{"type":"doc"`, "ImportIndex: record 2: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		c.Drop()
+		err := ImportIndex(strings.NewReader(tt.export), c)
+		assert.EqualError(t, err, tt.err, tt.export)
+	}
+}
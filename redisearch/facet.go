@@ -0,0 +1,427 @@
+package redisearch
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FacetType is an enumeration of the supported facet kinds
+type FacetType int
+
+const (
+	// TagFacet counts the documents per distinct value of a tag field
+	TagFacet FacetType = iota
+
+	// RangeFacet counts the documents falling in each of a list of numeric ranges
+	RangeFacet
+
+	// DateHistogramFacet counts the documents per fixed-width bucket of a numeric (timestamp) field
+	DateHistogramFacet
+)
+
+// DefaultFacetLimit is the default number of values returned for tag and date histogram facets
+const DefaultFacetLimit = 10
+
+// FacetRange is a named numeric range of a RangeFacet, including Min and excluding Max.
+// Use math.Inf() for open ranges.
+type FacetRange struct {
+	Name string
+	Min  float64
+	Max  float64
+}
+
+// Facet is the definition of a facet computed by FacetedSearch
+type Facet struct {
+	// Field is the name of the indexed field, without the '@' prefix
+	Field string
+	Type  FacetType
+
+	// Limit is the maximal number of values returned for tag and date histogram facets
+	Limit int
+
+	// Separator is the separator of the values of a TagFacet, as declared in the tag field options. Defaults to ','
+	Separator byte
+
+	// Ranges are the buckets of a RangeFacet
+	Ranges []FacetRange
+
+	// Interval is the bucket width of a DateHistogramFacet, in the unit of the field values (e.g. seconds)
+	Interval int64
+}
+
+// NewTagFacet creates a facet counting the top values of a tag field
+func NewTagFacet(field string, limit int) Facet {
+	return Facet{Field: field, Type: TagFacet, Limit: limit}
+}
+
+// NewRangeFacet creates a facet counting the documents in each numeric range
+func NewRangeFacet(field string, ranges ...FacetRange) Facet {
+	return Facet{Field: field, Type: RangeFacet, Ranges: ranges}
+}
+
+// NewDateHistogramFacet creates a facet counting the documents per bucket of the given interval
+func NewDateHistogramFacet(field string, interval int64) Facet {
+	return Facet{Field: field, Type: DateHistogramFacet, Interval: interval}
+}
+
+// FacetSelection maps a facet field to its selected values: tag values for tag facets,
+// range names for range facets, and bucket start values for date histogram facets
+type FacetSelection map[string][]string
+
+// FacetValue is a single value of a facet and the number of matching documents
+type FacetValue struct {
+	Value    string
+	Count    int
+	Selected bool
+}
+
+// FacetResult holds the values of a single facet
+type FacetResult struct {
+	Field  string
+	Values []FacetValue
+	Err    error
+}
+
+// FacetedSearchResult holds the search hits and the facet counts returned by FacetedSearch
+type FacetedSearchResult struct {
+	Docs     []Document
+	Total    int
+	Facets   []FacetResult
+	Warnings []string
+}
+
+// internal struct
+// facetRequest links a pipelined request to the facet (and range) it counts
+type facetRequest struct {
+	facet    int
+	rangeIdx int
+}
+
+// FacetedSearch runs the query restricted to the selected facet values, and computes the counts of each facet,
+// all in a single pipeline. Facets use multi-select semantics: the counts of a facet are computed with the
+// selections of all the other facets, but not its own, so that other values of the facet can still be selected.
+func (i *Client) FacetedSearch(q *Query, facets []Facet, selected FacetSelection) (*FacetedSearchResult, error) {
+	requests, owners, err := buildFacetRequests(q, facets, selected)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := i.MultiSearch(requests...)
+	if err != nil {
+		return nil, err
+	}
+
+	if replies[0].Err != nil {
+		return nil, replies[0].Err
+	}
+	ret := &FacetedSearchResult{
+		Docs:     replies[0].Docs,
+		Total:    replies[0].Total,
+		Facets:   make([]FacetResult, len(facets)),
+		Warnings: replies[0].Warnings,
+	}
+	for fi, f := range facets {
+		ret.Facets[fi].Field = f.Field
+		if f.Type == RangeFacet {
+			ret.Facets[fi].Values = make([]FacetValue, len(f.Ranges))
+		}
+	}
+	for ri, owner := range owners {
+		reply := replies[ri+1]
+		f := facets[owner.facet]
+		result := &ret.Facets[owner.facet]
+		if reply.Err != nil {
+			result.Err = reply.Err
+			continue
+		}
+		switch f.Type {
+		case RangeFacet:
+			name := f.Ranges[owner.rangeIdx].Name
+			result.Values[owner.rangeIdx] = FacetValue{
+				Value:    name,
+				Count:    reply.Total,
+				Selected: isFacetSelected(selected, f.Field, name),
+			}
+		case TagFacet, DateHistogramFacet:
+			key := f.Field
+			if f.Type == DateHistogramFacet {
+				key = facetBucketAlias
+			}
+			for _, row := range reply.AggregateReply {
+				value, _ := row[key].(string)
+				countStr, _ := row["count"].(string)
+				count, err := strconv.Atoi(countStr)
+				if err != nil {
+					result.Err = fmt.Errorf("FacetedSearch: could not parse count of facet %s: %v", f.Field, err)
+					break
+				}
+				result.Values = append(result.Values, FacetValue{
+					Value:    value,
+					Count:    count,
+					Selected: isFacetSelected(selected, f.Field, value),
+				})
+			}
+		}
+	}
+	return ret, nil
+}
+
+const facetBucketAlias = "__bucket"
+
+// internal function
+// buildFacetRequests returns the search request for the hits, followed by the requests computing the facets.
+// owners links each facet request to its facet, and is aligned with requests[1:]
+func buildFacetRequests(q *Query, facets []Facet, selected FacetSelection) (requests []SearchRequest, owners []facetRequest, err error) {
+	filters := make([]string, len(facets))
+	known := make(map[string]bool, len(facets))
+	for fi, f := range facets {
+		if f.Field == "" {
+			return nil, nil, fmt.Errorf("FacetedSearch: facet %d has no field", fi)
+		}
+		known[f.Field] = true
+		if filters[fi], err = f.filter(selected[f.Field]); err != nil {
+			return nil, nil, err
+		}
+	}
+	for field := range selected {
+		if !known[field] {
+			return nil, nil, fmt.Errorf("FacetedSearch: selection on unknown facet %s", field)
+		}
+	}
+
+	hits := *q
+	hits.Raw = facetQueryString(q.Raw, filters, -1)
+	requests = append(requests, NewSearchRequest(&hits))
+
+	aggregateRaw := facetAggregateQueryString(q)
+	for fi, f := range facets {
+		raw := facetQueryString(q.Raw, filters, fi)
+		switch f.Type {
+		case TagFacet:
+			// each value of a multi-value tag is counted separately
+			separator := f.Separator
+			if separator == 0 {
+				separator = ','
+			}
+			split := fmt.Sprintf(`split(@%s, "%s")`, f.Field, EscapeExpressionString(string(separator)))
+			agg := newFacetAggregate(q, facetQueryString(aggregateRaw, filters, fi)).
+				Load([]string{f.Field}).
+				Apply(*NewProjection(split, f.Field)).
+				GroupBy(*NewGroupBy().AddFields("@" + f.Field).Reduce(*Count())).
+				SortBy([]SortingKey{*NewSortingKeyDir("@count", false)}).
+				Limit(0, facetLimit(f))
+			requests = append(requests, NewAggregateRequest(agg))
+			owners = append(owners, facetRequest{facet: fi})
+		case RangeFacet:
+			for ri, r := range f.Ranges {
+				count := facetCountQuery(q, facetQueryString(raw, []string{rangeFilter(f.Field, r)}, -1))
+				requests = append(requests, NewSearchRequest(count))
+				owners = append(owners, facetRequest{facet: fi, rangeIdx: ri})
+			}
+		case DateHistogramFacet:
+			if f.Interval <= 0 {
+				return nil, nil, fmt.Errorf("FacetedSearch: date histogram facet %s requires a positive interval", f.Field)
+			}
+			bucket := fmt.Sprintf("floor(@%s/%d)*%d", f.Field, f.Interval, f.Interval)
+			agg := newFacetAggregate(q, facetQueryString(aggregateRaw, filters, fi)).
+				Load([]string{f.Field}).
+				Apply(*NewProjection(bucket, facetBucketAlias)).
+				GroupBy(*NewGroupBy().AddFields("@" + facetBucketAlias).Reduce(*Count())).
+				SortBy([]SortingKey{*NewSortingKeyDir("@"+facetBucketAlias, true)}).
+				Limit(0, facetLimit(f))
+			requests = append(requests, NewAggregateRequest(agg))
+			owners = append(owners, facetRequest{facet: fi})
+		default:
+			return nil, nil, fmt.Errorf("FacetedSearch: unknown type %d of facet %s", f.Type, f.Field)
+		}
+	}
+	return
+}
+
+// internal function
+// facetCountQuery creates a query counting the documents matching the given query string,
+// with the filtering options of q: filters, keys, fields, matching flags, language, expander, params and dialect
+func facetCountQuery(q *Query, raw string) *Query {
+	count := *q
+	count.Raw = raw
+	count.Paging = Paging{0, 0}
+	count.Flags = q.Flags&(QueryVerbatim|QueryInOrder|QueryWithStopWords) | QueryNoContent
+	count.ReturnFields = nil
+	count.Scorer = ""
+	count.Payload = nil
+	count.SortBy = nil
+	count.HighlightOpts = nil
+	count.SummarizeOpts = nil
+	return &count
+}
+
+// internal function
+// newFacetAggregate creates an aggregation on the given query string, with the filtering options of q
+// that FT.AGGREGATE accepts as arguments (VERBATIM, params and dialect) or as steps (the keys).
+// The other filtering options are in the query string built by facetAggregateQueryString.
+func newFacetAggregate(q *Query, raw string) *AggregateQuery {
+	agg := NewAggregateQuery().
+		SetQuery(NewQuery(raw)).
+		SetVerbatim(q.Flags&QueryVerbatim != 0).
+		SetParams(q.Params).
+		SetDialect(q.Dialect)
+	if len(q.InKeys) > 0 {
+		keys := make([]string, len(q.InKeys))
+		for ki, key := range q.InKeys {
+			keys[ki] = `@__key == "` + EscapeExpressionString(key) + `"`
+		}
+		agg.Load([]string{"__key"}).Filter(strings.Join(keys, " || "))
+	}
+	return agg
+}
+
+// internal function
+// facetAggregateQueryString returns the query string of q with the filtering options that FT.AGGREGATE does not
+// accept as arguments: the fields restrict the query with a field modifier, the slop and order are set as query
+// attributes, and the numeric and geo filters are intersected with the query
+func facetAggregateQueryString(q *Query) string {
+	raw := strings.TrimSpace(q.Raw)
+	if raw != "" && raw != "*" {
+		if len(q.InFields) > 0 {
+			raw = fmt.Sprintf("@%s:(%s)", facetFieldList(q.InFields), raw)
+		}
+		var attributes []string
+		if q.Slop != nil {
+			attributes = append(attributes, fmt.Sprintf("$slop: %d;", *q.Slop))
+		}
+		if q.Flags&QueryInOrder != 0 {
+			attributes = append(attributes, "$inorder: true;")
+		}
+		if len(attributes) > 0 {
+			raw = fmt.Sprintf("(%s)=>{%s}", raw, strings.Join(attributes, " "))
+		}
+	}
+	clauses := make([]string, 0, len(q.Filters))
+	for _, f := range q.Filters {
+		switch opts := f.Options.(type) {
+		case NumericFilterOptions:
+			min, max := formatFacetBound(opts.Min), formatFacetBound(opts.Max)
+			if opts.ExclusiveMin && !math.IsInf(opts.Min, 0) {
+				min = "(" + min
+			}
+			if opts.ExclusiveMax && !math.IsInf(opts.Max, 0) {
+				max = "(" + max
+			}
+			clauses = append(clauses, fmt.Sprintf("@%s:[%s %s]", f.Field, min, max))
+		case GeoFilterOptions:
+			clauses = append(clauses, fmt.Sprintf("@%s:[%s %s %s %s]", f.Field, formatFacetBound(opts.Lon),
+				formatFacetBound(opts.Lat), formatFacetBound(opts.Radius), opts.Unit))
+		}
+	}
+	return facetQueryString(raw, clauses, -1)
+}
+
+// internal function
+// facetFieldList returns the field list of a field modifier, e.g. title or (title|body)
+func facetFieldList(fields []string) string {
+	if len(fields) == 1 {
+		return fields[0]
+	}
+	return "(" + strings.Join(fields, "|") + ")"
+}
+
+// filter returns the query filter matching the selected values, or "" if none is selected
+func (f Facet) filter(values []string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	clauses := make([]string, 0, len(values))
+	switch f.Type {
+	case TagFacet:
+		escaped := make([]string, len(values))
+		for vi, v := range values {
+			escaped[vi] = EscapeTagValue(v)
+		}
+		return fmt.Sprintf("@%s:{%s}", f.Field, strings.Join(escaped, "|")), nil
+	case RangeFacet:
+		for _, v := range values {
+			found := false
+			for _, r := range f.Ranges {
+				if r.Name == v {
+					clauses = append(clauses, rangeFilter(f.Field, r))
+					found = true
+					break
+				}
+			}
+			if !found {
+				return "", fmt.Errorf("FacetedSearch: unknown range %s of facet %s", v, f.Field)
+			}
+		}
+	case DateHistogramFacet:
+		for _, v := range values {
+			start, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return "", fmt.Errorf("FacetedSearch: invalid bucket %s of facet %s: %v", v, f.Field, err)
+			}
+			clauses = append(clauses, rangeFilter(f.Field, FacetRange{Min: start, Max: start + float64(f.Interval)}))
+		}
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return "(" + strings.Join(clauses, "|") + ")", nil
+}
+
+// internal function
+// rangeFilter returns a numeric filter including r.Min and excluding r.Max
+func rangeFilter(field string, r FacetRange) string {
+	max := formatFacetBound(r.Max)
+	if !math.IsInf(r.Max, 0) {
+		max = "(" + max
+	}
+	return fmt.Sprintf("@%s:[%s %s]", field, formatFacetBound(r.Min), max)
+}
+
+func formatFacetBound(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+inf"
+	}
+	if math.IsInf(v, -1) {
+		return "-inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// internal function
+// facetQueryString intersects the query with all the facet filters, except the one at position skip
+func facetQueryString(raw string, filters []string, skip int) string {
+	active := make([]string, 0, len(filters))
+	for fi, filter := range filters {
+		if fi != skip && filter != "" {
+			active = append(active, filter)
+		}
+	}
+	raw = strings.TrimSpace(raw)
+	if len(active) == 0 {
+		if raw == "" {
+			return "*"
+		}
+		return raw
+	}
+	if raw != "" && raw != "*" {
+		active = append([]string{"(" + raw + ")"}, active...)
+	}
+	return strings.Join(active, " ")
+}
+
+func facetLimit(f Facet) int {
+	if f.Limit > 0 {
+		return f.Limit
+	}
+	return DefaultFacetLimit
+}
+
+func isFacetSelected(selected FacetSelection, field, value string) bool {
+	for _, v := range selected[field] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
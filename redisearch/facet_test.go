@@ -0,0 +1,214 @@
+package redisearch
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacet_filter(t *testing.T) {
+	ranges := []FacetRange{{"cheap", math.Inf(-1), 10}, {"mid", 10, 100}, {"expensive", 100, math.Inf(1)}}
+	tests := []struct {
+		name    string
+		facet   Facet
+		values  []string
+		want    string
+		wantErr bool
+	}{
+		{"none", NewTagFacet("brand", 5), nil, "", false},
+		{"tag", NewTagFacet("brand", 5), []string{"sony"}, "@brand:{sony}", false},
+		{"tag-multi-escaped", NewTagFacet("brand", 5), []string{"sony", "hello world"}, "@brand:{sony|hello\\ world}", false},
+		{"range", NewRangeFacet("price", ranges...), []string{"mid"}, "@price:[10 (100]", false},
+		{"range-open", NewRangeFacet("price", ranges...), []string{"cheap", "expensive"}, "(@price:[-inf (10]|@price:[100 +inf])", false},
+		{"range-unknown", NewRangeFacet("price", ranges...), []string{"free"}, "", true},
+		{"histogram", NewDateHistogramFacet("ts", 3600), []string{"7200"}, "@ts:[7200 (10800]", false},
+		{"histogram-invalid", NewDateHistogramFacet("ts", 3600), []string{"yesterday"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.facet.filter(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("filter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_facetQueryString(t *testing.T) {
+	filters := []string{"@brand:{sony}", "", "@price:[10 (100]"}
+	assert.Equal(t, "(hello) @brand:{sony} @price:[10 (100]", facetQueryString("hello", filters, -1))
+	assert.Equal(t, "(hello) @price:[10 (100]", facetQueryString("hello", filters, 0))
+	assert.Equal(t, "@brand:{sony}", facetQueryString("*", filters, 2))
+	assert.Equal(t, "hello", facetQueryString("hello", []string{""}, -1))
+	assert.Equal(t, "*", facetQueryString("", nil, -1))
+}
+
+func Test_buildFacetRequests(t *testing.T) {
+	facets := []Facet{
+		NewTagFacet("brand", 5),
+		NewRangeFacet("price", FacetRange{"cheap", 0, 10}, FacetRange{"expensive", 10, math.Inf(1)}),
+		NewDateHistogramFacet("ts", 60),
+	}
+	selected := FacetSelection{"brand": {"sony"}, "price": {"cheap"}}
+	requests, owners, err := buildFacetRequests(NewQuery("game").Limit(0, 20), facets, selected)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(requests))
+	assert.Equal(t, []facetRequest{{0, 0}, {1, 0}, {1, 1}, {2, 0}}, owners)
+
+	c := &Client{name: "idx"}
+	want := [][]interface{}{
+		{"idx", "(game) @brand:{sony} @price:[0 (10]", "LIMIT", 0, 20},
+		{"idx", "(game) @price:[0 (10]", "LOAD", 1, "@brand", "APPLY", `split(@brand, ",")`, "AS", "brand", "GROUPBY", 1, "@brand", "REDUCE", "COUNT", 0, "AS", "count", "SORTBY", 2, "@count", "DESC", "LIMIT", 0, 5},
+		{"idx", "((game) @brand:{sony}) @price:[0 (10]", "LIMIT", 0, 0, "NOCONTENT"},
+		{"idx", "((game) @brand:{sony}) @price:[10 +inf]", "LIMIT", 0, 0, "NOCONTENT"},
+		{"idx", "(game) @brand:{sony} @price:[0 (10]", "LOAD", 1, "@ts", "APPLY", "floor(@ts/60)*60", "AS", "__bucket", "GROUPBY", 1, "@__bucket", "REDUCE", "COUNT", 0, "AS", "count", "SORTBY", 2, "@__bucket", "ASC", "LIMIT", 0, 10},
+	}
+	for ri, r := range requests {
+		_, args, err := c.searchRequestArgs(r)
+		assert.Nil(t, err)
+		if !reflect.DeepEqual(args, want[ri]) {
+			t.Errorf("request %d = %v, want %v", ri, args, want[ri])
+		}
+	}
+
+	_, _, err = buildFacetRequests(NewQuery("game"), facets, FacetSelection{"color": {"red"}})
+	assert.NotNil(t, err)
+	_, _, err = buildFacetRequests(NewQuery("game"), []Facet{NewDateHistogramFacet("ts", 0)}, nil)
+	assert.NotNil(t, err)
+}
+
+func Test_buildFacetRequests_tagSeparator(t *testing.T) {
+	facet := NewTagFacet("tags", 10)
+	facet.Separator = ';'
+	requests, _, err := buildFacetRequests(NewQuery("*"), []Facet{facet}, nil)
+	assert.Nil(t, err)
+	_, args, err := (&Client{name: "idx"}).searchRequestArgs(requests[1])
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"idx", "*", "LOAD", 1, "@tags", "APPLY", `split(@tags, ";")`, "AS", "tags",
+		"GROUPBY", 1, "@tags", "REDUCE", "COUNT", 0, "AS", "count", "SORTBY", 2, "@count", "DESC", "LIMIT", 0, 10}, args)
+}
+
+func Test_buildFacetRequests_filters(t *testing.T) {
+	q := NewQuery("game").
+		AddFilter(Filter{Field: "price", Options: NumericFilterOptions{Min: 10, Max: math.Inf(1), ExclusiveMin: true}}).
+		AddFilter(Filter{Field: "loc", Options: GeoFilterOptions{Lon: 2.35, Lat: 48.85, Radius: 10, Unit: KILOMETERS}}).
+		SetInKeys("doc1", `doc"2`).
+		SetInFields("title", "body").
+		SetDialect(2)
+	facets := []Facet{
+		NewTagFacet("brand", 5),
+		NewRangeFacet("year", FacetRange{"old", 0, 2000}),
+	}
+	requests, _, err := buildFacetRequests(q, facets, nil)
+	assert.Nil(t, err)
+
+	c := &Client{name: "idx"}
+	want := [][]interface{}{
+		{"idx", "game", "INKEYS", 2, "doc1", `doc"2`, "INFIELDS", 2, "title", "body",
+			"FILTER", "price", "(10", "+inf", "GEOFILTER", "loc", 2.35, 48.85, 10.0, KILOMETERS, "DIALECT", 2},
+		{"idx", "(@(title|body):(game)) @price:[(10 +inf] @loc:[2.35 48.85 10 km]",
+			"LOAD", 1, "@__key", "FILTER", `@__key == "doc1" || @__key == "doc\"2"`,
+			"LOAD", 1, "@brand", "APPLY", `split(@brand, ",")`, "AS", "brand",
+			"GROUPBY", 1, "@brand", "REDUCE", "COUNT", 0, "AS", "count", "SORTBY", 2, "@count", "DESC", "LIMIT", 0, 5, "DIALECT", 2},
+		{"idx", "(game) @year:[0 (2000]", "LIMIT", 0, 0, "NOCONTENT", "INKEYS", 2, "doc1", `doc"2`, "INFIELDS", 2, "title", "body",
+			"FILTER", "price", "(10", "+inf", "GEOFILTER", "loc", 2.35, 48.85, 10.0, KILOMETERS, "DIALECT", 2},
+	}
+	for ri, r := range requests {
+		_, args, err := c.searchRequestArgs(r)
+		assert.Nil(t, err)
+		assert.Equal(t, want[ri], args, "request %d", ri)
+	}
+
+	slop := 1
+	q = NewQuery("hello world").SetFlags(QueryInOrder | QueryVerbatim)
+	q.Slop = &slop
+	assert.Equal(t, "(hello world)=>{$slop: 1; $inorder: true;}", facetAggregateQueryString(q))
+	assert.Equal(t, "@price:[-inf 5]", facetAggregateQueryString(NewQuery("*").
+		AddFilter(Filter{Field: "price", Options: NumericFilterOptions{Min: math.Inf(-1), Max: 5}})))
+}
+
+func TestClient_FacetedSearch_filter(t *testing.T) {
+	c := createClient("testfacetedsearch-filter")
+	c.Drop()
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("title")).
+		AddField(NewSortableNumericField("price")).
+		AddField(NewNumericField("year"))
+	assert.Nil(t, c.CreateIndex(sc))
+	defer teardown(c)
+	docs := make([]Document, 10)
+	for i := 0; i < 10; i++ {
+		docs[i] = NewDocument(fmt.Sprintf("facet-filter-doc%d", i), 1).
+			Set("title", "game").
+			Set("price", i*10).
+			Set("year", 1995+i)
+	}
+	assert.Nil(t, c.Index(docs...))
+
+	q := NewQuery("game").AddFilter(Filter{Field: "price", Options: NumericFilterOptions{Min: 50, Max: math.Inf(1)}})
+	facets := []Facet{NewRangeFacet("year", FacetRange{"old", 0, 2000}, FacetRange{"new", 2000, math.Inf(1)})}
+	res, err := c.FacetedSearch(q, facets, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, res.Total)
+	// the counts describe the filtered hits, years 2000 to 2004
+	assert.Nil(t, res.Facets[0].Err)
+	assert.Equal(t, []FacetValue{{Value: "old", Count: 0}, {Value: "new", Count: 5}}, res.Facets[0].Values)
+}
+
+func TestClient_FacetedSearch(t *testing.T) {
+	skipInMemory(t, "FT.AGGREGATE")
+	c := createClient("testfacetedsearch")
+	c.Drop()
+
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("title")).
+		AddField(NewTagField("brand")).
+		AddField(NewSortableNumericField("price"))
+	if err := c.CreateIndex(sc); err != nil {
+		t.Fatal(err)
+	}
+	docs := make([]Document, 12)
+	for i := 0; i < 12; i++ {
+		docs[i] = NewDocument(fmt.Sprintf("facet-doc%d", i), 1).
+			Set("title", "game").
+			Set("brand", []string{"sony", "nintendo", "sega"}[i%3]).
+			Set("price", i*10)
+	}
+	// each value of a multi-value tag is counted
+	docs = append(docs, NewDocument("facet-doc-multi", 1).
+		Set("title", "game").
+		Set("brand", "sony,sega").
+		Set("price", 200))
+	assert.Nil(t, c.Index(docs...))
+
+	facets := []Facet{
+		NewTagFacet("brand", 10),
+		NewRangeFacet("price", FacetRange{"cheap", 0, 50}, FacetRange{"expensive", 50, math.Inf(1)}),
+	}
+	res, err := c.FacetedSearch(NewQuery("game"), facets, FacetSelection{"brand": {"sony"}})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, res.Total)
+
+	// the brand counts ignore the brand selection
+	assert.Nil(t, res.Facets[0].Err)
+	assert.Equal(t, 3, len(res.Facets[0].Values))
+	counts := map[string]int{}
+	for _, v := range res.Facets[0].Values {
+		counts[v.Value] = v.Count
+		assert.Equal(t, v.Value == "sony", v.Selected)
+	}
+	assert.Equal(t, map[string]int{"sony": 5, "sega": 5, "nintendo": 4}, counts)
+
+	// the price counts are restricted by the brand selection
+	assert.Nil(t, res.Facets[1].Err)
+	assert.Equal(t, 2, res.Facets[1].Values[0].Count)
+	assert.Equal(t, 3, res.Facets[1].Values[1].Count)
+	teardown(c)
+}
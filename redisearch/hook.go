@@ -0,0 +1,219 @@
+package redisearch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// CommandInfo describes a command run through the hooks of a Client or an Autocompleter
+type CommandInfo struct {
+	// Name and Args are the command sent to redis. BeforeCommand hooks can rewrite them.
+	Name string
+	Args []interface{}
+	// Context is the context of the command, context.Background() unless a BeforeCommand hook replaced it,
+	// e.g. with the context of the request being served, so that the next hooks can link their spans to it
+	Context context.Context
+	// Start is the time the command was sent
+	Start time.Time
+	// Duration, Reply and Err are set before the AfterCommand hooks are run. AfterCommand hooks can replace
+	// Reply and Err, e.g. to inject faults in tests.
+	Duration time.Duration
+	Reply    interface{}
+	Err      error
+	// Pipeline is set for commands sent in a pipeline, e.g. by IndexOptions or AddTerms. Their Duration
+	// includes the time spent waiting for the replies of the previous commands.
+	Pipeline *PipelineInfo
+}
+
+// PipelineInfo is shared by the commands sent in the same pipeline
+type PipelineInfo struct {
+	Start time.Time
+	// Commands is the number of commands sent in the pipeline so far
+	Commands int
+	// Replies is the number of replies received so far, including the reply of the current command.
+	// The pipeline is complete when it equals Commands.
+	Replies int
+}
+
+// Done returns whether all the replies of the pipeline have been received
+func (p *PipelineInfo) Done() bool {
+	return p.Replies == p.Commands
+}
+
+// Hook intercepts the commands of a Client or an Autocompleter, e.g. for logging, auditing, query rewriting
+// or fault injection
+type Hook interface {
+	// BeforeCommand is called before the command is sent. If it returns an error, the command is not sent,
+	// and the error is returned as the reply of the command (the AfterCommand hooks are still run).
+	BeforeCommand(cmd *CommandInfo) error
+	// AfterCommand is called once the reply of the command has been received
+	AfterCommand(cmd *CommandInfo)
+}
+
+// PipelineHook is implemented by the hooks that must know when a pipeline is abandoned before all its replies
+// were received: when flushing it failed, or when the connection was closed with pending replies. AfterCommand
+// is not called for the commands whose replies were not received.
+type PipelineHook interface {
+	Hook
+	AbortPipeline(pipeline *PipelineInfo, err error)
+}
+
+// ErrPipelineClosed is passed to PipelineHook.AbortPipeline when the connection is closed with pending replies
+var ErrPipelineClosed = errors.New("connection closed before all the pipeline replies were received")
+
+// AddHook registers hooks around all the commands of the client, including the pipelined ones.
+// Hooks are run in the order they were added. AddHook must not be called concurrently with other methods.
+func (i *Client) AddHook(hooks ...Hook) {
+	i.pool = withHooks(i.pool, hooks)
+}
+
+// AddHook registers hooks around all the commands of the autocompleter, including the pipelined ones.
+// Hooks are run in the order they were added. AddHook must not be called concurrently with other methods.
+func (a *Autocompleter) AddHook(hooks ...Hook) {
+	a.pool = withHooks(a.pool, hooks)
+}
+
+// internal function
+// withHooks wraps the pool so that its connections run the hooks, appending to the existing hooks if any,
+// even when a retry policy was set after them
+func withHooks(pool ConnPool, hooks []Hook) ConnPool {
+	replaced, ok := replaceWrapper(pool, func(p ConnPool) (ConnPool, bool) {
+		hp, ok := p.(*hookPool)
+		if !ok {
+			return p, false
+		}
+		return &hookPool{pool: hp.pool, hooks: append(append([]Hook{}, hp.hooks...), hooks...)}, true
+	})
+	if ok {
+		return replaced
+	}
+	return &hookPool{pool: pool, hooks: append([]Hook{}, hooks...)}
+}
+
+// internal struct
+// hookPool is a ConnPool returning connections that run the hooks
+type hookPool struct {
+	pool  ConnPool
+	hooks []Hook
+}
+
+func (p *hookPool) Get() redis.Conn {
+	return &hookConn{Conn: p.pool.Get(), hooks: p.hooks}
+}
+
+func (p *hookPool) Close() error {
+	return p.pool.Close()
+}
+
+// internal struct
+// hookConn runs the hooks around the commands of the underlying connection.
+// Pipelined commands rejected by a hook are not sent, their error is returned by the matching Receive so that
+// the replies stay aligned with the commands.
+type hookConn struct {
+	redis.Conn
+	hooks    []Hook
+	pending  []*CommandInfo
+	pipeline *PipelineInfo
+}
+
+func (c *hookConn) before(cmd *CommandInfo) error {
+	for _, hook := range c.hooks {
+		if err := hook.BeforeCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *hookConn) after(cmd *CommandInfo, reply interface{}, err error) (interface{}, error) {
+	cmd.Duration = time.Since(cmd.Start)
+	cmd.Reply, cmd.Err = reply, err
+	for _, hook := range c.hooks {
+		hook.AfterCommand(cmd)
+	}
+	return cmd.Reply, cmd.Err
+}
+
+func (c *hookConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if len(c.pending) > 0 || commandName == "" {
+		if commandName != "" {
+			c.Send(commandName, args...)
+		}
+		if err := c.Flush(); err != nil {
+			return nil, err
+		}
+		var reply interface{}
+		var err error
+		for len(c.pending) > 0 {
+			reply, err = c.Receive()
+		}
+		return reply, err
+	}
+	cmd := &CommandInfo{Name: commandName, Args: args, Context: context.Background(), Start: time.Now()}
+	if err := c.before(cmd); err != nil {
+		return c.after(cmd, nil, err)
+	}
+	reply, err := c.Conn.Do(cmd.Name, cmd.Args...)
+	return c.after(cmd, reply, err)
+}
+
+func (c *hookConn) Send(commandName string, args ...interface{}) error {
+	if len(c.pending) == 0 {
+		c.pipeline = &PipelineInfo{Start: time.Now()}
+	}
+	c.pipeline.Commands++
+	cmd := &CommandInfo{Name: commandName, Args: args, Context: context.Background(), Start: time.Now(), Pipeline: c.pipeline}
+	if err := c.before(cmd); err != nil {
+		cmd.Err = err
+		c.pending = append(c.pending, cmd)
+		return nil
+	}
+	if err := c.Conn.Send(cmd.Name, cmd.Args...); err != nil {
+		c.pipeline.Commands--
+		return err
+	}
+	c.pending = append(c.pending, cmd)
+	return nil
+}
+
+func (c *hookConn) Receive() (interface{}, error) {
+	if len(c.pending) == 0 {
+		return c.Conn.Receive()
+	}
+	cmd := c.pending[0]
+	c.pending = c.pending[1:]
+	cmd.Pipeline.Replies++
+	if cmd.Err != nil {
+		return c.after(cmd, nil, cmd.Err)
+	}
+	reply, err := c.Conn.Receive()
+	return c.after(cmd, reply, err)
+}
+
+func (c *hookConn) Flush() error {
+	err := c.Conn.Flush()
+	if err != nil && len(c.pending) > 0 {
+		c.abort(err)
+	}
+	return err
+}
+
+func (c *hookConn) Close() error {
+	if len(c.pending) > 0 {
+		c.abort(ErrPipelineClosed)
+	}
+	return c.Conn.Close()
+}
+
+// abort drops the pending commands of the pipeline, and tells the hooks that the pipeline is abandoned
+func (c *hookConn) abort(err error) {
+	c.pending = nil
+	for _, hook := range c.hooks {
+		if ph, ok := hook.(PipelineHook); ok {
+			ph.AbortPipeline(c.pipeline, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package redisearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHook rewrites FT.SEARCH queries and records the commands it sees
+type recordingHook struct {
+	before []string
+	after  []*CommandInfo
+	fail   string
+}
+
+func (h *recordingHook) BeforeCommand(cmd *CommandInfo) error {
+	h.before = append(h.before, cmd.Name)
+	if cmd.Name == "FT.SEARCH" {
+		cmd.Args[1] = "@tenant:{acme} " + cmd.Args[1].(string)
+	}
+	if len(cmd.Args) > 1 && cmd.Args[1] == h.fail {
+		return errors.New("injected")
+	}
+	return nil
+}
+
+func (h *recordingHook) AfterCommand(cmd *CommandInfo) {
+	h.after = append(h.after, cmd)
+}
+
+func TestClient_AddHook(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Value: []interface{}{int64(0)}}}}
+	hook := &recordingHook{}
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(hook)
+
+	_, total, err := c.Search(NewQuery("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, total)
+	assert.Equal(t, "@tenant:{acme} hello", exec.cmds[0].Args[1])
+	assert.Equal(t, []string{"FT.SEARCH"}, hook.before)
+	assert.Len(t, hook.after, 1)
+	assert.Nil(t, hook.after[0].Pipeline)
+	assert.Equal(t, []interface{}{int64(0)}, hook.after[0].Reply)
+}
+
+func TestAutocompleter_AddHook(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Value: int64(1)}, {Value: int64(2)}}}
+	hook := &recordingHook{fail: "b"}
+	second := &recordingHook{}
+	a := NewAutocompleterFromExecutor(exec, "ac")
+	a.AddHook(hook)
+	a.AddHook(second)
+
+	err := a.AddTerms(Suggestion{Term: "a", Score: 1}, Suggestion{Term: "b", Score: 1}, Suggestion{Term: "c", Score: 1})
+	assert.Equal(t, "injected", err.Error())
+	// the rejected command is not sent, the others are pipelined
+	assert.Len(t, exec.cmds, 2)
+	assert.Equal(t, "c", exec.cmds[1].Args[1])
+	assert.Equal(t, []string{"FT.SUGADD", "FT.SUGADD", "FT.SUGADD"}, hook.before)
+	// the second hook is not run before the rejected command
+	assert.Equal(t, []string{"FT.SUGADD", "FT.SUGADD"}, second.before)
+	assert.Len(t, hook.after, 2)
+	assert.Equal(t, 3, hook.after[0].Pipeline.Commands)
+	assert.Equal(t, 2, hook.after[1].Pipeline.Replies)
+	assert.Equal(t, int64(1), hook.after[0].Reply)
+	assert.Equal(t, "injected", hook.after[1].Err.Error())
+}
@@ -1,9 +1,5 @@
 package redisearch
 
-import (
-	"github.com/gomodule/redigo/redis"
-)
-
 // IndexType - Enum of existing index types
 type IndexType int
 
@@ -36,6 +32,90 @@ type IndexInfo struct {
 	IsIndexing           bool    `redis:"indexing"`
 	PercentIndexed       float64 `redis:"percent_indexed"`
 	HashIndexingFailures uint64  `redis:"hash_indexing_failures"`
+
+	VectorIndexSizeMB        float64 `redis:"vector_index_sz_mb"`
+	TotalInvertedIndexBlocks uint64  `redis:"total_inverted_index_blocks"`
+	SortableValuesSizeMB     float64 `redis:"sortable_values_size_mb"`
+	TagOverheadSizeMB        float64 `redis:"tag_overhead_sz_mb"`
+	TextOverheadSizeMB       float64 `redis:"text_overhead_sz_mb"`
+	TotalIndexMemorySizeMB   float64 `redis:"total_index_memory_sz_mb"`
+	TotalIndexingTime        float64 `redis:"total_indexing_time"`
+	NumberOfUses             uint64  `redis:"number_of_uses"`
+	IsCleaning               bool    `redis:"cleaning"`
+
+	GCStats         GCStats
+	CursorStats     CursorStats
+	DialectStats    DialectStats
+	IndexErrors     IndexErrors
+	FieldStatistics []FieldStatistics
+	Attributes      []AttributeInfo
+	// Definition is the definition of the index, nil if FT.INFO does not report it (RediSearch < 2.0)
+	Definition *IndexDefinition
+
+	// Raw holds the sections of the FT.INFO reply that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// GCStats - Garbage collector statistics of an index
+type GCStats struct {
+	BytesCollected     uint64  `redis:"bytes_collected"`
+	TotalMsRun         uint64  `redis:"total_ms_run"`
+	TotalCycles        uint64  `redis:"total_cycles"`
+	AverageCycleTimeMs float64 `redis:"average_cycle_time_ms"`
+	LastRunTimeMs      float64 `redis:"last_run_time_ms"`
+	NumericTreesMissed uint64  `redis:"gc_numeric_trees_missed"`
+	BlocksDenied       uint64  `redis:"gc_blocks_denied"`
+	// Raw holds the keys of the section that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// CursorStats - Statistics of the aggregation cursors, globally and for an index
+type CursorStats struct {
+	GlobalIdle    uint64 `redis:"global_idle"`
+	GlobalTotal   uint64 `redis:"global_total"`
+	IndexCapacity uint64 `redis:"index_capacity"`
+	IndexTotal    uint64 `redis:"index_total"`
+	// Raw holds the keys of the section that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// DialectStats - Number of queries executed with each dialect
+type DialectStats struct {
+	Dialect1 uint64 `redis:"dialect_1"`
+	Dialect2 uint64 `redis:"dialect_2"`
+	Dialect3 uint64 `redis:"dialect_3"`
+	Dialect4 uint64 `redis:"dialect_4"`
+	// Raw holds the keys of the section that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// IndexErrors - Indexing failures of an index, or of a single field
+type IndexErrors struct {
+	IndexingFailures     uint64 `redis:"indexing failures"`
+	LastIndexingError    string `redis:"last indexing error"`
+	LastIndexingErrorKey string `redis:"last indexing error key"`
+	// Raw holds the keys of the section that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// FieldStatistics - Indexing failures of a single field
+type FieldStatistics struct {
+	Identifier  string `redis:"identifier"`
+	Attribute   string `redis:"attribute"`
+	IndexErrors IndexErrors
+	// Raw holds the keys of the section that are not mapped to a field
+	Raw map[string]interface{}
+}
+
+// AttributeInfo - Details of a single attribute of the index schema, as reported by FT.INFO
+type AttributeInfo struct {
+	Identifier string `redis:"identifier"`
+	Attribute  string `redis:"attribute"`
+	Type       string `redis:"type"`
+	// Options holds the attribute options that have a value, e.g. WEIGHT or SEPARATOR
+	Options map[string]string
+	// Flags holds the attribute options without a value, e.g. SORTABLE or NOSTEM
+	Flags []string
 }
 
 // IndexDefinition is used to define a index definition for automatic indexing on Hash update
@@ -121,7 +201,7 @@ func (defintion *IndexDefinition) SetPayloadField(value string) (outDef *IndexDe
 }
 
 // This is only valid for >= RediSearch 2.0
-func (defintion *IndexDefinition) Serialize(args redis.Args) redis.Args {
+func (defintion *IndexDefinition) Serialize(args []interface{}) []interface{} {
 	args = append(args, "ON", defintion.IndexOn)
 	if defintion.Async {
 		args = append(args, "ASYNC")
@@ -156,7 +236,7 @@ func (defintion *IndexDefinition) Serialize(args redis.Args) redis.Args {
 	return args
 }
 
-func SerializeIndexingOptions(opts IndexingOptions, args redis.Args) redis.Args {
+func SerializeIndexingOptions(opts IndexingOptions, args []interface{}) []interface{} {
 	// apply options
 
 	// As of RediSearch 2.0 and above NOSAVE is no longer supported.
@@ -1,7 +1,6 @@
 package redisearch
 
 import (
-	"github.com/gomodule/redigo/redis"
 	"reflect"
 	"testing"
 )
@@ -11,24 +10,24 @@ func TestIndexDefinition_Serialize(t *testing.T) {
 		Definition *IndexDefinition
 	}
 	type args struct {
-		args redis.Args
+		args []interface{}
 	}
 	tests := []struct {
 		name   string
 		fields fields
 		args   args
-		want   redis.Args
+		want   []interface{}
 	}{
-		{"default", fields{NewIndexDefinition()}, args{redis.Args{}}, redis.Args{"ON", "HASH"}},
-		{"default", fields{NewIndexDefinition().SetIndexOn(JSON)}, args{redis.Args{}}, redis.Args{"ON", "JSON"}},
-		{"default+async", fields{NewIndexDefinition().SetAsync(true)}, args{redis.Args{}}, redis.Args{"ON", "HASH", "ASYNC"}},
-		{"default+score", fields{NewIndexDefinition().SetScore(0.75)}, args{redis.Args{}}, redis.Args{"ON", "HASH", "SCORE", 0.75}},
-		{"default+score_field", fields{NewIndexDefinition().SetScoreField("myscore")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "SCORE_FIELD", "myscore"}},
-		{"default+language", fields{NewIndexDefinition().SetLanguage("portuguese")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "LANGUAGE", "portuguese"}},
-		{"default+language_field", fields{NewIndexDefinition().SetLanguageField("mylanguage")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "LANGUAGE_FIELD", "mylanguage"}},
-		{"default+prefix", fields{NewIndexDefinition().AddPrefix("products:*")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "PREFIX", 1, "products:*"}},
-		{"default+payload_field", fields{NewIndexDefinition().SetPayloadField("products_description")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "PAYLOAD_FIELD", "products_description"}},
-		{"default+filter", fields{NewIndexDefinition().SetFilterExpression("@score:[0 50]")}, args{redis.Args{}}, redis.Args{"ON", "HASH", "FILTER", "@score:[0 50]"}},
+		{"default", fields{NewIndexDefinition()}, args{[]interface{}{}}, []interface{}{"ON", "HASH"}},
+		{"default", fields{NewIndexDefinition().SetIndexOn(JSON)}, args{[]interface{}{}}, []interface{}{"ON", "JSON"}},
+		{"default+async", fields{NewIndexDefinition().SetAsync(true)}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "ASYNC"}},
+		{"default+score", fields{NewIndexDefinition().SetScore(0.75)}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "SCORE", 0.75}},
+		{"default+score_field", fields{NewIndexDefinition().SetScoreField("myscore")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "SCORE_FIELD", "myscore"}},
+		{"default+language", fields{NewIndexDefinition().SetLanguage("portuguese")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "LANGUAGE", "portuguese"}},
+		{"default+language_field", fields{NewIndexDefinition().SetLanguageField("mylanguage")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "LANGUAGE_FIELD", "mylanguage"}},
+		{"default+prefix", fields{NewIndexDefinition().AddPrefix("products:*")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "PREFIX", 1, "products:*"}},
+		{"default+payload_field", fields{NewIndexDefinition().SetPayloadField("products_description")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "PAYLOAD_FIELD", "products_description"}},
+		{"default+filter", fields{NewIndexDefinition().SetFilterExpression("@score:[0 50]")}, args{[]interface{}{}}, []interface{}{"ON", "HASH", "FILTER", "@score:[0 50]"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
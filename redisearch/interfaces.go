@@ -0,0 +1,58 @@
+package redisearch
+
+// The interfaces below are implemented by Client, ShardedClient and Autocompleter so that the code using them can be
+// tested with the mocks of the redisearchmock package. Run go generate in redisearchmock after changing them.
+
+// Searcher runs full-text queries
+type Searcher interface {
+	Search(q *Query) (docs []Document, total int, err error)
+}
+
+// Indexer adds, gets and deletes the documents of an index
+type Indexer interface {
+	Index(docs ...Document) error
+	IndexOptions(opts IndexingOptions, docs ...Document) error
+	Get(docId string) (doc *Document, err error)
+	DeleteDocument(docId string) error
+}
+
+// Aggregator runs aggregation queries
+type Aggregator interface {
+	Aggregate(q *AggregateQuery) (aggregateReply [][]string, total int, err error)
+	AggregateQuery(q *AggregateQuery) (total int, aggregateReply []map[string]interface{}, err error)
+}
+
+// IndexAdmin manages the schema, the aliases and the synonyms of an index
+type IndexAdmin interface {
+	CreateIndex(schema *Schema) error
+	CreateIndexWithIndexDefinition(schema *Schema, definition *IndexDefinition) error
+	AddField(f Field) error
+	Info() (info *IndexInfo, err error)
+	DropIndex(deleteDocuments bool) error
+	List() (indexes []string, err error)
+	AliasAdd(name string) error
+	AliasDel(name string) error
+	AliasUpdate(name string) error
+	SynUpdate(indexName string, synonymGroupId int64, terms []string) (reply string, err error)
+	SynDump(indexName string) (synonyms map[string][]int64, err error)
+}
+
+// Suggester manages an auto-complete dictionary
+type Suggester interface {
+	AddTerms(terms ...Suggestion) error
+	DeleteTerms(terms ...Suggestion) error
+	Suggest(prefix string, num int, fuzzy bool) (suggestions []Suggestion, err error)
+	SuggestOpts(prefix string, opts SuggestOptions) (suggestions []Suggestion, err error)
+	Length() (length int64, err error)
+	Delete() error
+}
+
+var (
+	_ Searcher   = (*Client)(nil)
+	_ Indexer    = (*Client)(nil)
+	_ Aggregator = (*Client)(nil)
+	_ IndexAdmin = (*Client)(nil)
+	_ Searcher   = (*ShardedClient)(nil)
+	_ Indexer    = (*ShardedClient)(nil)
+	_ Suggester  = (*Autocompleter)(nil)
+)
@@ -0,0 +1,189 @@
+// Package loader bulk loads CSV and JSON Lines files into a RediSearch index.
+//
+// A Source streams the records of a file, optionally gzip or bzip2 compressed. A Mapping maps the columns
+// of each record to the fields of a document, coercing the values to the field types, and generates the
+// document ids from a template. The documents are indexed by batches:
+//
+//	f, err := loader.OpenFile("games.json.bz2")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	m := loader.MappingFromSchema(sc, "game:{asin}")
+//	progress, err := loader.Load(c, loader.NewJSONSource(f), m)
+package loader
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// Options are the options of LoadOptions
+type Options struct {
+	// BatchSize is the number of documents indexed in a single pipeline
+	BatchSize int
+
+	// IndexingOptions are the options the documents are indexed with
+	IndexingOptions redisearch.IndexingOptions
+
+	// MaxErrors is the number of failed records tolerated before the load stops.
+	// A negative value tolerates any number of failed records.
+	MaxErrors int
+
+	// OnError is called on each failed record, when set
+	OnError func(err *RecordError)
+
+	// OnProgress is called after each batch, when set
+	OnProgress func(p Progress)
+}
+
+// DefaultOptions are the default options of Load: the load stops on the first failed record
+var DefaultOptions = Options{
+	BatchSize:       1000,
+	IndexingOptions: redisearch.DefaultIndexingOptions,
+	MaxErrors:       0,
+}
+
+// Progress is the progress of a load
+type Progress struct {
+	// Records is the number of records read
+	Records int
+	// Indexed is the number of documents indexed
+	Indexed int
+	// Failed is the number of records that could not be read, mapped or indexed
+	Failed int
+	// Elapsed is the time elapsed since the start of the load
+	Elapsed time.Duration
+}
+
+// RecordError is the error of a record that could not be read, mapped or indexed
+type RecordError struct {
+	// Record is the number of the record, starting at 1
+	Record int
+	// Id is the document id, empty if the record could not be mapped
+	Id  string
+	Err error
+}
+
+// Error returns the error with the record number and the document id
+func (e *RecordError) Error() string {
+	if e.Id != "" {
+		return fmt.Sprintf("record %d (%s): %v", e.Record, e.Id, e.Err)
+	}
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Load indexes the records of the source with the default options. See LoadOptions.
+func Load(idx redisearch.Indexer, src Source, m Mapping) (Progress, error) {
+	return LoadOptions(DefaultOptions, idx, src, m)
+}
+
+// LoadOptions maps the records of the source to documents, and indexes them by batches until the end of the source.
+// Records that cannot be mapped or indexed are reported to OnError; once more than MaxErrors records have failed,
+// the load stops and returns the error of the record that exceeded MaxErrors. Errors reading the source stop the load, after the
+// documents already read are indexed. The returned progress counts the records up to the end of the load.
+func LoadOptions(opts Options, idx redisearch.Indexer, src Source, m Mapping) (Progress, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultOptions.BatchSize
+	}
+	id, err := parseTemplate(m.ID)
+	if err != nil {
+		return Progress{}, err
+	}
+	l := &load{opts: opts, idx: idx, start: time.Now()}
+	for {
+		rec, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		l.progress.Records++
+		n := l.progress.Records
+		if err != nil {
+			if ferr := l.flush(); ferr != nil {
+				return l.progress, ferr
+			}
+			rerr := &RecordError{Record: n, Err: err}
+			l.fail(rerr)
+			return l.progress, rerr
+		}
+		doc, err := m.document(id, rec, n)
+		if err != nil {
+			if ferr := l.fail(&RecordError{Record: n, Id: doc.Id, Err: err}); ferr != nil {
+				return l.progress, ferr
+			}
+			continue
+		}
+		l.docs = append(l.docs, doc)
+		l.records = append(l.records, n)
+		if len(l.docs) >= opts.BatchSize {
+			if err := l.flush(); err != nil {
+				return l.progress, err
+			}
+		}
+	}
+	return l.progress, l.flush()
+}
+
+// internal struct
+// load is the state of a running load
+type load struct {
+	opts     Options
+	idx      redisearch.Indexer
+	start    time.Time
+	progress Progress
+	// docs is the pending batch, and records the record numbers of its documents
+	docs    []redisearch.Document
+	records []int
+}
+
+// internal method
+// fail reports a failed record, and returns it if the load must stop
+func (l *load) fail(err *RecordError) error {
+	l.progress.Failed++
+	if l.opts.OnError != nil {
+		l.opts.OnError(err)
+	}
+	if l.opts.MaxErrors >= 0 && l.progress.Failed > l.opts.MaxErrors {
+		return err
+	}
+	return nil
+}
+
+// internal method
+// flush indexes the pending batch, and reports the progress
+func (l *load) flush() error {
+	if len(l.docs) == 0 {
+		return nil
+	}
+	docs, records := l.docs, l.records
+	l.docs, l.records = l.docs[:0], l.records[:0]
+
+	err := l.idx.IndexOptions(l.opts.IndexingOptions, docs...)
+	merr, ok := err.(redisearch.MultiError)
+	if err != nil && !ok {
+		return err
+	}
+	var stop error
+	for i, doc := range docs {
+		if ok && i < len(merr) && merr[i] != nil {
+			if ferr := l.fail(&RecordError{Record: records[i], Id: doc.Id, Err: merr[i]}); ferr != nil && stop == nil {
+				stop = ferr
+			}
+			continue
+		}
+		l.progress.Indexed++
+	}
+	l.progress.Elapsed = time.Since(l.start)
+	if l.opts.OnProgress != nil {
+		l.opts.OnProgress(l.progress)
+	}
+	return stop
+}
@@ -0,0 +1,98 @@
+package loader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchmock"
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_games(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	c := redisearch.NewClient(srv.Addr, "games")
+	sc := redisearch.NewSchema(redisearch.DefaultOptions).
+		AddField(redisearch.NewTextFieldOptions("title", redisearch.TextFieldOptions{Sortable: true})).
+		AddField(redisearch.NewTextFieldOptions("brand", redisearch.TextFieldOptions{Sortable: true, NoStem: true})).
+		AddField(redisearch.NewTextField("description")).
+		AddField(redisearch.NewSortableNumericField("price")).
+		AddField(redisearch.NewTagField("categories"))
+	assert.Nil(t, c.CreateIndex(sc))
+
+	f, err := OpenFile("../../tests/games.json.bz2")
+	assert.Nil(t, err)
+	defer f.Close()
+	var batches []Progress
+	opts := DefaultOptions
+	opts.BatchSize = 500
+	opts.OnProgress = func(p Progress) { batches = append(batches, p) }
+	progress, err := LoadOptions(opts, c, NewJSONSource(f), MappingFromSchema(sc, "game:{asin}"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2265, progress.Records)
+	assert.Equal(t, 2265, progress.Indexed)
+	assert.Equal(t, 0, progress.Failed)
+	assert.Len(t, batches, 5)
+	assert.Equal(t, 500, batches[0].Indexed)
+
+	doc, err := c.Get("game:0984529527")
+	assert.Nil(t, err)
+	assert.Equal(t, "Dark Age Apocalypse: Forcelists HC", doc.Properties["title"])
+	assert.Equal(t, "31.23", doc.Properties["price"])
+	assert.Equal(t, "Games,PC,Video Games", doc.Properties["categories"])
+	assert.NotContains(t, doc.Properties, "description")
+
+	_, total, err := c.Search(redisearch.NewQuery("@categories:{PC}").SetFlags(redisearch.QueryNoContent))
+	assert.Nil(t, err)
+	assert.NotZero(t, total)
+}
+
+func TestLoad_errors(t *testing.T) {
+	idx := &redisearchmock.Indexer{
+		IndexOptionsFunc: func(opts redisearch.IndexingOptions, docs ...redisearch.Document) error {
+			merr := redisearch.NewMultiError(len(docs))
+			for i, doc := range docs {
+				if doc.Id == "item:3" {
+					merr[i] = errors.New("Document already exists")
+					return merr
+				}
+			}
+			return nil
+		},
+	}
+	input := "id,price\n1,10\n2,free\n3,30\n4,40\n"
+	m := Mapping{ID: "item:{id}", Fields: []FieldMapping{{Field: "price", Type: redisearch.NumericField}}}
+
+	// the load stops on the first failed record by default
+	progress, err := Load(idx, NewCSVSource(strings.NewReader(input), CSVOptions{}), m)
+	assert.EqualError(t, err, `record 2 (item:2): field price: invalid number "free"`)
+	assert.Equal(t, Progress{Records: 2, Failed: 1}, progress)
+
+	var failed []string
+	opts := DefaultOptions
+	opts.BatchSize = 2
+	opts.MaxErrors = -1
+	opts.OnError = func(err *RecordError) { failed = append(failed, err.Error()) }
+	progress, err = LoadOptions(opts, idx, NewCSVSource(strings.NewReader(input), CSVOptions{}), m)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, progress.Records)
+	assert.Equal(t, 2, progress.Indexed)
+	assert.Equal(t, 2, progress.Failed)
+	assert.Equal(t, []string{
+		`record 2 (item:2): field price: invalid number "free"`,
+		"record 3 (item:3): Document already exists",
+	}, failed)
+
+	// the documents read before a source error are indexed
+	calls := len(idx.IndexOptionsCalls())
+	progress, err = Load(idx, NewCSVSource(strings.NewReader("id,price\n1,10\n2,20,extra\n"), CSVOptions{}), m)
+	assert.EqualError(t, err, "record 2: record has 3 columns, expected at most 2")
+	assert.Equal(t, 1, progress.Indexed)
+	assert.Len(t, idx.IndexOptionsCalls(), calls+1)
+
+	_, err = Load(idx, NewCSVSource(strings.NewReader(input), CSVOptions{}), Mapping{ID: "item:{id"})
+	assert.EqualError(t, err, "unclosed { in ID template")
+}
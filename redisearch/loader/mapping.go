@@ -0,0 +1,341 @@
+package loader
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// FieldMapping maps a column of the source to a field of the documents
+type FieldMapping struct {
+	// Field is the name of the document field
+	Field string
+
+	// Column is the column of the source, defaults to Field.
+	// Nested JSON values are referenced by a dotted path, e.g. "address.city".
+	Column string
+
+	// Type is the type the value is coerced to:
+	//  - TextField: strings and numbers as is, lists joined by spaces
+	//  - NumericField: a number
+	//  - TagField: strings as is, lists joined by the Separator
+	//  - GeoField: "lon,lat", from a "lon,lat" string, a [lon, lat] list, a {"lon", "lat"} object or the LatColumn
+	//  - VectorField: a FLOAT32 blob, from a list of numbers or its JSON string
+	Type redisearch.FieldType
+
+	// Separator joins the values of a list for tag fields, defaults to ","
+	Separator string
+
+	// LatColumn is the column of the latitude for geo fields whose coordinates are in two columns,
+	// the longitude being in Column
+	LatColumn string
+}
+
+// Mapping maps the records of a source to documents
+type Mapping struct {
+	// ID is the template of the document ids. Its {column} placeholders are replaced by the values of the record,
+	// and {#} by the record number starting at 1, e.g. "game:{asin}" or "line:{#}".
+	ID string
+
+	// Score is the column of the document scores. If empty, the documents have a score of 1.
+	Score string
+
+	// Fields are the fields of the documents. Empty values are left out of the documents.
+	Fields []FieldMapping
+}
+
+// MappingFromSchema maps each field of the schema to the column of the same name, coerced to the field type
+func MappingFromSchema(sc *redisearch.Schema, id string) Mapping {
+	m := Mapping{ID: id, Fields: make([]FieldMapping, 0, len(sc.Fields))}
+	for _, f := range sc.Fields {
+		fm := FieldMapping{Field: f.Name, Type: f.Type}
+		if opts, ok := f.Options.(redisearch.TagFieldOptions); ok && opts.Separator != 0 {
+			fm.Separator = string(rune(opts.Separator))
+		}
+		m.Fields = append(m.Fields, fm)
+	}
+	return m
+}
+
+// internal struct
+// template is a parsed ID template: literal parts alternate with the column placeholders
+type template struct {
+	literals []string
+	columns  []string
+}
+
+// internal function
+// parseTemplate parses an ID template
+func parseTemplate(s string) (*template, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing ID template")
+	}
+	t := &template{}
+	for {
+		start := strings.IndexByte(s, '{')
+		if start == -1 {
+			t.literals = append(t.literals, s)
+			return t, nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed { in ID template")
+		}
+		column := s[start+1 : start+end]
+		if column == "" {
+			return nil, fmt.Errorf("empty placeholder in ID template")
+		}
+		t.literals = append(t.literals, s[:start])
+		t.columns = append(t.columns, column)
+		s = s[start+end+1:]
+	}
+}
+
+// internal method
+// execute renders the id of the n-th record
+func (t *template) execute(rec Record, n int) (string, error) {
+	var sb strings.Builder
+	for i, column := range t.columns {
+		sb.WriteString(t.literals[i])
+		if column == "#" {
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		value, ok := stringValue(lookup(rec, column))
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing %s for the document id", column)
+		}
+		sb.WriteString(value)
+	}
+	sb.WriteString(t.literals[len(t.columns)])
+	return sb.String(), nil
+}
+
+// internal method
+// document maps the n-th record to a document
+func (m *Mapping) document(id *template, rec Record, n int) (redisearch.Document, error) {
+	docId, err := id.execute(rec, n)
+	if err != nil {
+		return redisearch.Document{}, err
+	}
+	score := 1.0
+	if m.Score != "" {
+		if score, err = numericValue(lookup(rec, m.Score)); err != nil {
+			return redisearch.Document{}, fmt.Errorf("score: %v", err)
+		}
+	}
+	doc := redisearch.NewDocument(docId, float32(score))
+	for _, fm := range m.Fields {
+		column := fm.Column
+		if column == "" {
+			column = fm.Field
+		}
+		value, err := fm.coerce(rec, lookup(rec, column))
+		if err != nil {
+			return doc, fmt.Errorf("field %s: %v", fm.Field, err)
+		}
+		if value != nil {
+			doc.Set(fm.Field, value)
+		}
+	}
+	return doc, nil
+}
+
+// internal method
+// coerce converts the value of the column to the field type, nil if the value is empty
+func (fm *FieldMapping) coerce(rec Record, value interface{}) (interface{}, error) {
+	if isEmpty(value) && !(fm.Type == redisearch.GeoField && fm.LatColumn != "") {
+		return nil, nil
+	}
+	switch fm.Type {
+	case redisearch.TextField:
+		if list, ok := value.([]interface{}); ok {
+			return joinValues(list, " "), nil
+		}
+		s, ok := stringValue(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		}
+		return s, nil
+	case redisearch.NumericField:
+		return numericValue(value)
+	case redisearch.TagField:
+		separator := fm.Separator
+		if separator == "" {
+			separator = ","
+		}
+		if list, ok := value.([]interface{}); ok {
+			return joinValues(list, separator), nil
+		}
+		s, ok := stringValue(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a string or a list, got %v", value)
+		}
+		return s, nil
+	case redisearch.GeoField:
+		if fm.LatColumn != "" {
+			lat := lookup(rec, fm.LatColumn)
+			if isEmpty(value) && isEmpty(lat) {
+				return nil, nil
+			}
+			return geoValue(value, lat)
+		}
+		return geoPoint(value)
+	case redisearch.VectorField:
+		return vectorValue(value)
+	}
+	return nil, fmt.Errorf("unsupported field type %v", fm.Type)
+}
+
+// internal function
+// lookup returns the value of a column, or of a dotted path in nested objects
+func lookup(rec Record, column string) interface{} {
+	if value, ok := rec[column]; ok {
+		return value
+	}
+	var value interface{} = map[string]interface{}(rec)
+	for _, key := range strings.Split(column, ".") {
+		switch obj := value.(type) {
+		case map[string]interface{}:
+			value = obj[key]
+		case Record:
+			value = obj[key]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// internal function
+// isEmpty reports whether a value is missing: nil, an empty string or an empty list
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// internal function
+// stringValue formats a scalar value
+func stringValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	}
+	return "", false
+}
+
+// internal function
+// joinValues joins the scalar values of a list, skipping the empty ones
+func joinValues(list []interface{}, separator string) string {
+	values := make([]string, 0, len(list))
+	for _, elem := range list {
+		if s, ok := stringValue(elem); ok && s != "" {
+			values = append(values, s)
+		}
+	}
+	return strings.Join(values, separator)
+}
+
+// internal function
+// numericValue converts a number, or a string holding a number
+func numericValue(value interface{}) (float64, error) {
+	s, ok := stringValue(value)
+	if !ok || s == "" {
+		return 0, fmt.Errorf("expected a number, got %v", value)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
+
+// internal function
+// geoPoint converts a "lon,lat" string, a [lon, lat] list or a {"lon", "lat"} object
+func geoPoint(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		coords := strings.Split(v, ",")
+		if len(coords) != 2 {
+			return "", fmt.Errorf("invalid coordinates %q, expected lon,lat", v)
+		}
+		return geoValue(coords[0], coords[1])
+	case []interface{}:
+		if len(v) != 2 {
+			return "", fmt.Errorf("invalid coordinates %v, expected [lon, lat]", v)
+		}
+		return geoValue(v[0], v[1])
+	case map[string]interface{}:
+		lon, ok := v["lon"]
+		if !ok {
+			lon = v["lng"]
+		}
+		return geoValue(lon, v["lat"])
+	}
+	return "", fmt.Errorf("invalid coordinates %v", value)
+}
+
+// internal function
+// geoValue validates the coordinates and formats them as "lon,lat"
+func geoValue(lon, lat interface{}) (string, error) {
+	x, err := numericValue(lon)
+	if err != nil {
+		return "", fmt.Errorf("longitude: %v", err)
+	}
+	y, err := numericValue(lat)
+	if err != nil {
+		return "", fmt.Errorf("latitude: %v", err)
+	}
+	// the latitude range of redis geo sets
+	if x < -180 || x > 180 || y < -85.05112878 || y > 85.05112878 {
+		return "", fmt.Errorf("coordinates %v,%v out of range", x, y)
+	}
+	return strconv.FormatFloat(x, 'f', -1, 64) + "," + strconv.FormatFloat(y, 'f', -1, 64), nil
+}
+
+// internal function
+// vectorValue converts a list of numbers, or its JSON string, to a little-endian FLOAT32 blob
+func vectorValue(value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid vector %q: %v", s, err)
+		}
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of numbers, got %v", value)
+	}
+	blob := make([]byte, 4*len(list))
+	for i, elem := range list {
+		f, err := numericValue(elem)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %v", i, err)
+		}
+		binary.LittleEndian.PutUint32(blob[4*i:], math.Float32bits(float32(f)))
+	}
+	return blob, nil
+}
@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate(t *testing.T) {
+	rec := Record{"asin": "B01", "n": json.Number("7"), "meta": map[string]interface{}{"shop": "eu"}}
+	tests := []struct {
+		template string
+		id       string
+		err      string
+	}{
+		{"game:{asin}", "game:B01", ""},
+		{"{meta.shop}:{asin}:{n}", "eu:B01:7", ""},
+		{"line:{#}", "line:3", ""},
+		{"static", "static", ""},
+		{"game:{title}", "", "missing title for the document id"},
+		{"", "", "missing ID template"},
+		{"game:{asin", "", "unclosed { in ID template"},
+		{"game:{}", "", "empty placeholder in ID template"},
+	}
+	for _, tt := range tests {
+		tpl, err := parseTemplate(tt.template)
+		var id string
+		if err == nil {
+			id, err = tpl.execute(rec, 3)
+		}
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.template)
+			continue
+		}
+		assert.Nil(t, err, tt.template)
+		assert.Equal(t, tt.id, id, tt.template)
+	}
+}
+
+func TestFieldMapping_coerce(t *testing.T) {
+	rec := Record{"lat": "45.5"}
+	tests := []struct {
+		name    string
+		mapping FieldMapping
+		value   interface{}
+		want    interface{}
+		err     string
+	}{
+		{"text", FieldMapping{Type: redisearch.TextField}, "red shoes", "red shoes", ""},
+		{"text number", FieldMapping{Type: redisearch.TextField}, json.Number("12"), "12", ""},
+		{"text list", FieldMapping{Type: redisearch.TextField}, []interface{}{"red", "shoes"}, "red shoes", ""},
+		{"text empty", FieldMapping{Type: redisearch.TextField}, " ", nil, ""},
+		{"text object", FieldMapping{Type: redisearch.TextField}, map[string]interface{}{}, nil, "expected a string, got map[]"},
+		{"numeric", FieldMapping{Type: redisearch.NumericField}, " 31.23", 31.23, ""},
+		{"numeric json", FieldMapping{Type: redisearch.NumericField}, json.Number("-2"), -2.0, ""},
+		{"numeric missing", FieldMapping{Type: redisearch.NumericField}, nil, nil, ""},
+		{"numeric invalid", FieldMapping{Type: redisearch.NumericField}, "free", nil, `invalid number "free"`},
+		{"tag", FieldMapping{Type: redisearch.TagField}, "acme", "acme", ""},
+		{"tag list", FieldMapping{Type: redisearch.TagField}, []interface{}{"Games", "", "PC"}, "Games,PC", ""},
+		{"tag separator", FieldMapping{Type: redisearch.TagField, Separator: ";"}, []interface{}{"a", json.Number("1")}, "a;1", ""},
+		{"geo string", FieldMapping{Type: redisearch.GeoField}, "13.361389, 38.115556", "13.361389,38.115556", ""},
+		{"geo list", FieldMapping{Type: redisearch.GeoField}, []interface{}{json.Number("2"), json.Number("48.5")}, "2,48.5", ""},
+		{"geo object", FieldMapping{Type: redisearch.GeoField}, map[string]interface{}{"lng": 2.0, "lat": 48.5}, "2,48.5", ""},
+		{"geo columns", FieldMapping{Type: redisearch.GeoField, LatColumn: "lat"}, "-73.5", "-73.5,45.5", ""},
+		{"geo lat only", FieldMapping{Type: redisearch.GeoField, LatColumn: "lat"}, nil, nil, "longitude: expected a number, got <nil>"},
+		{"geo invalid", FieldMapping{Type: redisearch.GeoField}, "13.36", nil, `invalid coordinates "13.36", expected lon,lat`},
+		{"geo range", FieldMapping{Type: redisearch.GeoField}, "38.1,190", nil, "coordinates 38.1,190 out of range"},
+		{"vector", FieldMapping{Type: redisearch.VectorField}, []interface{}{json.Number("1"), json.Number("2")}, []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0x40}, ""},
+		{"vector string", FieldMapping{Type: redisearch.VectorField}, "[1, 2]", []byte{0, 0, 0x80, 0x3f, 0, 0, 0, 0x40}, ""},
+		{"vector invalid", FieldMapping{Type: redisearch.VectorField}, []interface{}{"x"}, nil, `element 0: invalid number "x"`},
+	}
+	for _, tt := range tests {
+		got, err := tt.mapping.coerce(rec, tt.value)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.name)
+			continue
+		}
+		assert.Nil(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestMapping_document(t *testing.T) {
+	sc := redisearch.NewSchema(redisearch.DefaultOptions).
+		AddField(redisearch.NewTextField("title")).
+		AddField(redisearch.NewTagFieldOptions("categories", redisearch.TagFieldOptions{Separator: '|'})).
+		AddField(redisearch.NewNumericField("price"))
+	m := MappingFromSchema(sc, "game:{asin}")
+	assert.Equal(t, []FieldMapping{
+		{Field: "title", Type: redisearch.TextField},
+		{Field: "categories", Type: redisearch.TagField, Separator: "|"},
+		{Field: "price", Type: redisearch.NumericField},
+	}, m.Fields)
+	m.Score = "rank"
+	id, err := parseTemplate(m.ID)
+	assert.Nil(t, err)
+
+	doc, err := m.document(id, Record{
+		"asin": "B01", "title": "Dark Age", "categories": []interface{}{"Games", "PC"}, "price": nil, "rank": "0.5",
+	}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "game:B01", doc.Id)
+	assert.Equal(t, float32(0.5), doc.Score)
+	assert.Equal(t, map[string]interface{}{"title": "Dark Age", "categories": "Games|PC"}, doc.Properties)
+
+	doc, err = m.document(id, Record{"asin": "B02", "price": "free", "rank": "1"}, 2)
+	assert.EqualError(t, err, `field price: invalid number "free"`)
+	assert.Equal(t, "game:B02", doc.Id)
+
+	_, err = m.document(id, Record{"asin": "B03"}, 3)
+	assert.EqualError(t, err, "score: expected a number, got <nil>")
+}
@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Record is a record read from a source, by column name.
+// The values of CSV records are strings, the ones of JSON records are the decoded JSON values with json.Number numbers.
+type Record map[string]interface{}
+
+// Source reads the records of a file one by one. Next returns io.EOF after the last record.
+type Source interface {
+	Next() (Record, error)
+}
+
+// CSVOptions are the options of a CSV source
+type CSVOptions struct {
+	// Comma is the field delimiter, defaults to ','
+	Comma rune
+
+	// Columns are the names of the columns. If nil, they are read from the first line.
+	Columns []string
+
+	// LazyQuotes allows quotes in unquoted fields, and non-doubled quotes in quoted fields
+	LazyQuotes bool
+}
+
+// internal struct
+type csvSource struct {
+	r       *csv.Reader
+	columns []string
+}
+
+// NewCSVSource creates a source reading CSV records. Records may have fewer columns than the header,
+// the missing columns are left out of the record.
+func NewCSVSource(r io.Reader, opts CSVOptions) Source {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.LazyQuotes = opts.LazyQuotes
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &csvSource{r: cr, columns: opts.Columns}
+}
+
+// Next reads the next CSV record
+func (s *csvSource) Next() (Record, error) {
+	if s.columns == nil {
+		header, err := s.r.Read()
+		if err == io.EOF {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read the CSV header: %v", err)
+		}
+		s.columns = append([]string{}, header...)
+	}
+	values, err := s.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > len(s.columns) {
+		return nil, fmt.Errorf("record has %d columns, expected at most %d", len(values), len(s.columns))
+	}
+	rec := make(Record, len(values))
+	for i, value := range values {
+		rec[s.columns[i]] = value
+	}
+	return rec, nil
+}
+
+// internal struct
+type jsonSource struct {
+	dec *json.Decoder
+}
+
+// NewJSONSource creates a source reading JSON Lines, or any stream of JSON objects
+func NewJSONSource(r io.Reader) Source {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &jsonSource{dec: dec}
+}
+
+// Next decodes the next JSON object
+func (s *jsonSource) Next() (Record, error) {
+	var rec Record
+	if err := s.dec.Decode(&rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Decompress returns a reader of the decompressed stream if r is gzip or bzip2 compressed,
+// detected by its magic number, and a reader of r otherwise.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(3)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case len(magic) >= 2 && magic[0] == 0x1f && magic[1] == 0x8b:
+		return gzip.NewReader(br)
+	case len(magic) == 3 && string(magic) == "BZh":
+		return bzip2.NewReader(br), nil
+	}
+	return br, nil
+}
+
+// OpenFile opens a file for reading, decompressing it if it is gzip or bzip2 compressed
+func OpenFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Decompress(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readAll reads the records of a source until its end or an error
+func readAll(src Source) ([]Record, error) {
+	var recs []Record
+	for {
+		rec, err := src.Next()
+		if err == io.EOF {
+			return recs, nil
+		} else if err != nil {
+			return recs, err
+		}
+		recs = append(recs, rec)
+	}
+}
+
+func TestCSVSource(t *testing.T) {
+	recs, err := readAll(NewCSVSource(strings.NewReader("id,title,price\n1,\"red, shoes\",50\n2,hat\n"), CSVOptions{}))
+	assert.Nil(t, err)
+	assert.Equal(t, []Record{
+		{"id": "1", "title": "red, shoes", "price": "50"},
+		{"id": "2", "title": "hat"},
+	}, recs)
+
+	recs, err = readAll(NewCSVSource(strings.NewReader("1;a\n2;b;c\n"), CSVOptions{Comma: ';', Columns: []string{"id", "title"}}))
+	assert.Equal(t, []Record{{"id": "1", "title": "a"}}, recs)
+	assert.EqualError(t, err, "record has 3 columns, expected at most 2")
+
+	recs, err = readAll(NewCSVSource(strings.NewReader(""), CSVOptions{}))
+	assert.Nil(t, err)
+	assert.Empty(t, recs)
+}
+
+func TestJSONSource(t *testing.T) {
+	recs, err := readAll(NewJSONSource(strings.NewReader(`{"id": 1, "tags": ["a", "b"], "loc": {"lon": 2.5}}` + "\n\n" + `{"id": 2.5}` + "\n")))
+	assert.Nil(t, err)
+	assert.Equal(t, []Record{
+		{"id": json.Number("1"), "tags": []interface{}{"a", "b"}, "loc": map[string]interface{}{"lon": json.Number("2.5")}},
+		{"id": json.Number("2.5")},
+	}, recs)
+
+	recs, err = readAll(NewJSONSource(strings.NewReader(`{"id": 1}` + "\n" + `[1, 2]`)))
+	assert.Len(t, recs, 1)
+	assert.NotNil(t, err)
+}
+
+func TestDecompress(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{"id": 1}`))
+	zw.Close()
+	for _, in := range [][]byte{buf.Bytes(), []byte(`{"id": 1}`)} {
+		r, err := Decompress(bytes.NewReader(in))
+		assert.Nil(t, err)
+		out, err := ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id": 1}`, string(out))
+	}
+
+	r, err := Decompress(strings.NewReader(""))
+	assert.Nil(t, err)
+	out, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+}
+
+func TestOpenFile(t *testing.T) {
+	f, err := OpenFile("../../tests/will_play_text.csv.bz2")
+	assert.Nil(t, err)
+	defer f.Close()
+	recs, err := readAll(NewCSVSource(f, CSVOptions{
+		Comma:   ';',
+		Columns: []string{"line", "play", "speech", "act_scene_line", "player", "text"},
+	}))
+	assert.Nil(t, err)
+	assert.Len(t, recs, 111396)
+	assert.Equal(t, Record{"line": "1", "play": "Henry IV", "speech": "", "act_scene_line": "", "player": "", "text": "ACT I"}, recs[0])
+
+	_, err = OpenFile("../../tests/missing.csv")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,450 @@
+package redisearch
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// MetricsSink receives the measures of the commands and of the connection pools
+type MetricsSink interface {
+	// ObserveCommand records a command run
+	ObserveCommand(sample CommandSample)
+	// SetPoolStats records the current statistics of a connection pool
+	SetPoolStats(pool string, stats PoolStats)
+}
+
+// CommandSample is the measure of a single command
+type CommandSample struct {
+	// Command is the command name, with the subcommand for the commands having one (e.g. FT.CURSOR READ)
+	Command string
+	// Index is the index name, empty for the commands that are not bound to an index
+	Index    string
+	Duration time.Duration
+	// ErrorType is empty on success, otherwise one of "server", "timeout", "network" or "client"
+	ErrorType string
+	// Results is the number of results of search and aggregate replies, -1 for the other commands
+	Results int
+}
+
+// PoolStats are the statistics of a connection pool
+type PoolStats struct {
+	// ActiveCount is the number of connections in the pool, in use or idle
+	ActiveCount int
+	// IdleCount is the number of idle connections in the pool
+	IdleCount int
+	// WaitCount is the total number of connections waited for
+	WaitCount int64
+	// WaitDuration is the total time spent waiting for connections
+	WaitDuration time.Duration
+}
+
+// NewMetricsHook creates a Hook recording a CommandSample per command into the sink, e.g.
+//
+//	sink := redisearch.NewPrometheusSink()
+//	c.AddHook(redisearch.NewMetricsHook(sink))
+//	http.Handle("/metrics", sink)
+func NewMetricsHook(sink MetricsSink) Hook {
+	return &metricsHook{sink: sink}
+}
+
+// internal struct
+// metricsHook converts the commands to samples
+type metricsHook struct {
+	sink MetricsSink
+}
+
+func (h *metricsHook) BeforeCommand(cmd *CommandInfo) error {
+	return nil
+}
+
+func (h *metricsHook) AfterCommand(cmd *CommandInfo) {
+	index, _ := commandTarget(cmd)
+	sample := CommandSample{
+		Command:   operationName(cmd),
+		Index:     index,
+		Duration:  cmd.Duration,
+		ErrorType: errorType(cmd.Err),
+		Results:   -1,
+	}
+	if results, _, ok := replyCounts(cmd); ok {
+		sample.Results = results
+	}
+	h.sink.ObserveCommand(sample)
+}
+
+// internal function
+// errorType classifies the errors of the commands
+func errorType(err error) string {
+	if err == nil {
+		return ""
+	}
+	if _, ok := err.(redis.Error); ok {
+		return "server"
+	}
+	if netErr, ok := err.(net.Error); ok {
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return "network"
+	}
+	return "client"
+}
+
+// PoolStats returns the statistics of the client connection pool.
+// It returns false if the pool does not report statistics, e.g. if the client runs on a custom Executor.
+func (i *Client) PoolStats() (PoolStats, bool) {
+	return connPoolStats(i.pool)
+}
+
+// PoolStats returns the statistics of the autocompleter connection pool.
+// It returns false if the pool does not report statistics, e.g. if the autocompleter runs on a custom Executor.
+func (a *Autocompleter) PoolStats() (PoolStats, bool) {
+	return connPoolStats(a.pool)
+}
+
+// ReportPoolStats sets the statistics of the pool in the sink every interval, until the returned stop
+// function is called
+func ReportPoolStats(sink MetricsSink, name string, stats func() (PoolStats, bool), interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if s, ok := stats(); ok {
+				sink.SetPoolStats(name, s)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// internal function
+// connPoolStats returns the statistics of the redigo pool behind the pool wrappers of this package
+func connPoolStats(pool ConnPool) (PoolStats, bool) {
+	switch p := pool.(type) {
+	case *hookPool:
+		return connPoolStats(p.pool)
+	case *retryPool:
+		return connPoolStats(p.pool)
+	case *breakerPool:
+		return connPoolStats(p.pool)
+	case *executorPool:
+		if e, ok := p.exec.(*redigoExecutor); ok {
+			return connPoolStats(e.pool)
+		}
+		return PoolStats{}, false
+	case interface{ Stats() redis.PoolStats }:
+		s := p.Stats()
+		return PoolStats{ActiveCount: s.ActiveCount, IdleCount: s.IdleCount, WaitCount: s.WaitCount, WaitDuration: s.WaitDuration}, true
+	}
+	return PoolStats{}, false
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histograms
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// DefaultResultsBuckets are the upper bounds of the result size histograms
+var DefaultResultsBuckets = []float64{0, 1, 10, 100, 1000, 10000}
+
+// Histogram is a snapshot of a histogram
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets
+	Buckets []float64
+	// Counts are the number of observations of each bucket, followed by the observations above the last bound
+	Counts []int64
+	Count  int64
+	Sum    float64
+}
+
+func newHistogram(buckets []float64) Histogram {
+	return Histogram{Buckets: buckets, Counts: make([]int64, len(buckets)+1)}
+}
+
+func (h *Histogram) observe(value float64) {
+	h.Count++
+	h.Sum += value
+	h.Counts[sort.SearchFloat64s(h.Buckets, value)]++
+}
+
+func (h Histogram) copy() Histogram {
+	h.Counts = append([]int64{}, h.Counts...)
+	return h
+}
+
+// CommandStats are the aggregated measures of a command on an index
+type CommandStats struct {
+	Command string
+	Index   string
+	Latency Histogram
+	// Results is the histogram of the result sizes, empty for the commands without results
+	Results Histogram
+	// Errors is the number of errors by error type
+	Errors map[string]int64
+}
+
+// MetricsSnapshot is a copy of the measures of a MetricsRegistry
+type MetricsSnapshot struct {
+	// Commands are sorted by command and index
+	Commands []CommandStats
+	Pools    map[string]PoolStats
+}
+
+// MetricsRegistry is an in-memory MetricsSink aggregating the samples per command and index.
+// It is the base of the Prometheus and expvar sinks.
+type MetricsRegistry struct {
+	mu             sync.Mutex
+	latencyBuckets []float64
+	resultsBuckets []float64
+	commands       map[[2]string]*CommandStats
+	pools          map[string]PoolStats
+}
+
+// NewMetricsRegistry creates a registry with the default buckets
+func NewMetricsRegistry() *MetricsRegistry {
+	return NewMetricsRegistryWithBuckets(DefaultLatencyBuckets, DefaultResultsBuckets)
+}
+
+// NewMetricsRegistryWithBuckets creates a registry with the given histogram upper bounds, in ascending order.
+// The latency bounds are in seconds.
+func NewMetricsRegistryWithBuckets(latencyBuckets, resultsBuckets []float64) *MetricsRegistry {
+	return &MetricsRegistry{
+		latencyBuckets: latencyBuckets,
+		resultsBuckets: resultsBuckets,
+		commands:       make(map[[2]string]*CommandStats),
+		pools:          make(map[string]PoolStats),
+	}
+}
+
+// ObserveCommand implements MetricsSink
+func (r *MetricsRegistry) ObserveCommand(sample CommandSample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := [2]string{sample.Command, sample.Index}
+	stats, ok := r.commands[key]
+	if !ok {
+		stats = &CommandStats{
+			Command: sample.Command,
+			Index:   sample.Index,
+			Latency: newHistogram(r.latencyBuckets),
+			Results: newHistogram(r.resultsBuckets),
+			Errors:  make(map[string]int64),
+		}
+		r.commands[key] = stats
+	}
+	stats.Latency.observe(sample.Duration.Seconds())
+	if sample.Results >= 0 {
+		stats.Results.observe(float64(sample.Results))
+	}
+	if sample.ErrorType != "" {
+		stats.Errors[sample.ErrorType]++
+	}
+}
+
+// SetPoolStats implements MetricsSink
+func (r *MetricsRegistry) SetPoolStats(pool string, stats PoolStats) {
+	r.mu.Lock()
+	r.pools[pool] = stats
+	r.mu.Unlock()
+}
+
+// Snapshot returns a copy of the measures
+func (r *MetricsRegistry) Snapshot() MetricsSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := MetricsSnapshot{
+		Commands: make([]CommandStats, 0, len(r.commands)),
+		Pools:    make(map[string]PoolStats, len(r.pools)),
+	}
+	for _, stats := range r.commands {
+		errors := make(map[string]int64, len(stats.Errors))
+		for errType, count := range stats.Errors {
+			errors[errType] = count
+		}
+		snapshot.Commands = append(snapshot.Commands, CommandStats{
+			Command: stats.Command,
+			Index:   stats.Index,
+			Latency: stats.Latency.copy(),
+			Results: stats.Results.copy(),
+			Errors:  errors,
+		})
+	}
+	sort.Slice(snapshot.Commands, func(i, j int) bool {
+		if snapshot.Commands[i].Command != snapshot.Commands[j].Command {
+			return snapshot.Commands[i].Command < snapshot.Commands[j].Command
+		}
+		return snapshot.Commands[i].Index < snapshot.Commands[j].Index
+	})
+	for name, stats := range r.pools {
+		snapshot.Pools[name] = stats
+	}
+	return snapshot
+}
+
+// PrometheusSink is a MetricsSink exposing the measures in the Prometheus text format.
+// It is an http.Handler serving the metrics.
+type PrometheusSink struct {
+	*MetricsRegistry
+}
+
+// NewPrometheusSink creates a Prometheus sink with the default buckets
+func NewPrometheusSink() *PrometheusSink {
+	return &PrometheusSink{NewMetricsRegistry()}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (s *PrometheusSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (s *PrometheusSink) WriteTo(w io.Writer) (int64, error) {
+	snapshot := s.Snapshot()
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	fmt.Fprintln(cw, "# HELP redisearch_command_duration_seconds Latency of the redisearch commands.")
+	fmt.Fprintln(cw, "# TYPE redisearch_command_duration_seconds histogram")
+	for _, stats := range snapshot.Commands {
+		writePrometheusHistogram(cw, "redisearch_command_duration_seconds", commandLabels(stats), stats.Latency)
+	}
+	fmt.Fprintln(cw, "# HELP redisearch_command_results Number of results of the search and aggregate commands.")
+	fmt.Fprintln(cw, "# TYPE redisearch_command_results histogram")
+	for _, stats := range snapshot.Commands {
+		if stats.Results.Count > 0 {
+			writePrometheusHistogram(cw, "redisearch_command_results", commandLabels(stats), stats.Results)
+		}
+	}
+	fmt.Fprintln(cw, "# HELP redisearch_command_errors_total Errors of the redisearch commands, by error type.")
+	fmt.Fprintln(cw, "# TYPE redisearch_command_errors_total counter")
+	for _, stats := range snapshot.Commands {
+		errTypes := make([]string, 0, len(stats.Errors))
+		for errType := range stats.Errors {
+			errTypes = append(errTypes, errType)
+		}
+		sort.Strings(errTypes)
+		for _, errType := range errTypes {
+			fmt.Fprintf(cw, "redisearch_command_errors_total{%s,type=%s} %d\n",
+				commandLabels(stats), strconv.Quote(errType), stats.Errors[errType])
+		}
+	}
+
+	pools := make([]string, 0, len(snapshot.Pools))
+	for name := range snapshot.Pools {
+		pools = append(pools, name)
+	}
+	sort.Strings(pools)
+	poolMetrics := []struct {
+		name, kind, help string
+		value            func(PoolStats) string
+	}{
+		{"redisearch_pool_active_connections", "gauge", "Connections in the pool, in use or idle.",
+			func(s PoolStats) string { return strconv.Itoa(s.ActiveCount) }},
+		{"redisearch_pool_idle_connections", "gauge", "Idle connections in the pool.",
+			func(s PoolStats) string { return strconv.Itoa(s.IdleCount) }},
+		{"redisearch_pool_wait_count_total", "counter", "Connections waited for.",
+			func(s PoolStats) string { return strconv.FormatInt(s.WaitCount, 10) }},
+		{"redisearch_pool_wait_duration_seconds_total", "counter", "Time spent waiting for connections.",
+			func(s PoolStats) string { return formatPrometheusFloat(s.WaitDuration.Seconds()) }},
+	}
+	for _, metric := range poolMetrics {
+		fmt.Fprintf(cw, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, name := range pools {
+			fmt.Fprintf(cw, "%s{pool=%s} %s\n", metric.name, strconv.Quote(name), metric.value(snapshot.Pools[name]))
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+func commandLabels(stats CommandStats) string {
+	return fmt.Sprintf("command=%s,index=%s", strconv.Quote(stats.Command), strconv.Quote(stats.Index))
+}
+
+func writePrometheusHistogram(w io.Writer, name, labels string, h Histogram) {
+	var cumulative int64
+	for ii, bound := range h.Buckets {
+		cumulative += h.Counts[ii]
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatPrometheusFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.Count)
+	fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatPrometheusFloat(h.Sum))
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.Count)
+}
+
+func formatPrometheusFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// internal struct
+// countingWriter counts the bytes written and keeps the first error
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// ExpvarSink is a MetricsSink publishing the measures as an expvar variable, served as JSON on /debug/vars
+type ExpvarSink struct {
+	*MetricsRegistry
+}
+
+// NewExpvarSink creates an expvar sink with the default buckets, and publishes it under the given name.
+// Like expvar.Publish, it panics if the name is already in use.
+func NewExpvarSink(name string) *ExpvarSink {
+	s := &ExpvarSink{NewMetricsRegistry()}
+	expvar.Publish(name, expvar.Func(s.value))
+	return s
+}
+
+// internal method
+// value returns the expvar value: the command stats keyed by "command index", and the pool stats keyed by name
+func (s *ExpvarSink) value() interface{} {
+	snapshot := s.Snapshot()
+	commands := make(map[string]interface{}, len(snapshot.Commands))
+	for _, stats := range snapshot.Commands {
+		key := strings.TrimSpace(stats.Command + " " + stats.Index)
+		commands[key] = map[string]interface{}{
+			"count":           stats.Latency.Count,
+			"latency_seconds": stats.Latency,
+			"results":         stats.Results,
+			"errors":          stats.Errors,
+		}
+	}
+	return map[string]interface{}{
+		"commands": commands,
+		"pools":    snapshot.Pools,
+	}
+}
@@ -0,0 +1,99 @@
+package redisearch
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetricsHook(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{
+		{Value: []interface{}{int64(3), []byte("doc1"), []interface{}{}}},
+		{Err: redis.Error("Unknown index name")},
+	}}
+	sink := NewPrometheusSink()
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(NewMetricsHook(sink))
+
+	_, _, err := c.Search(NewQuery("hello"))
+	assert.Nil(t, err)
+	_, err = c.Info()
+	assert.NotNil(t, err)
+
+	snapshot := sink.Snapshot()
+	assert.Len(t, snapshot.Commands, 2)
+	assert.Equal(t, "FT.INFO", snapshot.Commands[0].Command)
+	assert.Equal(t, map[string]int64{"server": 1}, snapshot.Commands[0].Errors)
+	assert.Equal(t, int64(0), snapshot.Commands[0].Results.Count)
+	assert.Equal(t, "FT.SEARCH", snapshot.Commands[1].Command)
+	assert.Equal(t, "idx", snapshot.Commands[1].Index)
+	assert.Equal(t, int64(1), snapshot.Commands[1].Latency.Count)
+	assert.Equal(t, []int64{0, 1, 0, 0, 0, 0, 0}, snapshot.Commands[1].Results.Counts)
+}
+
+func TestPrometheusSink_WriteTo(t *testing.T) {
+	sink := &PrometheusSink{NewMetricsRegistryWithBuckets([]float64{0.01, 0.1}, []float64{10})}
+	sink.ObserveCommand(CommandSample{Command: "FT.SEARCH", Index: "idx", Duration: 5 * time.Millisecond, Results: 3})
+	sink.ObserveCommand(CommandSample{Command: "FT.SEARCH", Index: "idx", Duration: 50 * time.Millisecond, Results: 20, ErrorType: "timeout"})
+	sink.SetPoolStats("main", PoolStats{ActiveCount: 3, IdleCount: 2, WaitCount: 7, WaitDuration: time.Second})
+
+	var buf bytes.Buffer
+	n, err := sink.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	out := buf.String()
+	for _, line := range []string{
+		`redisearch_command_duration_seconds_bucket{command="FT.SEARCH",index="idx",le="0.01"} 1`,
+		`redisearch_command_duration_seconds_bucket{command="FT.SEARCH",index="idx",le="0.1"} 2`,
+		`redisearch_command_duration_seconds_bucket{command="FT.SEARCH",index="idx",le="+Inf"} 2`,
+		`redisearch_command_duration_seconds_count{command="FT.SEARCH",index="idx"} 2`,
+		`redisearch_command_results_bucket{command="FT.SEARCH",index="idx",le="10"} 1`,
+		`redisearch_command_results_sum{command="FT.SEARCH",index="idx"} 23`,
+		`redisearch_command_errors_total{command="FT.SEARCH",index="idx",type="timeout"} 1`,
+		`redisearch_pool_active_connections{pool="main"} 3`,
+		`redisearch_pool_wait_duration_seconds_total{pool="main"} 1`,
+	} {
+		assert.True(t, strings.Contains(out, line+"\n"), line)
+	}
+}
+
+func TestExpvarSink(t *testing.T) {
+	sink := NewExpvarSink("redisearch_test_metrics")
+	sink.ObserveCommand(CommandSample{Command: "FT.INFO", Index: "idx", Duration: time.Millisecond, Results: -1})
+	value := expvar.Get("redisearch_test_metrics").String()
+	assert.True(t, strings.Contains(value, `"FT.INFO idx":{`), value)
+}
+
+func Test_errorType(t *testing.T) {
+	assert.Equal(t, "", errorType(nil))
+	assert.Equal(t, "server", errorType(redis.Error("ERR")))
+	assert.Equal(t, "client", errorType(errors.New("bad")))
+}
+
+func Test_connPoolStats(t *testing.T) {
+	pool := NewSingleHostPool("localhost:0")
+	c := &Client{pool: pool, name: "idx"}
+	c.AddHook(NewMetricsHook(NewMetricsRegistry()))
+	stats, ok := c.PoolStats()
+	assert.True(t, ok)
+	assert.Equal(t, PoolStats{}, stats)
+
+	_, ok = NewClientFromExecutor(&fakeExecutor{}, "idx").PoolStats()
+	assert.False(t, ok)
+
+	// the statistics are still found behind the retry and circuit breaker layers
+	c.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2})
+	stats, ok = c.PoolStats()
+	assert.True(t, ok)
+	assert.Equal(t, PoolStats{}, stats)
+	c.SetCircuitBreaker(&CircuitBreakerSettings{})
+	stats, ok = c.PoolStats()
+	assert.True(t, ok)
+	assert.Equal(t, PoolStats{}, stats)
+}
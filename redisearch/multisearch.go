@@ -0,0 +1,132 @@
+package redisearch
+
+import (
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SearchRequest is a single request of a MultiSearch batch.
+// Exactly one of Query (FT.SEARCH) or Aggregate (FT.AGGREGATE, or FT.CURSOR READ for a cursor with results) is set.
+type SearchRequest struct {
+	Query     *Query
+	Aggregate *AggregateQuery
+}
+
+// NewSearchRequest creates a batch request running FT.SEARCH with the given query
+func NewSearchRequest(q *Query) SearchRequest {
+	return SearchRequest{Query: q}
+}
+
+// NewAggregateRequest creates a batch request running FT.AGGREGATE with the given aggregation
+func NewAggregateRequest(q *AggregateQuery) SearchRequest {
+	return SearchRequest{Aggregate: q}
+}
+
+// SearchResult is the reply of a single MultiSearch request.
+// Docs is set for search requests and AggregateReply for aggregate requests. Total follows
+// the semantics of Search() and AggregateQuery() respectively.
+type SearchResult struct {
+	Docs           []Document
+	AggregateReply []map[string]interface{}
+	Total          int
+	// Warnings are the warnings returned by the server with RESP3, e.g. on timeouts or partial results
+	Warnings []string
+	Err      error
+}
+
+// MultiSearch runs several FT.SEARCH and FT.AGGREGATE requests in a single pipeline, using one connection
+// and one network round trip. The results are returned in the same order as the requests, and
+// each result carries its own error. The returned error is only set if the pipeline itself failed.
+func (i *Client) MultiSearch(requests ...SearchRequest) ([]SearchResult, error) {
+	results := make([]SearchResult, len(requests))
+	if len(requests) == 0 {
+		return results, nil
+	}
+
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	sent := make([]bool, len(requests))
+	n := 0
+	for ii, r := range requests {
+		cmd, args, err := i.searchRequestArgs(r)
+		if err != nil {
+			results[ii].Err = err
+			continue
+		}
+		if err := conn.Send(cmd, args...); err != nil {
+			return nil, err
+		}
+		sent[ii] = true
+		n++
+	}
+	if n == 0 {
+		return results, nil
+	}
+
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	for ii, r := range requests {
+		if !sent[ii] {
+			continue
+		}
+		res, err := conn.Receive()
+		if err != nil {
+			results[ii].Err = err
+			continue
+		}
+		results[ii] = processSearchRequestReply(r, res)
+	}
+	return results, nil
+}
+
+// Run runs a single FT.SEARCH or FT.AGGREGATE request. Unlike Search and AggregateQuery, the result carries
+// the warnings returned by the server with RESP3, e.g. when a query timed out with partial results.
+// Errors, including connection errors, are returned in the Err field of the result.
+func (i *Client) Run(r SearchRequest) SearchResult {
+	cmd, args, err := i.searchRequestArgs(r)
+	if err != nil {
+		return SearchResult{Err: err}
+	}
+	conn := i.pool.Get()
+	defer conn.Close()
+
+	res, err := conn.Do(cmd, args...)
+	if err != nil {
+		return SearchResult{Err: err}
+	}
+	return processSearchRequestReply(r, res)
+}
+
+// internal method
+// searchRequestArgs returns the command and arguments of a single batch request
+func (i *Client) searchRequestArgs(r SearchRequest) (cmd string, args []interface{}, err error) {
+	switch {
+	case r.Query != nil && r.Aggregate != nil:
+		err = errors.New("SearchRequest: only one of Query or Aggregate can be set")
+	case r.Query != nil:
+		cmd = "FT.SEARCH"
+		args = redis.Args{i.name}
+		args = append(args, r.Query.serialize()...)
+	case r.Aggregate != nil:
+		cmd, args, err = i.aggregateArgs(r.Aggregate)
+	default:
+		err = errors.New("SearchRequest: either Query or Aggregate must be set")
+	}
+	return
+}
+
+// internal function
+// processSearchRequestReply converts the reply of a single batch request
+func processSearchRequestReply(r SearchRequest, res interface{}) (result SearchResult) {
+	result.Warnings = replyWarnings(res)
+	if r.Query != nil {
+		result.Docs, result.Total, result.Err = processSearchReply(r.Query, res)
+	} else {
+		result.Total, result.AggregateReply, result.Err = r.Aggregate.processReply(res)
+	}
+	return
+}
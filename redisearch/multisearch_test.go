@@ -0,0 +1,145 @@
+package redisearch
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_searchRequestArgs(t *testing.T) {
+	c := &Client{name: "idx"}
+	tests := []struct {
+		name     string
+		request  SearchRequest
+		wantCmd  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{"search", NewSearchRequest(NewQuery("foo").Limit(0, 1)), "FT.SEARCH", []interface{}{"idx", "foo", "LIMIT", 0, 1}, false},
+		{"aggregate", NewAggregateRequest(NewAggregateQuery().SetQuery(NewQuery("foo"))), "FT.AGGREGATE", []interface{}{"idx", "foo"}, false},
+		{"cursor-read", NewAggregateRequest(NewAggregateQuery().SetCursor(NewCursor().SetId(7))), "FT.CURSOR", []interface{}{"READ", "idx", 7}, false},
+		{"invalid-aggregate", NewAggregateRequest(NewAggregateQuery().SetQuery(NewQuery("foo").SetReturnFields("a"))), "", nil, true},
+		{"empty", SearchRequest{}, "", nil, true},
+		{"both", SearchRequest{Query: NewQuery("foo"), Aggregate: NewAggregateQuery()}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, err := c.searchRequestArgs(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("searchRequestArgs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if cmd != tt.wantCmd || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("searchRequestArgs() = %v %v, want %v %v", cmd, args, tt.wantCmd, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_processSearchReply(t *testing.T) {
+	reply := []interface{}{
+		int64(2),
+		[]byte("doc1"), []byte("1.5"), []interface{}{[]byte("foo"), []byte("bar")},
+		[]byte("doc2"), []byte("0.5"), []interface{}{[]byte("foo"), []byte("baz")},
+	}
+	docs, total, err := processSearchReply(NewQuery("foo").SetFlags(QueryWithScores), reply)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, len(docs))
+	assert.Equal(t, "doc1", docs[0].Id)
+	assert.Equal(t, float32(1.5), docs[0].Score)
+	assert.Equal(t, "baz", docs[1].Properties["foo"])
+
+	docs, total, err = processSearchReply(NewQuery("foo").SetFlags(QueryNoContent), []interface{}{int64(1), []byte("doc1")})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "doc1", docs[0].Id)
+
+	_, _, err = processSearchReply(NewQuery("foo"), []interface{}{})
+	assert.NotNil(t, err)
+}
+
+func TestClient_MultiSearch(t *testing.T) {
+	skipInMemory(t, "FT.AGGREGATE")
+	c := createClient("testmultisearch")
+	c.Drop()
+
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("foo")).
+		AddField(NewTagField("tag"))
+	if err := c.CreateIndex(sc); err != nil {
+		t.Fatal(err)
+	}
+	docs := make([]Document, 10)
+	for i := 0; i < 10; i++ {
+		docs[i] = NewDocument(fmt.Sprintf("multisearch-doc%d", i), 1).
+			Set("foo", "hello world").
+			Set("tag", fmt.Sprintf("t%d", i%2))
+	}
+	assert.Nil(t, c.Index(docs...))
+
+	results, err := c.MultiSearch(
+		NewSearchRequest(NewQuery("hello").Limit(0, 3)),
+		NewAggregateRequest(NewAggregateQuery().SetQuery(NewQuery("*")).
+			GroupBy(*NewGroupBy().AddFields("@tag").Reduce(*Count()))),
+		NewAggregateRequest(NewAggregateQuery().SetQuery(NewQuery("hello").SetReturnFields("foo"))),
+		NewSearchRequest(NewQuery("@foo:(")),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, 10, results[0].Total)
+	assert.Equal(t, 3, len(results[0].Docs))
+
+	assert.Nil(t, results[1].Err)
+	assert.Equal(t, 2, len(results[1].AggregateReply))
+	assert.Equal(t, "5", results[1].AggregateReply[0]["count"])
+
+	assert.NotNil(t, results[2].Err)
+	assert.NotNil(t, results[3].Err)
+	teardown(c)
+}
+
+func TestClient_Run_RESP3(t *testing.T) {
+	warning := []interface{}{[]byte("Timeout limit was reached")}
+	exec := &fakeExecutor{replies: []Reply{
+		{Value: RESP3Map{
+			"results", []interface{}{RESP3Map{"id", []byte("doc1"), "extra_attributes", RESP3Map{[]byte("foo"), []byte("bar")}}},
+			"total_results", int64(3),
+			"warning", warning,
+		}},
+		{Value: []interface{}{RESP3Map{
+			"results", []interface{}{RESP3Map{"extra_attributes", RESP3Map{[]byte("brand"), []byte("a")}}},
+			"total_results", int64(1),
+			"warning", warning,
+		}, int64(7)}},
+		{Err: redis.Error("Unknown Index name")},
+	}}
+	c := NewClientFromExecutor(exec, "idx")
+
+	res := c.Run(NewSearchRequest(NewQuery("foo")))
+	assert.Nil(t, res.Err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, []Document{{Id: "doc1", Score: 1, Properties: map[string]interface{}{"foo": "bar"}}}, res.Docs)
+	assert.Equal(t, []string{"Timeout limit was reached"}, res.Warnings)
+	assert.Equal(t, []interface{}{"idx", "foo"}, exec.cmds[0].Args)
+
+	q := NewAggregateQuery().SetCursor(NewCursor())
+	res = c.Run(NewAggregateRequest(q))
+	assert.Nil(t, res.Err)
+	assert.Equal(t, []map[string]interface{}{{"brand": "a"}}, res.AggregateReply)
+	assert.Equal(t, []string{"Timeout limit was reached"}, res.Warnings)
+	assert.Equal(t, 7, q.Cursor.Id)
+
+	res = c.Run(NewSearchRequest(NewQuery("foo")))
+	assert.Equal(t, redis.Error("Unknown Index name"), res.Err)
+	assert.Nil(t, res.Warnings)
+
+	res = c.Run(SearchRequest{})
+	assert.EqualError(t, res.Err, "SearchRequest: either Query or Aggregate must be set")
+	assert.Len(t, exec.cmds, 3)
+}
@@ -45,9 +45,29 @@ func NewMultiHostPool(hosts []string) *MultiHostPool {
 }
 
 func (p *MultiHostPool) Get() redis.Conn {
+	conn, _ := p.getFrom(nil)
+	return conn
+}
+
+// internal method
+// getFrom returns a connection to a random host accepted by the filter (all the hosts if nil), and the host.
+// It returns a nil connection if no host is accepted.
+func (p *MultiHostPool) getFrom(accept func(host string) bool) (redis.Conn, string) {
 	p.Lock()
 	defer p.Unlock()
-	host := p.hosts[rand.Intn(len(p.hosts))]
+	hosts := p.hosts
+	if accept != nil {
+		hosts = make([]string, 0, len(p.hosts))
+		for _, host := range p.hosts {
+			if accept(host) {
+				hosts = append(hosts, host)
+			}
+		}
+		if len(hosts) == 0 {
+			return nil, ""
+		}
+	}
+	host := hosts[rand.Intn(len(hosts))]
 	pool, found := p.pools[host]
 	if !found {
 		pool = redis.NewPool(func() (redis.Conn, error) {
@@ -63,7 +83,7 @@ func (p *MultiHostPool) Get() redis.Conn {
 
 		p.pools[host] = pool
 	}
-	return pool.Get()
+	return pool.Get(), host
 
 }
 
@@ -83,3 +103,18 @@ func (p *MultiHostPool) Close() (err error) {
 	}
 	return
 }
+
+// Stats returns the sum of the statistics of the host pools
+func (p *MultiHostPool) Stats() redis.PoolStats {
+	p.Lock()
+	defer p.Unlock()
+	var stats redis.PoolStats
+	for _, pool := range p.pools {
+		s := pool.Stats()
+		stats.ActiveCount += s.ActiveCount
+		stats.IdleCount += s.IdleCount
+		stats.WaitCount += s.WaitCount
+		stats.WaitDuration += s.WaitDuration
+	}
+	return stats
+}
@@ -51,7 +51,7 @@ func NewSortingKeyDir(field string, ascending bool) *SortingKey {
 	}
 }
 
-func (s SortingKey) Serialize() redis.Args {
+func (s SortingKey) Serialize() []interface{} {
 	args := redis.Args{s.Field}
 	if s.Ascending {
 		args = args.Add("ASC")
@@ -113,7 +113,7 @@ func NewPaging(offset int, num int) *Paging {
 	}
 }
 
-func (p Paging) serialize() redis.Args {
+func (p Paging) serialize() []interface{} {
 	args := redis.Args{}
 	// only serialize something if it's different than the default
 	// The default is 0 10
@@ -134,7 +134,7 @@ func NewQuery(raw string) *Query {
 	}
 }
 
-func (q Query) serialize() redis.Args {
+func (q Query) serialize() []interface{} {
 
 	args := redis.Args{q.Raw}.AddFlat(q.Paging.serialize())
 	if q.Flags&QueryVerbatim != 0 {
@@ -238,8 +238,8 @@ func (q Query) serialize() redis.Args {
 
 	if q.Params != nil {
 		args = args.Add("PARAMS", len(q.Params)*2)
-		for name, value := range q.Params {
-			args = args.Add(name, value)
+		for _, name := range sortedKeys(q.Params) {
+			args = args.Add(name, q.Params[name])
 		}
 	}
 
@@ -250,7 +250,7 @@ func (q Query) serialize() redis.Args {
 	return args
 }
 
-func appendNumArgs(num float64, exclude bool, args redis.Args) redis.Args {
+func appendNumArgs(num float64, exclude bool, args []interface{}) []interface{} {
 	if math.IsInf(num, 1) {
 		return append(args, "+inf")
 	}
@@ -433,8 +433,8 @@ func (i *Client) IndexOptions(opts IndexingOptions, docs ...Document) error {
 
 		args = append(args, "FIELDS")
 
-		for k, f := range doc.Properties {
-			args = append(args, k, f)
+		for _, k := range sortedKeys(doc.Properties) {
+			args = append(args, k, doc.Properties[k])
 		}
 
 		if err := conn.Send("FT.ADD", args...); err != nil {
@@ -452,14 +452,13 @@ func (i *Client) IndexOptions(opts IndexingOptions, docs ...Document) error {
 		return err
 	}
 
-	for n > 0 {
+	for ii := 0; ii < n; ii++ {
 		if _, err := conn.Receive(); err != nil {
 			if merr == nil {
 				merr = NewMultiError(len(docs))
 			}
-			merr[n-1] = err
+			merr[ii] = err
 		}
-		n--
 	}
 
 	if merr == nil {
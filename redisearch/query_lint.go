@@ -0,0 +1,219 @@
+package redisearch
+
+import (
+	"strconv"
+	"strings"
+)
+
+// fieldTypeNames are the names of the field types in the schemas
+var fieldTypeNames = map[FieldType]string{
+	TextField:    "text",
+	NumericField: "numeric",
+	TagField:     "tag",
+	GeoField:     "geo",
+	VectorField:  "vector",
+}
+
+// queryField returns the field of a schema referenced by a query, by its AS name if it has one
+func queryField(schema *Schema, name string) (Field, bool) {
+	name = strings.ReplaceAll(name, "\\", "")
+	for _, f := range schema.Fields {
+		as, _ := fieldQueryOptions(f)
+		if as == "" {
+			as = f.Name
+		}
+		if as == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// fieldQueryOptions returns the AS name and the NOINDEX flag of a field
+func fieldQueryOptions(f Field) (as string, noIndex bool) {
+	switch opts := f.Options.(type) {
+	case TextFieldOptions:
+		return opts.As, opts.NoIndex
+	case NumericFieldOptions:
+		return opts.As, opts.NoIndex
+	case TagFieldOptions:
+		return opts.As, opts.NoIndex
+	case GeoFieldOptions:
+		return opts.As, opts.NoIndex
+	}
+	return "", false
+}
+
+// queryFieldSyntax returns the syntax to query a field type
+func queryFieldSyntax(name string, t FieldType) string {
+	switch t {
+	case NumericField:
+		return "@" + name + ":[min max]"
+	case TagField:
+		return "@" + name + ":{tag}"
+	case GeoField:
+		return "@" + name + ":[lon lat radius unit]"
+	case VectorField:
+		return "a KNN or VECTOR_RANGE query"
+	}
+	return "@" + name + ":term"
+}
+
+// Lint checks the query against the schema of the index: unknown or not indexed fields, and syntax not matching
+// the type of the field such as tags on a text field or a numeric range on a tag field
+func (p *ParsedQuery) Lint(schema *Schema) []*QueryError {
+	l := &queryLinter{raw: p.Raw, schema: schema, issues: make([]*QueryError, 0)}
+	l.lint(p.Root, nil, nil)
+	return l.issues
+}
+
+// internal struct
+// queryLinter walks a syntax tree with the fields its nodes apply to
+type queryLinter struct {
+	raw    string
+	schema *Schema
+	issues []*QueryError
+}
+
+func (l *queryLinter) issue(node QueryNode, format string, args ...interface{}) {
+	l.issues = append(l.issues, newQueryError(l.raw, node.Offset(), format, args...))
+}
+
+// lint checks a node, restricted to the fields of the enclosing field node when it is not nil
+func (l *queryLinter) lint(node QueryNode, field *FieldNode, fields []Field) {
+	switch n := node.(type) {
+	case *UnionNode:
+		for _, child := range n.Children {
+			l.lint(child, field, fields)
+		}
+	case *IntersectNode:
+		for _, child := range n.Children {
+			l.lint(child, field, fields)
+		}
+	case *NotNode:
+		l.lint(n.Child, field, fields)
+	case *OptionalNode:
+		l.lint(n.Child, field, fields)
+	case *AttributesNode:
+		l.lint(n.Child, field, fields)
+	case *KNNNode:
+		l.lint(n.Base, nil, nil)
+		if f, ok := queryField(l.schema, n.Field); !ok {
+			l.issue(n, "unknown field @%s", n.Field)
+		} else if f.Type != VectorField {
+			l.issue(n, "KNN on the %s field @%s", fieldTypeNames[f.Type], n.Field)
+		}
+	case *FieldNode:
+		fields = make([]Field, 0, len(n.Fields))
+		for _, name := range n.Fields {
+			f, ok := queryField(l.schema, name)
+			if !ok {
+				l.issue(n, "unknown field @%s", name)
+				continue
+			}
+			if _, noIndex := fieldQueryOptions(f); noIndex {
+				l.issue(n, "field @%s is not indexed", name)
+			}
+			fields = append(fields, f)
+		}
+		l.lint(n.Child, n, fields)
+	default:
+		if field != nil {
+			l.lintFields(field, node, fields)
+		}
+	}
+}
+
+// lintFields checks that the syntax of a node restricted to fields matches their types
+func (l *queryLinter) lintFields(field *FieldNode, node QueryNode, fields []Field) {
+	var want FieldType
+	var syntax string
+	switch node.(type) {
+	case *TagNode:
+		want, syntax = TagField, "tag syntax {...}"
+	case *NumericRangeNode:
+		want, syntax = NumericField, "numeric range"
+	case *GeoNode:
+		want, syntax = GeoField, "geo filter"
+	case *VectorRangeNode:
+		want, syntax = VectorField, "VECTOR_RANGE"
+	case *TermNode, *PhraseNode, *ParamNode, *PatternNode:
+		want, syntax = TextField, "text query"
+	default:
+		return
+	}
+	for _, f := range fields {
+		if f.Type == want {
+			continue
+		}
+		name, _ := fieldQueryOptions(f)
+		if name == "" {
+			name = f.Name
+		}
+		l.issue(field, "%s on the %s field @%s, use %s", syntax, fieldTypeNames[f.Type], name, queryFieldSyntax(name, f.Type))
+	}
+}
+
+// ValidateQuery parses a query string and lints it against the schema if it is not nil. It returns the
+// *QueryError of the syntax error, or a MultiError of the *QueryError of the issues found by the linter.
+func ValidateQuery(raw string, dialect int, schema *Schema) error {
+	parsed, err := ParseQuery(raw, dialect)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	issues := parsed.Lint(schema)
+	if len(issues) == 0 {
+		return nil
+	}
+	merr := NewMultiError(len(issues))
+	for ii, issue := range issues {
+		merr[ii] = issue
+	}
+	return merr
+}
+
+// Validate parses the query string of the query and lints it against the schema if it is not nil, see ValidateQuery
+func (q *Query) Validate(schema *Schema) error {
+	return ValidateQuery(q.Raw, q.Dialect, schema)
+}
+
+// NewQueryValidationHook creates a hook validating the query strings of FT.SEARCH, FT.AGGREGATE, FT.EXPLAIN
+// and FT.SPELLCHECK before they are sent, so that the invalid queries fail without a network call. The queries
+// are linted against the schema if it is not nil. The queries without a DIALECT argument are parsed with the
+// default dialect, 0 for dialect 1.
+func NewQueryValidationHook(schema *Schema, defaultDialect int) Hook {
+	return &queryValidationHook{schema: schema, defaultDialect: defaultDialect}
+}
+
+// internal struct
+type queryValidationHook struct {
+	schema         *Schema
+	defaultDialect int
+}
+
+func (h *queryValidationHook) BeforeCommand(cmd *CommandInfo) error {
+	switch strings.ToUpper(cmd.Name) {
+	case "FT.SEARCH", "FT.AGGREGATE", "FT.EXPLAIN", "FT.EXPLAINCLI", "FT.SPELLCHECK":
+	default:
+		return nil
+	}
+	if len(cmd.Args) < 2 {
+		return nil
+	}
+	dialect := h.defaultDialect
+	// DIALECT is the last argument of the queries
+	for ii := len(cmd.Args) - 2; ii > 1; ii-- {
+		if strings.EqualFold(string(formatRESP3Arg(cmd.Args[ii], true)), "DIALECT") {
+			if d, err := strconv.Atoi(string(formatRESP3Arg(cmd.Args[ii+1], true))); err == nil {
+				dialect = d
+				break
+			}
+		}
+	}
+	return ValidateQuery(string(formatRESP3Arg(cmd.Args[1], true)), dialect, h.schema)
+}
+
+func (h *queryValidationHook) AfterCommand(cmd *CommandInfo) {}
@@ -0,0 +1,960 @@
+package redisearch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// QueryError is a syntax error of a query string, or an issue found by linting it against a schema
+type QueryError struct {
+	// Offset is the position of the error in the query string, in bytes
+	Offset int
+	// Column is the position of the error in the query string, in characters starting at 1
+	Column int
+	Msg    string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("redisearch: %s at column %d", e.Msg, e.Column)
+}
+
+func newQueryError(raw string, offset int, format string, args ...interface{}) *QueryError {
+	if offset > len(raw) {
+		offset = len(raw)
+	}
+	return &QueryError{Offset: offset, Column: utf8.RuneCountInString(raw[:offset]) + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// QueryNode is a node of the syntax tree of a query string
+type QueryNode interface {
+	// Offset returns the position of the node in the query string, in bytes
+	Offset() int
+	// String renders the node in the normalized query syntax
+	String() string
+}
+
+type nodeOffset int
+
+func (o nodeOffset) Offset() int {
+	return int(o)
+}
+
+// UnionNode matches the documents matching any of its children: a | b
+type UnionNode struct {
+	nodeOffset
+	Children []QueryNode
+}
+
+// IntersectNode matches the documents matching all of its children: a b
+type IntersectNode struct {
+	nodeOffset
+	Children []QueryNode
+}
+
+// NotNode matches the documents not matching its child: -a
+type NotNode struct {
+	nodeOffset
+	Child QueryNode
+}
+
+// OptionalNode ranks higher the documents matching its child, without filtering the other ones: ~a
+type OptionalNode struct {
+	nodeOffset
+	Child QueryNode
+}
+
+// FieldNode restricts its child to some fields: @title:a, @title|body:(a b)
+type FieldNode struct {
+	nodeOffset
+	Fields []string
+	Child  QueryNode
+}
+
+// TermNode matches a term, as written in the query with its escapes: hello, hel*, *llo, %helo%
+type TermNode struct {
+	nodeOffset
+	Term string
+	// Prefix and Suffix are set for the prefix (term*), suffix (*term) and infix (*term*) queries
+	Prefix bool
+	Suffix bool
+	// Fuzzy is the Levenshtein distance of the fuzzy queries, from 1 (%term%) to 3 (%%%term%%%)
+	Fuzzy int
+}
+
+// PhraseNode matches an exact phrase, as written in the query with its escapes: "hello world"
+type PhraseNode struct {
+	nodeOffset
+	Text string
+}
+
+// ParamNode is a parameter, replaced by the value given in the PARAMS of the query: $name
+type ParamNode struct {
+	nodeOffset
+	Name string
+}
+
+// WildcardNode matches all the documents: *
+type WildcardNode struct {
+	nodeOffset
+}
+
+// PatternNode matches the terms matching a wildcard pattern: w'hel?o*'
+type PatternNode struct {
+	nodeOffset
+	Pattern string
+}
+
+// TagNode matches any of the tags, as written in the query with their escapes: {a | b\ c | pre* | $param}
+type TagNode struct {
+	nodeOffset
+	Values []string
+}
+
+// NumericRangeNode matches a numeric range: [10 (20], the bounds are numbers, -inf, +inf or parameters
+type NumericRangeNode struct {
+	nodeOffset
+	Min          string
+	Max          string
+	ExclusiveMin bool
+	ExclusiveMax bool
+}
+
+// GeoNode matches the locations within a radius: [lon lat radius unit]
+type GeoNode struct {
+	nodeOffset
+	Lon    string
+	Lat    string
+	Radius string
+	Unit   string
+}
+
+// VectorRangeNode matches the vectors within a distance of a vector parameter: [VECTOR_RANGE radius $param]
+type VectorRangeNode struct {
+	nodeOffset
+	Radius string
+	Param  string
+}
+
+// KNNNode returns the K nearest neighbors of a vector parameter among the documents matching the base query:
+// base=>[KNN k @field $param AS score]
+type KNNNode struct {
+	nodeOffset
+	Base  QueryNode
+	K     string
+	Field string
+	Param string
+	// Options are the words following the parameter, such as AS score or EF_RUNTIME 10
+	Options []string
+}
+
+// QueryAttribute is an attribute of a query node, such as $weight or $slop
+type QueryAttribute struct {
+	Name  string
+	Value string
+}
+
+// AttributesNode sets attributes to its child: (a b)=>{$slop: 1; $inorder: true}
+type AttributesNode struct {
+	nodeOffset
+	Child      QueryNode
+	Attributes []QueryAttribute
+}
+
+// queryAttributes are the attributes accepted in =>{...}
+var queryAttributes = map[string]bool{
+	"weight": true, "slop": true, "inorder": true, "phonetic": true,
+	"yield_distance_as": true, "ef_runtime": true, "epsilon": true,
+}
+
+// ParsedQuery is the syntax tree of a query string
+type ParsedQuery struct {
+	Raw     string
+	Dialect int
+	Root    QueryNode
+}
+
+// ParseQuery parses a query string of the given dialect, 0 for the default dialect 1. It returns a *QueryError
+// with the position of the first syntax error.
+//
+// Dialect 1 gives union a higher precedence than intersection (a b | c is a (b | c)), dialects 2 and above give it
+// a lower one ((a b) | c), and accept the parameters, the KNN and VECTOR_RANGE vector queries and the w'...'
+// patterns.
+func ParseQuery(raw string, dialect int) (*ParsedQuery, error) {
+	if dialect == 0 {
+		dialect = 1
+	}
+	if dialect < 1 || dialect > 4 {
+		return nil, newQueryError(raw, 0, "unsupported dialect %d", dialect)
+	}
+	p := &queryParser{raw: raw, dialect: dialect}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(raw) {
+		return nil, p.errorf(p.pos, "unexpected %q", p.raw[p.pos])
+	}
+	if err := p.checkKNN(root, true); err != nil {
+		return nil, err
+	}
+	return &ParsedQuery{Raw: raw, Dialect: dialect, Root: root}, nil
+}
+
+// Parse parses the query string of the query, with its dialect
+func (q *Query) Parse() (*ParsedQuery, error) {
+	return ParseQuery(q.Raw, q.Dialect)
+}
+
+// String renders the query in the normalized syntax: single spaces between the terms, " | " between the
+// alternatives, and parentheses only around the nested unions and intersections
+func (p *ParsedQuery) String() string {
+	return p.Root.String()
+}
+
+// internal struct
+// queryParser is a recursive descent parser of the query syntax
+type queryParser struct {
+	raw     string
+	pos     int
+	dialect int
+}
+
+func (p *queryParser) errorf(offset int, format string, args ...interface{}) *QueryError {
+	return newQueryError(p.raw, offset, format, args...)
+}
+
+// peek returns the next byte, 0 at the end of the query
+func (p *queryParser) peek() byte {
+	if p.pos >= len(p.raw) {
+		return 0
+	}
+	return p.raw[p.pos]
+}
+
+// querySeparators are the punctuation characters separating the terms, without a meaning in the query syntax
+const querySeparators = ",.!/&#^+<>?;'`="
+
+// skipSpaces skips the spaces and the separators
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.raw) {
+		c := p.raw[p.pos]
+		if c == '=' && strings.HasPrefix(p.raw[p.pos:], "=>") {
+			return
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' && strings.IndexByte(querySeparators, c) < 0 {
+			return
+		}
+		p.pos++
+	}
+}
+
+// readTerm reads a run of term characters and escaped characters
+func (p *queryParser) readTerm() string {
+	start := p.pos
+	for p.pos < len(p.raw) {
+		if p.raw[p.pos] == '\\' && p.pos+1 < len(p.raw) {
+			_, size := utf8.DecodeRuneInString(p.raw[p.pos+1:])
+			p.pos += 1 + size
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(p.raw[p.pos:])
+		if !isTermRune(r) {
+			break
+		}
+		p.pos += size
+	}
+	return p.raw[start:p.pos]
+}
+
+func (p *queryParser) atTerm() bool {
+	if p.pos >= len(p.raw) {
+		return false
+	}
+	if p.raw[p.pos] == '\\' {
+		return p.pos+1 < len(p.raw)
+	}
+	r, _ := utf8.DecodeRuneInString(p.raw[p.pos:])
+	return isTermRune(r)
+}
+
+// parseExpr parses the expression up to stop, 0 for the end of the query
+func (p *queryParser) parseExpr(stop byte) (QueryNode, error) {
+	if p.dialect == 1 {
+		return p.parseIntersect(stop, p.parseUnionOfUnary)
+	}
+	start := p.pos
+	first, err := p.parseIntersect(stop, p.parseUnary)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []QueryNode{first}
+	for p.skipSpaces(); p.peek() == '|'; p.skipSpaces() {
+		p.pos++
+		node, err := p.parseIntersect(stop, p.parseUnary)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return newUnion(start, nodes), nil
+}
+
+// parseIntersect parses a sequence of operands up to stop or a union
+func (p *queryParser) parseIntersect(stop byte, operand func(stop byte) (QueryNode, error)) (QueryNode, error) {
+	p.skipSpaces()
+	start := p.pos
+	nodes := make([]QueryNode, 0)
+	for {
+		p.skipSpaces()
+		if c := p.peek(); c == 0 || c == stop || c == '|' {
+			break
+		}
+		node, err := operand(stop)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		if p.pos >= len(p.raw) {
+			return nil, p.errorf(p.pos, "unexpected end of query")
+		}
+		return nil, p.errorf(p.pos, "unexpected %q", p.raw[p.pos])
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	children := make([]QueryNode, 0, len(nodes))
+	for _, node := range nodes {
+		if inner, ok := node.(*IntersectNode); ok {
+			children = append(children, inner.Children...)
+		} else {
+			children = append(children, node)
+		}
+	}
+	return &IntersectNode{nodeOffset(start), children}, nil
+}
+
+// parseUnionOfUnary parses the unions of dialect 1, which bind tighter than the intersections
+func (p *queryParser) parseUnionOfUnary(stop byte) (QueryNode, error) {
+	start := p.pos
+	first, err := p.parseUnary(stop)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []QueryNode{first}
+	for p.skipSpaces(); p.peek() == '|'; p.skipSpaces() {
+		p.pos++
+		p.skipSpaces()
+		if c := p.peek(); c == 0 || c == stop || c == '|' {
+			return nil, p.errorf(p.pos, "missing operand after |")
+		}
+		node, err := p.parseUnary(stop)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return newUnion(start, nodes), nil
+}
+
+func newUnion(start int, nodes []QueryNode) QueryNode {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	children := make([]QueryNode, 0, len(nodes))
+	for _, node := range nodes {
+		if inner, ok := node.(*UnionNode); ok {
+			children = append(children, inner.Children...)
+		} else {
+			children = append(children, node)
+		}
+	}
+	return &UnionNode{nodeOffset(start), children}
+}
+
+func (p *queryParser) parseUnary(stop byte) (QueryNode, error) {
+	p.skipSpaces()
+	start := p.pos
+	switch p.peek() {
+	case '-', '~':
+		op := p.peek()
+		p.pos++
+		p.skipSpaces()
+		if c := p.peek(); c == 0 || c == stop || c == '|' {
+			return nil, p.errorf(p.pos, "missing operand after %c", op)
+		}
+		child, err := p.parseUnary(stop)
+		if err != nil {
+			return nil, err
+		}
+		if op == '-' {
+			return &NotNode{nodeOffset(start), child}, nil
+		}
+		return &OptionalNode{nodeOffset(start), child}, nil
+	}
+	node, err := p.parsePrimary(stop)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseArrow(node)
+}
+
+func (p *queryParser) parsePrimary(stop byte) (QueryNode, error) {
+	start := p.pos
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		node, err := p.parseExpr(')')
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf(start, "missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	case c == '@':
+		return p.parseField(stop)
+	case c == '"':
+		return p.parsePhrase()
+	case c == '%':
+		return p.parseFuzzy()
+	case c == '$':
+		return p.parseParam()
+	case c == '*':
+		p.pos++
+		if !p.atTerm() {
+			return &WildcardNode{nodeOffset(start)}, nil
+		}
+		term := &TermNode{nodeOffset: nodeOffset(start), Term: p.readTerm(), Suffix: true}
+		if p.peek() == '*' {
+			p.pos++
+			term.Prefix = true
+		}
+		return term, nil
+	case c == 'w' && strings.HasPrefix(p.raw[p.pos:], "w'") && p.dialect >= 2:
+		return p.parsePattern()
+	case c == '{' || c == '[':
+		return nil, p.errorf(start, "%q must follow a field, e.g. @field:%c...", c, c)
+	case p.atTerm():
+		term := &TermNode{nodeOffset: nodeOffset(start), Term: p.readTerm()}
+		if p.peek() == '*' {
+			p.pos++
+			term.Prefix = true
+		}
+		return term, nil
+	case c == 0:
+		return nil, p.errorf(start, "unexpected end of query")
+	}
+	return nil, p.errorf(start, "unexpected %q", c)
+}
+
+func (p *queryParser) parseField(stop byte) (QueryNode, error) {
+	start := p.pos
+	fields := make([]string, 0, 1)
+	for {
+		p.pos++
+		name := p.readTerm()
+		if name == "" {
+			return nil, p.errorf(p.pos, "missing field name after %c", p.raw[p.pos-1])
+		}
+		fields = append(fields, name)
+		if p.peek() != '|' {
+			break
+		}
+	}
+	if p.peek() != ':' {
+		return nil, p.errorf(p.pos, "missing : after the field name")
+	}
+	p.pos++
+	p.skipSpaces()
+	node := &FieldNode{nodeOffset: nodeOffset(start), Fields: fields}
+	var err error
+	switch p.peek() {
+	case '{':
+		node.Child, err = p.parseTags()
+	case '[':
+		node.Child, err = p.parseRange()
+	case 0, ')', '|':
+		err = p.errorf(p.pos, "missing expression after the field")
+	default:
+		node.Child, err = p.parseUnary(stop)
+		if _, ok := node.Child.(*FieldNode); ok && err == nil {
+			err = p.errorf(node.Child.Offset(), "nested field modifier")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *queryParser) parsePhrase() (QueryNode, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.raw) && p.raw[p.pos] != '"' {
+		if p.raw[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.raw) {
+		return nil, p.errorf(start, "unterminated phrase")
+	}
+	text := p.raw[start+1 : p.pos]
+	p.pos++
+	if strings.TrimSpace(text) == "" {
+		return nil, p.errorf(start, "empty phrase")
+	}
+	return &PhraseNode{nodeOffset(start), text}, nil
+}
+
+func (p *queryParser) parseFuzzy() (QueryNode, error) {
+	start := p.pos
+	for p.peek() == '%' {
+		p.pos++
+	}
+	distance := p.pos - start
+	if distance > 3 {
+		return nil, p.errorf(start, "fuzzy distance above 3")
+	}
+	term := p.readTerm()
+	if term == "" {
+		return nil, p.errorf(p.pos, "missing term after %%")
+	}
+	if !strings.HasPrefix(p.raw[p.pos:], strings.Repeat("%", distance)) {
+		return nil, p.errorf(p.pos, "missing closing %s", strings.Repeat("%", distance))
+	}
+	p.pos += distance
+	return &TermNode{nodeOffset: nodeOffset(start), Term: term, Fuzzy: distance}, nil
+}
+
+func (p *queryParser) parseParam() (QueryNode, error) {
+	start := p.pos
+	name, err := p.readParam()
+	if err != nil {
+		return nil, err
+	}
+	return &ParamNode{nodeOffset(start), name}, nil
+}
+
+// readParam reads a $name parameter
+func (p *queryParser) readParam() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.raw) && isParamByte(p.raw[p.pos]) {
+		p.pos++
+	}
+	if err := p.checkParam(p.raw[start:p.pos], start); err != nil {
+		return "", err
+	}
+	return p.raw[start+1 : p.pos], nil
+}
+
+func isParamByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// checkParam checks a $name parameter written at the offset
+func (p *queryParser) checkParam(text string, offset int) error {
+	if p.dialect < 2 {
+		return p.errorf(offset, "parameters require DIALECT 2 or above")
+	}
+	if len(text) < 2 {
+		return p.errorf(offset, "missing parameter name after $")
+	}
+	for ii := 1; ii < len(text); ii++ {
+		if !isParamByte(text[ii]) {
+			return p.errorf(offset, "invalid parameter %s", text)
+		}
+	}
+	return nil
+}
+
+func (p *queryParser) parsePattern() (QueryNode, error) {
+	start := p.pos
+	p.pos += 2
+	for p.pos < len(p.raw) && p.raw[p.pos] != '\'' {
+		if p.raw[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.raw) {
+		return nil, p.errorf(start, "unterminated pattern")
+	}
+	pattern := p.raw[start+2 : p.pos]
+	p.pos++
+	return &PatternNode{nodeOffset(start), pattern}, nil
+}
+
+func (p *queryParser) parseTags() (QueryNode, error) {
+	start := p.pos
+	p.pos++
+	values := make([]string, 0)
+	for {
+		valueStart := p.pos
+		// the value ends after its last character that is not an unescaped space
+		valueEnd := p.pos
+		for p.pos < len(p.raw) && p.raw[p.pos] != '|' && p.raw[p.pos] != '}' {
+			if p.raw[p.pos] == '\\' && p.pos+1 < len(p.raw) {
+				p.pos++
+			} else if p.raw[p.pos] == ' ' || p.raw[p.pos] == '\t' {
+				p.pos++
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(p.raw[p.pos:])
+			p.pos += size
+			valueEnd = p.pos
+		}
+		if p.pos >= len(p.raw) {
+			return nil, p.errorf(start, "missing closing }")
+		}
+		value := strings.TrimLeft(p.raw[valueStart:valueEnd], " \t")
+		if value == "" {
+			return nil, p.errorf(valueStart, "empty tag")
+		}
+		if value[0] == '$' {
+			if err := p.checkParam(value, valueStart+strings.IndexByte(p.raw[valueStart:], '$')); err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, value)
+		p.pos++
+		if p.raw[p.pos-1] == '}' {
+			break
+		}
+	}
+	return &TagNode{nodeOffset(start), values}, nil
+}
+
+// queryWord is a word of a range or of a KNN query, with its position
+type queryWord struct {
+	text   string
+	offset int
+}
+
+// readWords reads the space separated words up to the closing bracket
+func (p *queryParser) readWords(open int) ([]queryWord, error) {
+	p.pos++
+	words := make([]queryWord, 0)
+	for {
+		for p.pos < len(p.raw) && (p.raw[p.pos] == ' ' || p.raw[p.pos] == '\t') {
+			p.pos++
+		}
+		if p.pos >= len(p.raw) {
+			return nil, p.errorf(open, "missing closing ]")
+		}
+		if p.raw[p.pos] == ']' {
+			p.pos++
+			return words, nil
+		}
+		start := p.pos
+		for p.pos < len(p.raw) && p.raw[p.pos] != ' ' && p.raw[p.pos] != '\t' && p.raw[p.pos] != ']' {
+			p.pos++
+		}
+		words = append(words, queryWord{p.raw[start:p.pos], start})
+	}
+}
+
+// checkNumber checks that a word is a number or a parameter
+func (p *queryParser) checkNumber(w queryWord, what string) error {
+	if strings.HasPrefix(w.text, "$") {
+		return p.checkParam(w.text, w.offset)
+	}
+	if _, err := strconv.ParseFloat(w.text, 64); err != nil {
+		return p.errorf(w.offset, "invalid %s %q", what, w.text)
+	}
+	return nil
+}
+
+func (p *queryParser) parseRange() (QueryNode, error) {
+	start := p.pos
+	words, err := p.readWords(start)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(words) > 0 && strings.EqualFold(words[0].text, "VECTOR_RANGE"):
+		if p.dialect < 2 {
+			return nil, p.errorf(start, "VECTOR_RANGE requires DIALECT 2 or above")
+		}
+		if len(words) != 3 {
+			return nil, p.errorf(start, "expected [VECTOR_RANGE radius $param]")
+		}
+		if err := p.checkNumber(words[1], "radius"); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(words[2].text, "$") {
+			return nil, p.errorf(words[2].offset, "expected a vector parameter")
+		}
+		if err := p.checkParam(words[2].text, words[2].offset); err != nil {
+			return nil, err
+		}
+		return &VectorRangeNode{nodeOffset(start), words[1].text, words[2].text[1:]}, nil
+	case len(words) == 2:
+		node := &NumericRangeNode{nodeOffset: nodeOffset(start)}
+		bounds := []*string{&node.Min, &node.Max}
+		exclusive := []*bool{&node.ExclusiveMin, &node.ExclusiveMax}
+		for ii, w := range words {
+			if strings.HasPrefix(w.text, "(") {
+				*exclusive[ii] = true
+				w = queryWord{w.text[1:], w.offset + 1}
+			}
+			switch strings.ToLower(w.text) {
+			case "inf", "+inf", "-inf":
+			default:
+				if err := p.checkNumber(w, "numeric bound"); err != nil {
+					return nil, err
+				}
+			}
+			*bounds[ii] = w.text
+		}
+		return node, nil
+	case len(words) == 4:
+		for ii, what := range []string{"longitude", "latitude", "radius"} {
+			if err := p.checkNumber(words[ii], what); err != nil {
+				return nil, err
+			}
+		}
+		switch strings.ToLower(words[3].text) {
+		case "m", "km", "mi", "ft":
+		default:
+			return nil, p.errorf(words[3].offset, "invalid geo unit %q, expected m, km, mi or ft", words[3].text)
+		}
+		return &GeoNode{nodeOffset(start), words[0].text, words[1].text, words[2].text, words[3].text}, nil
+	}
+	return nil, p.errorf(start, "expected [min max], [lon lat radius unit] or [VECTOR_RANGE radius $param]")
+}
+
+// parseArrow parses the attributes or the KNN query following a node
+func (p *queryParser) parseArrow(node QueryNode) (QueryNode, error) {
+	saved := p.pos
+	for p.pos < len(p.raw) && (p.raw[p.pos] == ' ' || p.raw[p.pos] == '\t') {
+		p.pos++
+	}
+	if !strings.HasPrefix(p.raw[p.pos:], "=>") {
+		p.pos = saved
+		return node, nil
+	}
+	arrow := p.pos
+	p.pos += 2
+	for p.pos < len(p.raw) && (p.raw[p.pos] == ' ' || p.raw[p.pos] == '\t') {
+		p.pos++
+	}
+	switch p.peek() {
+	case '{':
+		return p.parseAttributes(node)
+	case '[':
+		return p.parseKNN(node, arrow)
+	}
+	return nil, p.errorf(p.pos, "expected { or [ after =>")
+}
+
+func (p *queryParser) parseAttributes(node QueryNode) (QueryNode, error) {
+	open := p.pos
+	end := strings.IndexByte(p.raw[p.pos:], '}')
+	if end < 0 {
+		return nil, p.errorf(open, "missing closing }")
+	}
+	attrs := make([]QueryAttribute, 0)
+	offset := p.pos + 1
+	for _, part := range strings.Split(p.raw[p.pos+1:p.pos+end], ";") {
+		partOffset := offset
+		offset += len(part) + 1
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		colon := strings.IndexByte(part, ':')
+		name := strings.TrimSpace(part)
+		if colon >= 0 {
+			name = strings.TrimSpace(part[:colon])
+		}
+		if !strings.HasPrefix(name, "$") || !queryAttributes[strings.ToLower(name[1:])] {
+			return nil, p.errorf(partOffset+strings.Index(part, name), "unknown attribute %s", name)
+		}
+		value := ""
+		if colon >= 0 {
+			value = strings.TrimSpace(part[colon+1:])
+		}
+		if value == "" {
+			return nil, p.errorf(partOffset, "missing value of the attribute %s", name)
+		}
+		attrs = append(attrs, QueryAttribute{Name: name[1:], Value: value})
+	}
+	p.pos += end + 1
+	return &AttributesNode{nodeOffset(node.Offset()), node, attrs}, nil
+}
+
+func (p *queryParser) parseKNN(base QueryNode, arrow int) (QueryNode, error) {
+	open := p.pos
+	if p.dialect < 2 {
+		return nil, p.errorf(arrow, "KNN queries require DIALECT 2 or above")
+	}
+	words, err := p.readWords(open)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) < 4 || !strings.EqualFold(words[0].text, "KNN") {
+		return nil, p.errorf(open, "expected [KNN k @field $param]")
+	}
+	if strings.HasPrefix(words[1].text, "$") {
+		if err := p.checkParam(words[1].text, words[1].offset); err != nil {
+			return nil, err
+		}
+	} else if _, err := strconv.Atoi(words[1].text); err != nil {
+		return nil, p.errorf(words[1].offset, "invalid number of neighbors %q", words[1].text)
+	}
+	if !strings.HasPrefix(words[2].text, "@") || len(words[2].text) == 1 {
+		return nil, p.errorf(words[2].offset, "expected a @field")
+	}
+	if !strings.HasPrefix(words[3].text, "$") {
+		return nil, p.errorf(words[3].offset, "expected a vector parameter")
+	}
+	if err := p.checkParam(words[3].text, words[3].offset); err != nil {
+		return nil, err
+	}
+	options := make([]string, 0, len(words)-4)
+	for _, w := range words[4:] {
+		options = append(options, w.text)
+	}
+	return &KNNNode{nodeOffset(base.Offset()), base, words[1].text, words[2].text[1:], words[3].text[1:], options}, nil
+}
+
+// checkKNN checks that the KNN queries apply to the whole query
+func (p *queryParser) checkKNN(node QueryNode, root bool) error {
+	var children []QueryNode
+	switch n := node.(type) {
+	case *KNNNode:
+		if !root {
+			return p.errorf(n.Offset(), "a KNN query must apply to the whole query")
+		}
+		children = []QueryNode{n.Base}
+	case *UnionNode:
+		children = n.Children
+	case *IntersectNode:
+		children = n.Children
+	case *NotNode:
+		children = []QueryNode{n.Child}
+	case *OptionalNode:
+		children = []QueryNode{n.Child}
+	case *FieldNode:
+		children = []QueryNode{n.Child}
+	case *AttributesNode:
+		children = []QueryNode{n.Child}
+	}
+	for _, child := range children {
+		if err := p.checkKNN(child, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// group renders a node, between parentheses if it is a union or an intersection
+func group(node QueryNode) string {
+	switch node.(type) {
+	case *UnionNode, *IntersectNode:
+		return "(" + node.String() + ")"
+	}
+	return node.String()
+}
+
+func (n *UnionNode) String() string {
+	parts := make([]string, len(n.Children))
+	for ii, child := range n.Children {
+		parts[ii] = group(child)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (n *IntersectNode) String() string {
+	parts := make([]string, len(n.Children))
+	for ii, child := range n.Children {
+		parts[ii] = group(child)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (n *NotNode) String() string {
+	return "-" + group(n.Child)
+}
+
+func (n *OptionalNode) String() string {
+	return "~" + group(n.Child)
+}
+
+func (n *FieldNode) String() string {
+	return "@" + strings.Join(n.Fields, "|") + ":" + group(n.Child)
+}
+
+func (n *TermNode) String() string {
+	term := n.Term
+	if n.Suffix {
+		term = "*" + term
+	}
+	if n.Prefix {
+		term += "*"
+	}
+	fuzzy := strings.Repeat("%", n.Fuzzy)
+	return fuzzy + term + fuzzy
+}
+
+func (n *PhraseNode) String() string {
+	return `"` + n.Text + `"`
+}
+
+func (n *ParamNode) String() string {
+	return "$" + n.Name
+}
+
+func (n *WildcardNode) String() string {
+	return "*"
+}
+
+func (n *PatternNode) String() string {
+	return "w'" + n.Pattern + "'"
+}
+
+func (n *TagNode) String() string {
+	return "{" + strings.Join(n.Values, " | ") + "}"
+}
+
+func (n *NumericRangeNode) String() string {
+	bound := func(value string, exclusive bool) string {
+		if exclusive {
+			return "(" + value
+		}
+		return value
+	}
+	return "[" + bound(n.Min, n.ExclusiveMin) + " " + bound(n.Max, n.ExclusiveMax) + "]"
+}
+
+func (n *GeoNode) String() string {
+	return "[" + strings.Join([]string{n.Lon, n.Lat, n.Radius, n.Unit}, " ") + "]"
+}
+
+func (n *VectorRangeNode) String() string {
+	return "[VECTOR_RANGE " + n.Radius + " $" + n.Param + "]"
+}
+
+func (n *KNNNode) String() string {
+	base := "*"
+	if _, ok := n.Base.(*WildcardNode); !ok {
+		base = "(" + n.Base.String() + ")"
+	}
+	words := append([]string{"KNN", n.K, "@" + n.Field, "$" + n.Param}, n.Options...)
+	return base + "=>[" + strings.Join(words, " ") + "]"
+}
+
+func (n *AttributesNode) String() string {
+	attrs := make([]string, len(n.Attributes))
+	for ii, attr := range n.Attributes {
+		attrs[ii] = "$" + attr.Name + ": " + attr.Value
+	}
+	return group(n.Child) + "=>{" + strings.Join(attrs, "; ") + "}"
+}
@@ -0,0 +1,170 @@
+package redisearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery_normalize(t *testing.T) {
+	tests := []struct {
+		raw     string
+		dialect int
+		want    string
+	}{
+		{"hello   world", 1, "hello world"},
+		{"hello, world!", 1, "hello world"},
+		{"a b | c", 1, "a (b | c)"},
+		{"a b | c", 2, "(a b) | c"},
+		{"(a | b) | (c | d)", 2, "a | b | c | d"},
+		{"@title|body:( foo | bar* ) -@tags:{ a | b\\ c }", 2, "@title|body:(foo | bar*) -@tags:{a | b\\ c}"},
+		{"%%fuzz%% *fix*  ~opt", 1, "%%fuzz%% *fix* ~opt"},
+		{`@title:"exact  phrase"`, 1, `@title:"exact  phrase"`},
+		{"@price:[(10 +inf] @loc:[-0.12 51.5 10 km]", 1, "@price:[(10 +inf] @loc:[-0.12 51.5 10 km]"},
+		{"@price:[$min $max] @tags:{$tag}", 2, "@price:[$min $max] @tags:{$tag}"},
+		{"*=>[KNN 10 @vec $blob AS score]", 2, "*=>[KNN 10 @vec $blob AS score]"},
+		{"@genre:{action}=>[KNN $k @vec $blob]", 3, "(@genre:{action})=>[KNN $k @vec $blob]"},
+		{"@vec:[VECTOR_RANGE 0.5 $blob]", 4, "@vec:[VECTOR_RANGE 0.5 $blob]"},
+		{"(a b) => { $slop: 1; $inorder: true }", 2, "(a b)=>{$slop: 1; $inorder: true}"},
+		{"w'hel?o*' -(a | b)", 2, "w'hel?o*' -(a | b)"},
+		{"*", 0, "*"},
+	}
+	for _, tt := range tests {
+		parsed, err := ParseQuery(tt.raw, tt.dialect)
+		if !assert.Nil(t, err, tt.raw) {
+			continue
+		}
+		assert.Equal(t, tt.want, parsed.String(), tt.raw)
+		// the normalized query parses to the same query
+		again, err := ParseQuery(parsed.String(), tt.dialect)
+		assert.Nil(t, err, tt.raw)
+		assert.Equal(t, tt.want, again.String(), tt.raw)
+	}
+}
+
+func TestParseQuery_tree(t *testing.T) {
+	parsed, err := ParseQuery("hello @price:[10 (20]", 1)
+	assert.Nil(t, err)
+	root := parsed.Root.(*IntersectNode)
+	assert.Equal(t, &TermNode{nodeOffset: 0, Term: "hello"}, root.Children[0])
+	field := root.Children[1].(*FieldNode)
+	assert.Equal(t, 6, field.Offset())
+	assert.Equal(t, []string{"price"}, field.Fields)
+	assert.Equal(t, &NumericRangeNode{nodeOffset: 13, Min: "10", Max: "20", ExclusiveMax: true}, field.Child)
+
+	parsed, err = NewQuery("$name").SetDialect(2).Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, &ParamNode{nodeOffset: 0, Name: "name"}, parsed.Root)
+}
+
+func TestParseQuery_errors(t *testing.T) {
+	tests := []struct {
+		raw     string
+		dialect int
+		column  int
+		msg     string
+	}{
+		{"", 1, 1, "unexpected end of query"},
+		{"hello (world", 1, 7, "missing closing parenthesis"},
+		{"hello world)", 1, 12, "unexpected ')'"},
+		{"@title hello", 1, 7, "missing : after the field name"},
+		{"@title:", 1, 8, "missing expression after the field"},
+		{"@tags:{a | }", 1, 11, "empty tag"},
+		{"@tags:{a", 1, 7, "missing closing }"},
+		{"@price:[10]", 1, 8, "expected [min max], [lon lat radius unit] or [VECTOR_RANGE radius $param]"},
+		{"@price:[10 abc]", 1, 12, `invalid numeric bound "abc"`},
+		{"@loc:[1 2 3 parsecs]", 1, 13, `invalid geo unit "parsecs", expected m, km, mi or ft`},
+		{"héllo \"wörld", 1, 7, "unterminated phrase"},
+		{"%%fuzz%", 1, 7, "missing closing %%"},
+		{"$p", 1, 1, "parameters require DIALECT 2 or above"},
+		{"*=>[KNN 10 @vec $b]", 1, 2, "KNN queries require DIALECT 2 or above"},
+		{"*=>[KNN ten @vec $b]", 2, 9, `invalid number of neighbors "ten"`},
+		{"a (*=>[KNN 10 @vec $b])", 2, 4, "a KNN query must apply to the whole query"},
+		{"a=>{$weight: 2; $boost: 3}", 2, 17, "unknown attribute $boost"},
+		{"a | ", 2, 5, "unexpected end of query"},
+		{"a | | b", 1, 5, "missing operand after |"},
+		{"{a}", 1, 1, `'{' must follow a field, e.g. @field:{...`},
+		{"a", 5, 1, "unsupported dialect 5"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.raw, tt.dialect)
+		qerr, ok := err.(*QueryError)
+		if !assert.True(t, ok, tt.raw) {
+			continue
+		}
+		assert.Equal(t, tt.msg, qerr.Msg, tt.raw)
+		assert.Equal(t, tt.column, qerr.Column, tt.raw)
+	}
+	_, err := ParseQuery("hello (world", 1)
+	assert.EqualError(t, err, "redisearch: missing closing parenthesis at column 7")
+}
+
+func TestParsedQuery_Lint(t *testing.T) {
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("title")).
+		AddField(NewTextFieldOptions("body", TextFieldOptions{NoIndex: true})).
+		AddField(NewTagFieldOptions("$.tags", TagFieldOptions{As: "tags"})).
+		AddField(NewNumericField("price")).
+		AddField(NewGeoField("loc")).
+		AddField(NewVectorFieldOptions("vec", VectorFieldOptions{Algorithm: Flat}))
+
+	tests := []struct {
+		raw    string
+		issues []string
+	}{
+		{"@title:(hello | world*) @tags:{a | b} @price:[0 10] @loc:[1 2 3 km]", nil},
+		{"@unknown:foo", []string{"unknown field @unknown"}},
+		{"@body:foo", []string{"field @body is not indexed"}},
+		{"@title:{foo}", []string{"tag syntax {...} on the text field @title, use @title:term"}},
+		{"@tags:[1 2]", []string{"numeric range on the tag field @tags, use @tags:{tag}"}},
+		{"@price:foo", []string{"text query on the numeric field @price, use @price:[min max]"}},
+		{"@title|price:(foo -bar)", []string{
+			"text query on the numeric field @price, use @price:[min max]",
+			"text query on the numeric field @price, use @price:[min max]",
+		}},
+		{"@price:[1 2 3 km]", []string{"geo filter on the numeric field @price, use @price:[min max]"}},
+		{"*=>[KNN 10 @title $v]", []string{"KNN on the text field @title"}},
+		{"@title:[VECTOR_RANGE 1 $v]", []string{"VECTOR_RANGE on the text field @title, use @title:term"}},
+	}
+	for _, tt := range tests {
+		parsed, err := ParseQuery(tt.raw, 2)
+		if !assert.Nil(t, err, tt.raw) {
+			continue
+		}
+		issues := make([]string, 0)
+		for _, issue := range parsed.Lint(sc) {
+			issues = append(issues, issue.Msg)
+		}
+		if tt.issues == nil {
+			tt.issues = []string{}
+		}
+		assert.Equal(t, tt.issues, issues, tt.raw)
+	}
+
+	err := NewQuery("hello @price:foo").Validate(sc)
+	merr, ok := err.(MultiError)
+	assert.True(t, ok)
+	assert.Len(t, merr, 1)
+	assert.Equal(t, 7, merr[0].(*QueryError).Column)
+	assert.Nil(t, NewQuery("hello @price:[1 2]").Validate(sc))
+	assert.Nil(t, NewQuery("@unknown:foo").Validate(nil))
+}
+
+func TestQueryValidationHook(t *testing.T) {
+	sc := NewSchema(DefaultOptions).AddField(NewTextField("title")).AddField(NewTagField("tags"))
+	exec := &fakeExecutor{replies: []Reply{{Value: []interface{}{int64(0)}}}}
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(NewQueryValidationHook(sc, 0))
+
+	_, _, err := c.Search(NewQuery("hello (world"))
+	assert.IsType(t, &QueryError{}, err)
+	_, _, err = c.Search(NewQuery("@tags:hello"))
+	assert.IsType(t, MultiError{}, err)
+	_, _, err = c.Search(NewQuery("@tags:{$tag}"))
+	assert.IsType(t, &QueryError{}, err)
+	assert.Empty(t, exec.cmds)
+
+	_, _, err = c.Search(NewQuery("@tags:{$tag}").AddParam("tag", "a").SetDialect(2))
+	assert.Nil(t, err)
+	assert.Len(t, exec.cmds, 1)
+}
@@ -5,7 +5,6 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,13 +16,13 @@ func TestPaging_serialize(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   redis.Args
+		want   []interface{}
 	}{
-		{"default", fields{0, 10}, redis.Args{}},
-		{"0-1000", fields{0, 1000}, redis.Args{"LIMIT", 0, 1000}},
-		{"0-2", fields{0, 2}, redis.Args{"LIMIT", 0, 2}},
-		{"100-10", fields{100, 10}, redis.Args{"LIMIT", 100, 10}},
-		{"100-200", fields{100, 200}, redis.Args{"LIMIT", 100, 200}},
+		{"default", fields{0, 10}, []interface{}{}},
+		{"0-1000", fields{0, 1000}, []interface{}{"LIMIT", 0, 1000}},
+		{"0-2", fields{0, 2}, []interface{}{"LIMIT", 0, 2}},
+		{"100-10", fields{100, 10}, []interface{}{"LIMIT", 100, 10}},
+		{"100-200", fields{100, 200}, []interface{}{"LIMIT", 100, 200}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -41,20 +40,20 @@ func TestPaging_serialize(t *testing.T) {
 func Test_serializeIndexingOptions(t *testing.T) {
 	type args struct {
 		opts IndexingOptions
-		args redis.Args
+		args []interface{}
 	}
 	tests := []struct {
 		name string
 		args args
-		want redis.Args
+		want []interface{}
 	}{
-		{"default with args", args{DefaultIndexingOptions, redis.Args{"idx1", "doc1", 1.0}}, redis.Args{"idx1", "doc1", 1.0}},
-		{"default", args{DefaultIndexingOptions, redis.Args{}}, redis.Args{}},
-		{"default + language", args{IndexingOptions{Language: "portuguese"}, redis.Args{}}, redis.Args{"LANGUAGE", "portuguese"}},
-		{"replace full doc", args{IndexingOptions{Replace: true}, redis.Args{}}, redis.Args{"REPLACE"}},
-		{"replace partial", args{IndexingOptions{Replace: true, Partial: true}, redis.Args{}}, redis.Args{"REPLACE", "PARTIAL"}},
-		{"replace if", args{IndexingOptions{Replace: true, ReplaceCondition: "@timestamp < 23323234234"}, redis.Args{}}, redis.Args{"REPLACE", "IF", "@timestamp < 23323234234"}},
-		{"replace partial if", args{IndexingOptions{Replace: true, Partial: true, ReplaceCondition: "@timestamp < 23323234234"}, redis.Args{}}, redis.Args{"REPLACE", "PARTIAL", "IF", "@timestamp < 23323234234"}},
+		{"default with args", args{DefaultIndexingOptions, []interface{}{"idx1", "doc1", 1.0}}, []interface{}{"idx1", "doc1", 1.0}},
+		{"default", args{DefaultIndexingOptions, []interface{}{}}, []interface{}{}},
+		{"default + language", args{IndexingOptions{Language: "portuguese"}, []interface{}{}}, []interface{}{"LANGUAGE", "portuguese"}},
+		{"replace full doc", args{IndexingOptions{Replace: true}, []interface{}{}}, []interface{}{"REPLACE"}},
+		{"replace partial", args{IndexingOptions{Replace: true, Partial: true}, []interface{}{}}, []interface{}{"REPLACE", "PARTIAL"}},
+		{"replace if", args{IndexingOptions{Replace: true, ReplaceCondition: "@timestamp < 23323234234"}, []interface{}{}}, []interface{}{"REPLACE", "IF", "@timestamp < 23323234234"}},
+		{"replace partial if", args{IndexingOptions{Replace: true, Partial: true, ReplaceCondition: "@timestamp < 23323234234"}, []interface{}{}}, []interface{}{"REPLACE", "PARTIAL", "IF", "@timestamp < 23323234234"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -85,37 +84,37 @@ func TestQuery_serialize(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   redis.Args
+		want   []interface{}
 	}{
-		{"default", fields{Raw: ""}, redis.Args{"", "LIMIT", 0, 0}},
-		{"Raw", fields{Raw: raw}, redis.Args{raw, "LIMIT", 0, 0}},
-		{"QueryVerbatim", fields{Raw: raw, Flags: QueryVerbatim}, redis.Args{raw, "LIMIT", 0, 0, "VERBATIM"}},
-		{"QueryNoContent", fields{Raw: raw, Flags: QueryNoContent}, redis.Args{raw, "LIMIT", 0, 0, "NOCONTENT"}},
-		{"QueryInOrder", fields{Raw: raw, Flags: QueryInOrder}, redis.Args{raw, "LIMIT", 0, 0, "INORDER"}},
-		{"QueryWithPayloads", fields{Raw: raw, Flags: QueryWithPayloads}, redis.Args{raw, "LIMIT", 0, 0, "WITHPAYLOADS"}},
-		{"QueryWithScores", fields{Raw: raw, Flags: QueryWithScores}, redis.Args{raw, "LIMIT", 0, 0, "WITHSCORES"}},
-		{"QueryWithStopWords", fields{Raw: raw, Flags: QueryWithStopWords}, redis.Args{raw, "LIMIT", 0, 0, "NOSTOPWORDS"}},
-		{"InKeys", fields{Raw: raw, InKeys: []string{"test_key"}}, redis.Args{raw, "LIMIT", 0, 0, "INKEYS", 1, "test_key"}},
-		{"InFields", fields{Raw: raw, InFields: []string{"test_key"}}, redis.Args{raw, "LIMIT", 0, 0, "INFIELDS", 1, "test_key"}},
-		{"ReturnFields", fields{Raw: raw, ReturnFields: []string{"test_field"}}, redis.Args{raw, "LIMIT", 0, 0, "RETURN", 1, "test_field"}},
-		{"Language", fields{Raw: raw, Language: "chinese"}, redis.Args{raw, "LIMIT", 0, 0, "LANGUAGE", "chinese"}},
-		{"Expander", fields{Raw: raw, Expander: "test_expander"}, redis.Args{raw, "LIMIT", 0, 0, "EXPANDER", "test_expander"}},
-		{"Scorer", fields{Raw: raw, Scorer: "test_scorer"}, redis.Args{raw, "LIMIT", 0, 0, "SCORER", "test_scorer"}},
+		{"default", fields{Raw: ""}, []interface{}{"", "LIMIT", 0, 0}},
+		{"Raw", fields{Raw: raw}, []interface{}{raw, "LIMIT", 0, 0}},
+		{"QueryVerbatim", fields{Raw: raw, Flags: QueryVerbatim}, []interface{}{raw, "LIMIT", 0, 0, "VERBATIM"}},
+		{"QueryNoContent", fields{Raw: raw, Flags: QueryNoContent}, []interface{}{raw, "LIMIT", 0, 0, "NOCONTENT"}},
+		{"QueryInOrder", fields{Raw: raw, Flags: QueryInOrder}, []interface{}{raw, "LIMIT", 0, 0, "INORDER"}},
+		{"QueryWithPayloads", fields{Raw: raw, Flags: QueryWithPayloads}, []interface{}{raw, "LIMIT", 0, 0, "WITHPAYLOADS"}},
+		{"QueryWithScores", fields{Raw: raw, Flags: QueryWithScores}, []interface{}{raw, "LIMIT", 0, 0, "WITHSCORES"}},
+		{"QueryWithStopWords", fields{Raw: raw, Flags: QueryWithStopWords}, []interface{}{raw, "LIMIT", 0, 0, "NOSTOPWORDS"}},
+		{"InKeys", fields{Raw: raw, InKeys: []string{"test_key"}}, []interface{}{raw, "LIMIT", 0, 0, "INKEYS", 1, "test_key"}},
+		{"InFields", fields{Raw: raw, InFields: []string{"test_key"}}, []interface{}{raw, "LIMIT", 0, 0, "INFIELDS", 1, "test_key"}},
+		{"ReturnFields", fields{Raw: raw, ReturnFields: []string{"test_field"}}, []interface{}{raw, "LIMIT", 0, 0, "RETURN", 1, "test_field"}},
+		{"Language", fields{Raw: raw, Language: "chinese"}, []interface{}{raw, "LIMIT", 0, 0, "LANGUAGE", "chinese"}},
+		{"Expander", fields{Raw: raw, Expander: "test_expander"}, []interface{}{raw, "LIMIT", 0, 0, "EXPANDER", "test_expander"}},
+		{"Scorer", fields{Raw: raw, Scorer: "test_scorer"}, []interface{}{raw, "LIMIT", 0, 0, "SCORER", "test_scorer"}},
 		{"SortBy", fields{Raw: raw, SortBy: &SortingKey{
 			Field:     "test_field",
-			Ascending: true}}, redis.Args{raw, "LIMIT", 0, 0, "SORTBY", "test_field", "ASC"}},
+			Ascending: true}}, []interface{}{raw, "LIMIT", 0, 0, "SORTBY", "test_field", "ASC"}},
 		{"HighlightOpts", fields{Raw: raw, HighlightOpts: &HighlightOptions{
 			Fields: []string{"test_field"},
 			Tags:   [2]string{"<tag>", "</tag>"},
-		}}, redis.Args{raw, "LIMIT", 0, 0, "HIGHLIGHT", "FIELDS", 1, "test_field", "TAGS", "<tag>", "</tag>"}},
+		}}, []interface{}{raw, "LIMIT", 0, 0, "HIGHLIGHT", "FIELDS", 1, "test_field", "TAGS", "<tag>", "</tag>"}},
 		{"SummarizeOpts", fields{Raw: raw, SummarizeOpts: &SummaryOptions{
 			Fields:       []string{"test_field"},
 			FragmentLen:  20,
 			NumFragments: 3,
 			Separator:    "...",
-		}}, redis.Args{raw, "LIMIT", 0, 0, "SUMMARIZE", "FIELDS", 1, "test_field", "LEN", 20, "FRAGS", 3, "SEPARATOR", "..."}},
-		{"Params", fields{Raw: raw, Params: map[string]interface{}{"min": 1}}, redis.Args{raw, "LIMIT", 0, 0, "PARAMS", 2, "min", 1}},
-		{"Dialect", fields{Raw: raw, Dialect: 2}, redis.Args{raw, "LIMIT", 0, 0, "DIALECT", 2}},
+		}}, []interface{}{raw, "LIMIT", 0, 0, "SUMMARIZE", "FIELDS", 1, "test_field", "LEN", 20, "FRAGS", 3, "SEPARATOR", "..."}},
+		{"Params", fields{Raw: raw, Params: map[string]interface{}{"min": 1}}, []interface{}{raw, "LIMIT", 0, 0, "PARAMS", 2, "min", 1}},
+		{"Dialect", fields{Raw: raw, Dialect: 2}, []interface{}{raw, "LIMIT", 0, 0, "DIALECT", 2}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -145,17 +144,17 @@ func Test_appendNumArgs(t *testing.T) {
 	type args struct {
 		num     float64
 		exclude bool
-		args    redis.Args
+		args    []interface{}
 	}
 	tests := []struct {
 		name string
 		args args
-		want redis.Args
+		want []interface{}
 	}{
-		{"1 arg", args{1.0, false, redis.Args{}}, redis.Args{1.0}},
-		{"2.54 excluded arg", args{2.54, true, redis.Args{}}, redis.Args{"(2.54"}},
-		{"+inf", args{math.Inf(1), false, redis.Args{}}, redis.Args{"+inf"}},
-		{"+inf", args{math.Inf(-1), false, redis.Args{}}, redis.Args{"-inf"}},
+		{"1 arg", args{1.0, false, []interface{}{}}, []interface{}{1.0}},
+		{"2.54 excluded arg", args{2.54, true, []interface{}{}}, []interface{}{"(2.54"}},
+		{"+inf", args{math.Inf(1), false, []interface{}{}}, []interface{}{"+inf"}},
+		{"+inf", args{math.Inf(-1), false, []interface{}{}}, []interface{}{"-inf"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -4,19 +4,36 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchtest"
 	"github.com/gomodule/redigo/redis"
 
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	memoryServerOnce sync.Once
+	memoryServer     *redisearchtest.Server
+)
+
+// getTestConnectionDetails returns the server to test against. REDISEARCH_TEST_HOST=memory starts an
+// in-memory server shared by the tests.
 func getTestConnectionDetails() (string, string) {
 	value, exists := os.LookupEnv("REDISEARCH_TEST_HOST")
 	host := "localhost:6379"
 	password := ""
 	valuePassword, existsPassword := os.LookupEnv("REDISEARCH_TEST_PASSWORD")
+	if exists && value == "memory" {
+		memoryServerOnce.Do(func() {
+			memoryServer = redisearchtest.NewServer()
+		})
+		return memoryServer.Addr, ""
+	}
 	if exists && value != "" {
 		host = value
 	}
@@ -26,6 +43,13 @@ func getTestConnectionDetails() (string, string) {
 	return host, password
 }
 
+// skipInMemory skips the tests using features that the in-memory server does not implement
+func skipInMemory(t *testing.T, feature string) {
+	if os.Getenv("REDISEARCH_TEST_HOST") == "memory" {
+		t.Skipf("%s is not supported by the in-memory server", feature)
+	}
+}
+
 func createClient(indexName string) *Client {
 	host, password := getTestConnectionDetails()
 	if password != "" {
@@ -44,6 +68,50 @@ func createClient(indexName string) *Client {
 	}
 }
 
+// createGoldenClient creates a test client whose commands are recorded to, or replayed from, the golden file of the
+// test in testdata/golden depending on REDISEARCH_GOLDEN:
+//   - record runs the setup and the test against the test server, and saves the commands of the test
+//   - replay replies from the golden file without a server, the commands differing from it fail
+//   - diff replies from the golden file and reports the commands whose serialization changed
+//
+// Without REDISEARCH_GOLDEN the setup and the test run against the test server. Outside of replay and diff,
+// the test is skipped with the in-memory server, which does not support the given feature.
+func createGoldenClient(t *testing.T, indexName string, setup func(), feature string) *Client {
+	path := filepath.Join("testdata", "golden", strings.ReplaceAll(t.Name(), "/", "_")+".jsonl")
+	mode := os.Getenv("REDISEARCH_GOLDEN")
+	switch mode {
+	case "replay", "diff":
+		rec, err := LoadRecording(path)
+		if err != nil {
+			t.Fatalf("cannot load the golden file, run the test with REDISEARCH_GOLDEN=record: %v", err)
+		}
+		replayMode := ReplayStrict
+		if mode == "diff" {
+			replayMode = ReplayDiff
+		}
+		pool := NewReplayPool(rec, replayMode)
+		t.Cleanup(func() {
+			if diff := pool.Diff(); diff != "" {
+				t.Errorf("the commands differ from the golden file %s:\n%s", path, diff)
+			}
+		})
+		return &Client{pool: pool, name: indexName}
+	}
+	skipInMemory(t, feature)
+	setup()
+	c := createClient(indexName)
+	if mode == "record" {
+		pool := NewRecordingPool(c.pool)
+		c.pool = pool
+		t.Cleanup(func() {
+			if !t.Failed() {
+				assert.Nil(t, pool.Recording().Save(path))
+			}
+		})
+	}
+	return c
+}
+
 func createAutocompleter(dictName string) *Autocompleter {
 	host, password := getTestConnectionDetails()
 	if password != "" {
@@ -62,6 +130,28 @@ func createAutocompleter(dictName string) *Autocompleter {
 	}
 }
 
+func TestClient_IndexOptions_errors(t *testing.T) {
+	c := createClient("testung-errors")
+	c.Drop()
+	assert.Nil(t, c.CreateIndex(NewSchema(DefaultOptions).AddField(NewTextField("foo"))))
+	defer teardown(c)
+
+	dup := NewDocument("TestClient-errors-dup", 1).Set("foo", "hello")
+	assert.Nil(t, c.Index(dup))
+	err := c.Index(
+		NewDocument("TestClient-errors-1", 1).Set("foo", "hello"),
+		NewDocument("TestClient-errors-2", 1).Set("foo", "hello"),
+		dup,
+	)
+	merr, ok := err.(MultiError)
+	assert.True(t, ok, err)
+	assert.Len(t, merr, 3)
+	// the errors are aligned with the documents
+	assert.Nil(t, merr[0])
+	assert.Nil(t, merr[1])
+	assert.NotNil(t, merr[2])
+}
+
 func TestClient(t *testing.T) {
 
 	c := createClient("testung")
@@ -265,6 +355,7 @@ func TestHighlight(t *testing.T) {
 }
 
 func TestSummarize(t *testing.T) {
+	skipInMemory(t, "SUMMARIZE with stemming")
 	c := createClient("testung")
 
 	sc := NewSchema(DefaultOptions).
@@ -359,6 +450,7 @@ func TestTags(t *testing.T) {
 }
 
 func TestSpellCheck(t *testing.T) {
+	skipInMemory(t, "FT.SPELLCHECK")
 	c := createClient("testung")
 	countries := []string{"Spain", "Israel", "Portugal", "France", "England", "Angola"}
 	sc := NewSchema(DefaultOptions).
@@ -449,6 +541,7 @@ func TestFilter(t *testing.T) {
 }
 
 func TestReturnFields(t *testing.T) {
+	skipInMemory(t, "ON JSON")
 	c := createClient("TestReturnFields")
 	version, _ := c.getRediSearchVersion()
 	if version < 20200 {
@@ -562,6 +655,7 @@ func TestParams(t *testing.T) {
 }
 
 func TestVectorField(t *testing.T) {
+	skipInMemory(t, "KNN")
 	c := createClient("TestVectorField")
 	version, _ := c.getRediSearchVersion()
 	if version < 20430 {
@@ -0,0 +1,227 @@
+//go:build ignore
+// +build ignore
+
+// gen generates the mocks of the interfaces declared in ../interfaces.go to mocks.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// param is a parameter or a result of a method
+type param struct {
+	Name  string
+	Field string
+	Type  string
+}
+
+type method struct {
+	Name     string
+	Params   []param
+	Results  []param
+	Variadic bool
+}
+
+type mock struct {
+	Name    string
+	Methods []method
+}
+
+func main() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "../interfaces.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mocks := make([]mock, 0)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			m := mock{Name: ts.Name.Name}
+			for _, field := range it.Methods.List {
+				ft := field.Type.(*ast.FuncType)
+				meth := method{Name: field.Names[0].Name}
+				meth.Params, meth.Variadic = params(ft.Params, "p")
+				meth.Results, _ = params(ft.Results, "r")
+				m.Methods = append(m.Methods, meth)
+			}
+			mocks = append(mocks, m)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, mocks); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, buf.String())
+	}
+	if err := os.WriteFile("mocks.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// params returns the parameters of a field list, named with the prefix and their position when they are unnamed
+func params(fields *ast.FieldList, prefix string) ([]param, bool) {
+	ret := make([]param, 0)
+	variadic := false
+	if fields == nil {
+		return ret, false
+	}
+	for _, field := range fields.List {
+		typ := field.Type
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			variadic = true
+			typ = &ast.ArrayType{Elt: ellipsis.Elt}
+		}
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, token.NewFileSet(), qualify(typ)); err != nil {
+			log.Fatal(err)
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("%s%d", prefix, len(ret)))}
+		}
+		for _, name := range names {
+			runes := []rune(name.Name)
+			runes[0] = unicode.ToUpper(runes[0])
+			ret = append(ret, param{Name: name.Name, Field: string(runes), Type: buf.String()})
+		}
+	}
+	return ret, variadic
+}
+
+// qualify prefixes the exported identifiers of a type with the redisearch package
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("redisearch"), Sel: ast.NewIdent(e.Name)}
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	}
+	return expr
+}
+
+var tmpl = template.Must(template.New("mocks").Funcs(template.FuncMap{
+	"lower": func(s string) string { return strings.ToLower(s[:1]) + s[1:] },
+	"last":  func(ii int, params []param) bool { return ii == len(params)-1 },
+}).Parse(`// Code generated by gen.go; DO NOT EDIT.
+
+package redisearchmock
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+{{range $mock := .}}
+// {{.Name}} is a mock of redisearch.{{.Name}}. Its zero value is ready to use and replies with zero values.
+type {{.Name}} struct {
+	mu sync.Mutex
+{{- range .Methods}}
+	// {{.Name}}Func replies to {{.Name}}, when set
+	{{.Name}}Func func({{template "types" .}}) ({{range .Results}}{{.Type}}, {{end}})
+	{{lower .Name}}Calls []{{.Name}}Call
+{{- end}}
+}
+
+var _ redisearch.{{.Name}} = (*{{.Name}})(nil)
+{{range .Methods}}
+// {{.Name}}Call is a call of {{$mock.Name}}.{{.Name}}
+type {{.Name}}Call struct {
+{{- range .Params}}
+	{{.Field}} {{.Type}}
+{{- end}}
+}
+
+// {{.Name}} records the call and replies with {{.Name}}Func
+func (m *{{$mock.Name}}) {{.Name}}({{template "params" .}}) ({{range .Results}}{{.Name}} {{.Type}}, {{end}}) {
+	m.mu.Lock()
+	m.{{lower .Name}}Calls = append(m.{{lower .Name}}Calls, {{.Name}}Call{ {{- range .Params}}{{.Field}}: {{.Name}}, {{end -}} })
+	fn := m.{{.Name}}Func
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn({{template "args" .}})
+}
+
+// {{.Name}}Returns makes {{.Name}} reply with the given values
+func (m *{{$mock.Name}}) {{.Name}}Returns({{range .Results}}{{.Name}} {{.Type}}, {{end}}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.{{.Name}}Func = func({{template "types" .}}) ({{range .Results}}{{.Type}}, {{end}}) {
+		return {{range $ii, $r := .Results}}{{if $ii}}, {{end}}{{$r.Name}}{{end}}
+	}
+}
+
+// {{.Name}}Calls returns the calls of {{.Name}}
+func (m *{{$mock.Name}}) {{.Name}}Calls() []{{.Name}}Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]{{.Name}}Call(nil), m.{{lower .Name}}Calls...)
+}
+
+// Assert{{.Name}}Called checks that {{.Name}} was called with the given arguments
+func (m *{{$mock.Name}}) Assert{{.Name}}Called(t TestingT, {{range .Params}}{{.Name}} {{.Type}}, {{end}}) bool {
+	t.Helper()
+	want := {{.Name}}Call{ {{- range .Params}}{{.Field}}: {{.Name}}, {{end -}} }
+	return m.Assert{{.Name}}CalledMatching(t, func(call {{.Name}}Call) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// Assert{{.Name}}CalledMatching checks that {{.Name}} was called with arguments matching the given function
+func (m *{{$mock.Name}}) Assert{{.Name}}CalledMatching(t TestingT, match func(call {{.Name}}Call) bool) bool {
+	t.Helper()
+	calls := m.{{.Name}}Calls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of {{$mock.Name}}.{{.Name}}, got %s", describeCalls(calls))
+	return false
+}
+
+// Assert{{.Name}}NotCalled checks that {{.Name}} was not called
+func (m *{{$mock.Name}}) Assert{{.Name}}NotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.{{.Name}}Calls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of {{$mock.Name}}.{{.Name}}: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+{{end}}{{end}}
+{{- define "params"}}{{$v := .Variadic}}{{range $ii, $p := .Params}}{{$p.Name}} {{if and $v (last $ii $.Params)}}...{{slice $p.Type 2}}{{else}}{{$p.Type}}{{end}}, {{end}}{{end}}
+{{- define "types"}}{{$v := .Variadic}}{{range $ii, $p := .Params}}{{if and $v (last $ii $.Params)}}...{{slice $p.Type 2}}{{else}}{{$p.Type}}{{end}}, {{end}}{{end}}
+{{- define "args"}}{{$v := .Variadic}}{{range $ii, $p := .Params}}{{$p.Name}}{{if and $v (last $ii $.Params)}}...{{end}}, {{end}}{{end}}
+`))
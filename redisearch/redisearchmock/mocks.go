@@ -0,0 +1,1680 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package redisearchmock
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// Searcher is a mock of redisearch.Searcher. Its zero value is ready to use and replies with zero values.
+type Searcher struct {
+	mu sync.Mutex
+	// SearchFunc replies to Search, when set
+	SearchFunc  func(*redisearch.Query) ([]redisearch.Document, int, error)
+	searchCalls []SearchCall
+}
+
+var _ redisearch.Searcher = (*Searcher)(nil)
+
+// SearchCall is a call of Searcher.Search
+type SearchCall struct {
+	Q *redisearch.Query
+}
+
+// Search records the call and replies with SearchFunc
+func (m *Searcher) Search(q *redisearch.Query) (docs []redisearch.Document, total int, err error) {
+	m.mu.Lock()
+	m.searchCalls = append(m.searchCalls, SearchCall{Q: q})
+	fn := m.SearchFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(q)
+}
+
+// SearchReturns makes Search reply with the given values
+func (m *Searcher) SearchReturns(docs []redisearch.Document, total int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SearchFunc = func(*redisearch.Query) ([]redisearch.Document, int, error) {
+		return docs, total, err
+	}
+}
+
+// SearchCalls returns the calls of Search
+func (m *Searcher) SearchCalls() []SearchCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SearchCall(nil), m.searchCalls...)
+}
+
+// AssertSearchCalled checks that Search was called with the given arguments
+func (m *Searcher) AssertSearchCalled(t TestingT, q *redisearch.Query) bool {
+	t.Helper()
+	want := SearchCall{Q: q}
+	return m.AssertSearchCalledMatching(t, func(call SearchCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertSearchCalledMatching checks that Search was called with arguments matching the given function
+func (m *Searcher) AssertSearchCalledMatching(t TestingT, match func(call SearchCall) bool) bool {
+	t.Helper()
+	calls := m.SearchCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Searcher.Search, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertSearchNotCalled checks that Search was not called
+func (m *Searcher) AssertSearchNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.SearchCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Searcher.Search: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// Indexer is a mock of redisearch.Indexer. Its zero value is ready to use and replies with zero values.
+type Indexer struct {
+	mu sync.Mutex
+	// IndexFunc replies to Index, when set
+	IndexFunc  func(...redisearch.Document) error
+	indexCalls []IndexCall
+	// IndexOptionsFunc replies to IndexOptions, when set
+	IndexOptionsFunc  func(redisearch.IndexingOptions, ...redisearch.Document) error
+	indexOptionsCalls []IndexOptionsCall
+	// GetFunc replies to Get, when set
+	GetFunc  func(string) (*redisearch.Document, error)
+	getCalls []GetCall
+	// DeleteDocumentFunc replies to DeleteDocument, when set
+	DeleteDocumentFunc  func(string) error
+	deleteDocumentCalls []DeleteDocumentCall
+}
+
+var _ redisearch.Indexer = (*Indexer)(nil)
+
+// IndexCall is a call of Indexer.Index
+type IndexCall struct {
+	Docs []redisearch.Document
+}
+
+// Index records the call and replies with IndexFunc
+func (m *Indexer) Index(docs ...redisearch.Document) (r0 error) {
+	m.mu.Lock()
+	m.indexCalls = append(m.indexCalls, IndexCall{Docs: docs})
+	fn := m.IndexFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(docs...)
+}
+
+// IndexReturns makes Index reply with the given values
+func (m *Indexer) IndexReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.IndexFunc = func(...redisearch.Document) error {
+		return r0
+	}
+}
+
+// IndexCalls returns the calls of Index
+func (m *Indexer) IndexCalls() []IndexCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]IndexCall(nil), m.indexCalls...)
+}
+
+// AssertIndexCalled checks that Index was called with the given arguments
+func (m *Indexer) AssertIndexCalled(t TestingT, docs []redisearch.Document) bool {
+	t.Helper()
+	want := IndexCall{Docs: docs}
+	return m.AssertIndexCalledMatching(t, func(call IndexCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertIndexCalledMatching checks that Index was called with arguments matching the given function
+func (m *Indexer) AssertIndexCalledMatching(t TestingT, match func(call IndexCall) bool) bool {
+	t.Helper()
+	calls := m.IndexCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Indexer.Index, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertIndexNotCalled checks that Index was not called
+func (m *Indexer) AssertIndexNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.IndexCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Indexer.Index: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// IndexOptionsCall is a call of Indexer.IndexOptions
+type IndexOptionsCall struct {
+	Opts redisearch.IndexingOptions
+	Docs []redisearch.Document
+}
+
+// IndexOptions records the call and replies with IndexOptionsFunc
+func (m *Indexer) IndexOptions(opts redisearch.IndexingOptions, docs ...redisearch.Document) (r0 error) {
+	m.mu.Lock()
+	m.indexOptionsCalls = append(m.indexOptionsCalls, IndexOptionsCall{Opts: opts, Docs: docs})
+	fn := m.IndexOptionsFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(opts, docs...)
+}
+
+// IndexOptionsReturns makes IndexOptions reply with the given values
+func (m *Indexer) IndexOptionsReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.IndexOptionsFunc = func(redisearch.IndexingOptions, ...redisearch.Document) error {
+		return r0
+	}
+}
+
+// IndexOptionsCalls returns the calls of IndexOptions
+func (m *Indexer) IndexOptionsCalls() []IndexOptionsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]IndexOptionsCall(nil), m.indexOptionsCalls...)
+}
+
+// AssertIndexOptionsCalled checks that IndexOptions was called with the given arguments
+func (m *Indexer) AssertIndexOptionsCalled(t TestingT, opts redisearch.IndexingOptions, docs []redisearch.Document) bool {
+	t.Helper()
+	want := IndexOptionsCall{Opts: opts, Docs: docs}
+	return m.AssertIndexOptionsCalledMatching(t, func(call IndexOptionsCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertIndexOptionsCalledMatching checks that IndexOptions was called with arguments matching the given function
+func (m *Indexer) AssertIndexOptionsCalledMatching(t TestingT, match func(call IndexOptionsCall) bool) bool {
+	t.Helper()
+	calls := m.IndexOptionsCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Indexer.IndexOptions, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertIndexOptionsNotCalled checks that IndexOptions was not called
+func (m *Indexer) AssertIndexOptionsNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.IndexOptionsCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Indexer.IndexOptions: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// GetCall is a call of Indexer.Get
+type GetCall struct {
+	DocId string
+}
+
+// Get records the call and replies with GetFunc
+func (m *Indexer) Get(docId string) (doc *redisearch.Document, err error) {
+	m.mu.Lock()
+	m.getCalls = append(m.getCalls, GetCall{DocId: docId})
+	fn := m.GetFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(docId)
+}
+
+// GetReturns makes Get reply with the given values
+func (m *Indexer) GetReturns(doc *redisearch.Document, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetFunc = func(string) (*redisearch.Document, error) {
+		return doc, err
+	}
+}
+
+// GetCalls returns the calls of Get
+func (m *Indexer) GetCalls() []GetCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]GetCall(nil), m.getCalls...)
+}
+
+// AssertGetCalled checks that Get was called with the given arguments
+func (m *Indexer) AssertGetCalled(t TestingT, docId string) bool {
+	t.Helper()
+	want := GetCall{DocId: docId}
+	return m.AssertGetCalledMatching(t, func(call GetCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertGetCalledMatching checks that Get was called with arguments matching the given function
+func (m *Indexer) AssertGetCalledMatching(t TestingT, match func(call GetCall) bool) bool {
+	t.Helper()
+	calls := m.GetCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Indexer.Get, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertGetNotCalled checks that Get was not called
+func (m *Indexer) AssertGetNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.GetCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Indexer.Get: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// DeleteDocumentCall is a call of Indexer.DeleteDocument
+type DeleteDocumentCall struct {
+	DocId string
+}
+
+// DeleteDocument records the call and replies with DeleteDocumentFunc
+func (m *Indexer) DeleteDocument(docId string) (r0 error) {
+	m.mu.Lock()
+	m.deleteDocumentCalls = append(m.deleteDocumentCalls, DeleteDocumentCall{DocId: docId})
+	fn := m.DeleteDocumentFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(docId)
+}
+
+// DeleteDocumentReturns makes DeleteDocument reply with the given values
+func (m *Indexer) DeleteDocumentReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DeleteDocumentFunc = func(string) error {
+		return r0
+	}
+}
+
+// DeleteDocumentCalls returns the calls of DeleteDocument
+func (m *Indexer) DeleteDocumentCalls() []DeleteDocumentCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DeleteDocumentCall(nil), m.deleteDocumentCalls...)
+}
+
+// AssertDeleteDocumentCalled checks that DeleteDocument was called with the given arguments
+func (m *Indexer) AssertDeleteDocumentCalled(t TestingT, docId string) bool {
+	t.Helper()
+	want := DeleteDocumentCall{DocId: docId}
+	return m.AssertDeleteDocumentCalledMatching(t, func(call DeleteDocumentCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertDeleteDocumentCalledMatching checks that DeleteDocument was called with arguments matching the given function
+func (m *Indexer) AssertDeleteDocumentCalledMatching(t TestingT, match func(call DeleteDocumentCall) bool) bool {
+	t.Helper()
+	calls := m.DeleteDocumentCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Indexer.DeleteDocument, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertDeleteDocumentNotCalled checks that DeleteDocument was not called
+func (m *Indexer) AssertDeleteDocumentNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.DeleteDocumentCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Indexer.DeleteDocument: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// Aggregator is a mock of redisearch.Aggregator. Its zero value is ready to use and replies with zero values.
+type Aggregator struct {
+	mu sync.Mutex
+	// AggregateFunc replies to Aggregate, when set
+	AggregateFunc  func(*redisearch.AggregateQuery) ([][]string, int, error)
+	aggregateCalls []AggregateCall
+	// AggregateQueryFunc replies to AggregateQuery, when set
+	AggregateQueryFunc  func(*redisearch.AggregateQuery) (int, []map[string]interface{}, error)
+	aggregateQueryCalls []AggregateQueryCall
+}
+
+var _ redisearch.Aggregator = (*Aggregator)(nil)
+
+// AggregateCall is a call of Aggregator.Aggregate
+type AggregateCall struct {
+	Q *redisearch.AggregateQuery
+}
+
+// Aggregate records the call and replies with AggregateFunc
+func (m *Aggregator) Aggregate(q *redisearch.AggregateQuery) (aggregateReply [][]string, total int, err error) {
+	m.mu.Lock()
+	m.aggregateCalls = append(m.aggregateCalls, AggregateCall{Q: q})
+	fn := m.AggregateFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(q)
+}
+
+// AggregateReturns makes Aggregate reply with the given values
+func (m *Aggregator) AggregateReturns(aggregateReply [][]string, total int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AggregateFunc = func(*redisearch.AggregateQuery) ([][]string, int, error) {
+		return aggregateReply, total, err
+	}
+}
+
+// AggregateCalls returns the calls of Aggregate
+func (m *Aggregator) AggregateCalls() []AggregateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AggregateCall(nil), m.aggregateCalls...)
+}
+
+// AssertAggregateCalled checks that Aggregate was called with the given arguments
+func (m *Aggregator) AssertAggregateCalled(t TestingT, q *redisearch.AggregateQuery) bool {
+	t.Helper()
+	want := AggregateCall{Q: q}
+	return m.AssertAggregateCalledMatching(t, func(call AggregateCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAggregateCalledMatching checks that Aggregate was called with arguments matching the given function
+func (m *Aggregator) AssertAggregateCalledMatching(t TestingT, match func(call AggregateCall) bool) bool {
+	t.Helper()
+	calls := m.AggregateCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Aggregator.Aggregate, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAggregateNotCalled checks that Aggregate was not called
+func (m *Aggregator) AssertAggregateNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AggregateCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Aggregator.Aggregate: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AggregateQueryCall is a call of Aggregator.AggregateQuery
+type AggregateQueryCall struct {
+	Q *redisearch.AggregateQuery
+}
+
+// AggregateQuery records the call and replies with AggregateQueryFunc
+func (m *Aggregator) AggregateQuery(q *redisearch.AggregateQuery) (total int, aggregateReply []map[string]interface{}, err error) {
+	m.mu.Lock()
+	m.aggregateQueryCalls = append(m.aggregateQueryCalls, AggregateQueryCall{Q: q})
+	fn := m.AggregateQueryFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(q)
+}
+
+// AggregateQueryReturns makes AggregateQuery reply with the given values
+func (m *Aggregator) AggregateQueryReturns(total int, aggregateReply []map[string]interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AggregateQueryFunc = func(*redisearch.AggregateQuery) (int, []map[string]interface{}, error) {
+		return total, aggregateReply, err
+	}
+}
+
+// AggregateQueryCalls returns the calls of AggregateQuery
+func (m *Aggregator) AggregateQueryCalls() []AggregateQueryCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AggregateQueryCall(nil), m.aggregateQueryCalls...)
+}
+
+// AssertAggregateQueryCalled checks that AggregateQuery was called with the given arguments
+func (m *Aggregator) AssertAggregateQueryCalled(t TestingT, q *redisearch.AggregateQuery) bool {
+	t.Helper()
+	want := AggregateQueryCall{Q: q}
+	return m.AssertAggregateQueryCalledMatching(t, func(call AggregateQueryCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAggregateQueryCalledMatching checks that AggregateQuery was called with arguments matching the given function
+func (m *Aggregator) AssertAggregateQueryCalledMatching(t TestingT, match func(call AggregateQueryCall) bool) bool {
+	t.Helper()
+	calls := m.AggregateQueryCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Aggregator.AggregateQuery, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAggregateQueryNotCalled checks that AggregateQuery was not called
+func (m *Aggregator) AssertAggregateQueryNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AggregateQueryCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Aggregator.AggregateQuery: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// IndexAdmin is a mock of redisearch.IndexAdmin. Its zero value is ready to use and replies with zero values.
+type IndexAdmin struct {
+	mu sync.Mutex
+	// CreateIndexFunc replies to CreateIndex, when set
+	CreateIndexFunc  func(*redisearch.Schema) error
+	createIndexCalls []CreateIndexCall
+	// CreateIndexWithIndexDefinitionFunc replies to CreateIndexWithIndexDefinition, when set
+	CreateIndexWithIndexDefinitionFunc  func(*redisearch.Schema, *redisearch.IndexDefinition) error
+	createIndexWithIndexDefinitionCalls []CreateIndexWithIndexDefinitionCall
+	// AddFieldFunc replies to AddField, when set
+	AddFieldFunc  func(redisearch.Field) error
+	addFieldCalls []AddFieldCall
+	// InfoFunc replies to Info, when set
+	InfoFunc  func() (*redisearch.IndexInfo, error)
+	infoCalls []InfoCall
+	// DropIndexFunc replies to DropIndex, when set
+	DropIndexFunc  func(bool) error
+	dropIndexCalls []DropIndexCall
+	// ListFunc replies to List, when set
+	ListFunc  func() ([]string, error)
+	listCalls []ListCall
+	// AliasAddFunc replies to AliasAdd, when set
+	AliasAddFunc  func(string) error
+	aliasAddCalls []AliasAddCall
+	// AliasDelFunc replies to AliasDel, when set
+	AliasDelFunc  func(string) error
+	aliasDelCalls []AliasDelCall
+	// AliasUpdateFunc replies to AliasUpdate, when set
+	AliasUpdateFunc  func(string) error
+	aliasUpdateCalls []AliasUpdateCall
+	// SynUpdateFunc replies to SynUpdate, when set
+	SynUpdateFunc  func(string, int64, []string) (string, error)
+	synUpdateCalls []SynUpdateCall
+	// SynDumpFunc replies to SynDump, when set
+	SynDumpFunc  func(string) (map[string][]int64, error)
+	synDumpCalls []SynDumpCall
+}
+
+var _ redisearch.IndexAdmin = (*IndexAdmin)(nil)
+
+// CreateIndexCall is a call of IndexAdmin.CreateIndex
+type CreateIndexCall struct {
+	Schema *redisearch.Schema
+}
+
+// CreateIndex records the call and replies with CreateIndexFunc
+func (m *IndexAdmin) CreateIndex(schema *redisearch.Schema) (r0 error) {
+	m.mu.Lock()
+	m.createIndexCalls = append(m.createIndexCalls, CreateIndexCall{Schema: schema})
+	fn := m.CreateIndexFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(schema)
+}
+
+// CreateIndexReturns makes CreateIndex reply with the given values
+func (m *IndexAdmin) CreateIndexReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CreateIndexFunc = func(*redisearch.Schema) error {
+		return r0
+	}
+}
+
+// CreateIndexCalls returns the calls of CreateIndex
+func (m *IndexAdmin) CreateIndexCalls() []CreateIndexCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CreateIndexCall(nil), m.createIndexCalls...)
+}
+
+// AssertCreateIndexCalled checks that CreateIndex was called with the given arguments
+func (m *IndexAdmin) AssertCreateIndexCalled(t TestingT, schema *redisearch.Schema) bool {
+	t.Helper()
+	want := CreateIndexCall{Schema: schema}
+	return m.AssertCreateIndexCalledMatching(t, func(call CreateIndexCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertCreateIndexCalledMatching checks that CreateIndex was called with arguments matching the given function
+func (m *IndexAdmin) AssertCreateIndexCalledMatching(t TestingT, match func(call CreateIndexCall) bool) bool {
+	t.Helper()
+	calls := m.CreateIndexCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.CreateIndex, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertCreateIndexNotCalled checks that CreateIndex was not called
+func (m *IndexAdmin) AssertCreateIndexNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.CreateIndexCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.CreateIndex: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// CreateIndexWithIndexDefinitionCall is a call of IndexAdmin.CreateIndexWithIndexDefinition
+type CreateIndexWithIndexDefinitionCall struct {
+	Schema     *redisearch.Schema
+	Definition *redisearch.IndexDefinition
+}
+
+// CreateIndexWithIndexDefinition records the call and replies with CreateIndexWithIndexDefinitionFunc
+func (m *IndexAdmin) CreateIndexWithIndexDefinition(schema *redisearch.Schema, definition *redisearch.IndexDefinition) (r0 error) {
+	m.mu.Lock()
+	m.createIndexWithIndexDefinitionCalls = append(m.createIndexWithIndexDefinitionCalls, CreateIndexWithIndexDefinitionCall{Schema: schema, Definition: definition})
+	fn := m.CreateIndexWithIndexDefinitionFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(schema, definition)
+}
+
+// CreateIndexWithIndexDefinitionReturns makes CreateIndexWithIndexDefinition reply with the given values
+func (m *IndexAdmin) CreateIndexWithIndexDefinitionReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CreateIndexWithIndexDefinitionFunc = func(*redisearch.Schema, *redisearch.IndexDefinition) error {
+		return r0
+	}
+}
+
+// CreateIndexWithIndexDefinitionCalls returns the calls of CreateIndexWithIndexDefinition
+func (m *IndexAdmin) CreateIndexWithIndexDefinitionCalls() []CreateIndexWithIndexDefinitionCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CreateIndexWithIndexDefinitionCall(nil), m.createIndexWithIndexDefinitionCalls...)
+}
+
+// AssertCreateIndexWithIndexDefinitionCalled checks that CreateIndexWithIndexDefinition was called with the given arguments
+func (m *IndexAdmin) AssertCreateIndexWithIndexDefinitionCalled(t TestingT, schema *redisearch.Schema, definition *redisearch.IndexDefinition) bool {
+	t.Helper()
+	want := CreateIndexWithIndexDefinitionCall{Schema: schema, Definition: definition}
+	return m.AssertCreateIndexWithIndexDefinitionCalledMatching(t, func(call CreateIndexWithIndexDefinitionCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertCreateIndexWithIndexDefinitionCalledMatching checks that CreateIndexWithIndexDefinition was called with arguments matching the given function
+func (m *IndexAdmin) AssertCreateIndexWithIndexDefinitionCalledMatching(t TestingT, match func(call CreateIndexWithIndexDefinitionCall) bool) bool {
+	t.Helper()
+	calls := m.CreateIndexWithIndexDefinitionCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.CreateIndexWithIndexDefinition, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertCreateIndexWithIndexDefinitionNotCalled checks that CreateIndexWithIndexDefinition was not called
+func (m *IndexAdmin) AssertCreateIndexWithIndexDefinitionNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.CreateIndexWithIndexDefinitionCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.CreateIndexWithIndexDefinition: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AddFieldCall is a call of IndexAdmin.AddField
+type AddFieldCall struct {
+	F redisearch.Field
+}
+
+// AddField records the call and replies with AddFieldFunc
+func (m *IndexAdmin) AddField(f redisearch.Field) (r0 error) {
+	m.mu.Lock()
+	m.addFieldCalls = append(m.addFieldCalls, AddFieldCall{F: f})
+	fn := m.AddFieldFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(f)
+}
+
+// AddFieldReturns makes AddField reply with the given values
+func (m *IndexAdmin) AddFieldReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AddFieldFunc = func(redisearch.Field) error {
+		return r0
+	}
+}
+
+// AddFieldCalls returns the calls of AddField
+func (m *IndexAdmin) AddFieldCalls() []AddFieldCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AddFieldCall(nil), m.addFieldCalls...)
+}
+
+// AssertAddFieldCalled checks that AddField was called with the given arguments
+func (m *IndexAdmin) AssertAddFieldCalled(t TestingT, f redisearch.Field) bool {
+	t.Helper()
+	want := AddFieldCall{F: f}
+	return m.AssertAddFieldCalledMatching(t, func(call AddFieldCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAddFieldCalledMatching checks that AddField was called with arguments matching the given function
+func (m *IndexAdmin) AssertAddFieldCalledMatching(t TestingT, match func(call AddFieldCall) bool) bool {
+	t.Helper()
+	calls := m.AddFieldCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.AddField, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAddFieldNotCalled checks that AddField was not called
+func (m *IndexAdmin) AssertAddFieldNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AddFieldCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.AddField: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// InfoCall is a call of IndexAdmin.Info
+type InfoCall struct {
+}
+
+// Info records the call and replies with InfoFunc
+func (m *IndexAdmin) Info() (info *redisearch.IndexInfo, err error) {
+	m.mu.Lock()
+	m.infoCalls = append(m.infoCalls, InfoCall{})
+	fn := m.InfoFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn()
+}
+
+// InfoReturns makes Info reply with the given values
+func (m *IndexAdmin) InfoReturns(info *redisearch.IndexInfo, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.InfoFunc = func() (*redisearch.IndexInfo, error) {
+		return info, err
+	}
+}
+
+// InfoCalls returns the calls of Info
+func (m *IndexAdmin) InfoCalls() []InfoCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InfoCall(nil), m.infoCalls...)
+}
+
+// AssertInfoCalled checks that Info was called with the given arguments
+func (m *IndexAdmin) AssertInfoCalled(t TestingT) bool {
+	t.Helper()
+	want := InfoCall{}
+	return m.AssertInfoCalledMatching(t, func(call InfoCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertInfoCalledMatching checks that Info was called with arguments matching the given function
+func (m *IndexAdmin) AssertInfoCalledMatching(t TestingT, match func(call InfoCall) bool) bool {
+	t.Helper()
+	calls := m.InfoCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.Info, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertInfoNotCalled checks that Info was not called
+func (m *IndexAdmin) AssertInfoNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.InfoCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.Info: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// DropIndexCall is a call of IndexAdmin.DropIndex
+type DropIndexCall struct {
+	DeleteDocuments bool
+}
+
+// DropIndex records the call and replies with DropIndexFunc
+func (m *IndexAdmin) DropIndex(deleteDocuments bool) (r0 error) {
+	m.mu.Lock()
+	m.dropIndexCalls = append(m.dropIndexCalls, DropIndexCall{DeleteDocuments: deleteDocuments})
+	fn := m.DropIndexFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(deleteDocuments)
+}
+
+// DropIndexReturns makes DropIndex reply with the given values
+func (m *IndexAdmin) DropIndexReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DropIndexFunc = func(bool) error {
+		return r0
+	}
+}
+
+// DropIndexCalls returns the calls of DropIndex
+func (m *IndexAdmin) DropIndexCalls() []DropIndexCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DropIndexCall(nil), m.dropIndexCalls...)
+}
+
+// AssertDropIndexCalled checks that DropIndex was called with the given arguments
+func (m *IndexAdmin) AssertDropIndexCalled(t TestingT, deleteDocuments bool) bool {
+	t.Helper()
+	want := DropIndexCall{DeleteDocuments: deleteDocuments}
+	return m.AssertDropIndexCalledMatching(t, func(call DropIndexCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertDropIndexCalledMatching checks that DropIndex was called with arguments matching the given function
+func (m *IndexAdmin) AssertDropIndexCalledMatching(t TestingT, match func(call DropIndexCall) bool) bool {
+	t.Helper()
+	calls := m.DropIndexCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.DropIndex, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertDropIndexNotCalled checks that DropIndex was not called
+func (m *IndexAdmin) AssertDropIndexNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.DropIndexCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.DropIndex: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// ListCall is a call of IndexAdmin.List
+type ListCall struct {
+}
+
+// List records the call and replies with ListFunc
+func (m *IndexAdmin) List() (indexes []string, err error) {
+	m.mu.Lock()
+	m.listCalls = append(m.listCalls, ListCall{})
+	fn := m.ListFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn()
+}
+
+// ListReturns makes List reply with the given values
+func (m *IndexAdmin) ListReturns(indexes []string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ListFunc = func() ([]string, error) {
+		return indexes, err
+	}
+}
+
+// ListCalls returns the calls of List
+func (m *IndexAdmin) ListCalls() []ListCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ListCall(nil), m.listCalls...)
+}
+
+// AssertListCalled checks that List was called with the given arguments
+func (m *IndexAdmin) AssertListCalled(t TestingT) bool {
+	t.Helper()
+	want := ListCall{}
+	return m.AssertListCalledMatching(t, func(call ListCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertListCalledMatching checks that List was called with arguments matching the given function
+func (m *IndexAdmin) AssertListCalledMatching(t TestingT, match func(call ListCall) bool) bool {
+	t.Helper()
+	calls := m.ListCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.List, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertListNotCalled checks that List was not called
+func (m *IndexAdmin) AssertListNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.ListCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.List: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AliasAddCall is a call of IndexAdmin.AliasAdd
+type AliasAddCall struct {
+	Name string
+}
+
+// AliasAdd records the call and replies with AliasAddFunc
+func (m *IndexAdmin) AliasAdd(name string) (r0 error) {
+	m.mu.Lock()
+	m.aliasAddCalls = append(m.aliasAddCalls, AliasAddCall{Name: name})
+	fn := m.AliasAddFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(name)
+}
+
+// AliasAddReturns makes AliasAdd reply with the given values
+func (m *IndexAdmin) AliasAddReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AliasAddFunc = func(string) error {
+		return r0
+	}
+}
+
+// AliasAddCalls returns the calls of AliasAdd
+func (m *IndexAdmin) AliasAddCalls() []AliasAddCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AliasAddCall(nil), m.aliasAddCalls...)
+}
+
+// AssertAliasAddCalled checks that AliasAdd was called with the given arguments
+func (m *IndexAdmin) AssertAliasAddCalled(t TestingT, name string) bool {
+	t.Helper()
+	want := AliasAddCall{Name: name}
+	return m.AssertAliasAddCalledMatching(t, func(call AliasAddCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAliasAddCalledMatching checks that AliasAdd was called with arguments matching the given function
+func (m *IndexAdmin) AssertAliasAddCalledMatching(t TestingT, match func(call AliasAddCall) bool) bool {
+	t.Helper()
+	calls := m.AliasAddCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.AliasAdd, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAliasAddNotCalled checks that AliasAdd was not called
+func (m *IndexAdmin) AssertAliasAddNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AliasAddCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.AliasAdd: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AliasDelCall is a call of IndexAdmin.AliasDel
+type AliasDelCall struct {
+	Name string
+}
+
+// AliasDel records the call and replies with AliasDelFunc
+func (m *IndexAdmin) AliasDel(name string) (r0 error) {
+	m.mu.Lock()
+	m.aliasDelCalls = append(m.aliasDelCalls, AliasDelCall{Name: name})
+	fn := m.AliasDelFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(name)
+}
+
+// AliasDelReturns makes AliasDel reply with the given values
+func (m *IndexAdmin) AliasDelReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AliasDelFunc = func(string) error {
+		return r0
+	}
+}
+
+// AliasDelCalls returns the calls of AliasDel
+func (m *IndexAdmin) AliasDelCalls() []AliasDelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AliasDelCall(nil), m.aliasDelCalls...)
+}
+
+// AssertAliasDelCalled checks that AliasDel was called with the given arguments
+func (m *IndexAdmin) AssertAliasDelCalled(t TestingT, name string) bool {
+	t.Helper()
+	want := AliasDelCall{Name: name}
+	return m.AssertAliasDelCalledMatching(t, func(call AliasDelCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAliasDelCalledMatching checks that AliasDel was called with arguments matching the given function
+func (m *IndexAdmin) AssertAliasDelCalledMatching(t TestingT, match func(call AliasDelCall) bool) bool {
+	t.Helper()
+	calls := m.AliasDelCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.AliasDel, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAliasDelNotCalled checks that AliasDel was not called
+func (m *IndexAdmin) AssertAliasDelNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AliasDelCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.AliasDel: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AliasUpdateCall is a call of IndexAdmin.AliasUpdate
+type AliasUpdateCall struct {
+	Name string
+}
+
+// AliasUpdate records the call and replies with AliasUpdateFunc
+func (m *IndexAdmin) AliasUpdate(name string) (r0 error) {
+	m.mu.Lock()
+	m.aliasUpdateCalls = append(m.aliasUpdateCalls, AliasUpdateCall{Name: name})
+	fn := m.AliasUpdateFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(name)
+}
+
+// AliasUpdateReturns makes AliasUpdate reply with the given values
+func (m *IndexAdmin) AliasUpdateReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AliasUpdateFunc = func(string) error {
+		return r0
+	}
+}
+
+// AliasUpdateCalls returns the calls of AliasUpdate
+func (m *IndexAdmin) AliasUpdateCalls() []AliasUpdateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AliasUpdateCall(nil), m.aliasUpdateCalls...)
+}
+
+// AssertAliasUpdateCalled checks that AliasUpdate was called with the given arguments
+func (m *IndexAdmin) AssertAliasUpdateCalled(t TestingT, name string) bool {
+	t.Helper()
+	want := AliasUpdateCall{Name: name}
+	return m.AssertAliasUpdateCalledMatching(t, func(call AliasUpdateCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAliasUpdateCalledMatching checks that AliasUpdate was called with arguments matching the given function
+func (m *IndexAdmin) AssertAliasUpdateCalledMatching(t TestingT, match func(call AliasUpdateCall) bool) bool {
+	t.Helper()
+	calls := m.AliasUpdateCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.AliasUpdate, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAliasUpdateNotCalled checks that AliasUpdate was not called
+func (m *IndexAdmin) AssertAliasUpdateNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AliasUpdateCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.AliasUpdate: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// SynUpdateCall is a call of IndexAdmin.SynUpdate
+type SynUpdateCall struct {
+	IndexName      string
+	SynonymGroupId int64
+	Terms          []string
+}
+
+// SynUpdate records the call and replies with SynUpdateFunc
+func (m *IndexAdmin) SynUpdate(indexName string, synonymGroupId int64, terms []string) (reply string, err error) {
+	m.mu.Lock()
+	m.synUpdateCalls = append(m.synUpdateCalls, SynUpdateCall{IndexName: indexName, SynonymGroupId: synonymGroupId, Terms: terms})
+	fn := m.SynUpdateFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(indexName, synonymGroupId, terms)
+}
+
+// SynUpdateReturns makes SynUpdate reply with the given values
+func (m *IndexAdmin) SynUpdateReturns(reply string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SynUpdateFunc = func(string, int64, []string) (string, error) {
+		return reply, err
+	}
+}
+
+// SynUpdateCalls returns the calls of SynUpdate
+func (m *IndexAdmin) SynUpdateCalls() []SynUpdateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SynUpdateCall(nil), m.synUpdateCalls...)
+}
+
+// AssertSynUpdateCalled checks that SynUpdate was called with the given arguments
+func (m *IndexAdmin) AssertSynUpdateCalled(t TestingT, indexName string, synonymGroupId int64, terms []string) bool {
+	t.Helper()
+	want := SynUpdateCall{IndexName: indexName, SynonymGroupId: synonymGroupId, Terms: terms}
+	return m.AssertSynUpdateCalledMatching(t, func(call SynUpdateCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertSynUpdateCalledMatching checks that SynUpdate was called with arguments matching the given function
+func (m *IndexAdmin) AssertSynUpdateCalledMatching(t TestingT, match func(call SynUpdateCall) bool) bool {
+	t.Helper()
+	calls := m.SynUpdateCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.SynUpdate, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertSynUpdateNotCalled checks that SynUpdate was not called
+func (m *IndexAdmin) AssertSynUpdateNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.SynUpdateCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.SynUpdate: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// SynDumpCall is a call of IndexAdmin.SynDump
+type SynDumpCall struct {
+	IndexName string
+}
+
+// SynDump records the call and replies with SynDumpFunc
+func (m *IndexAdmin) SynDump(indexName string) (synonyms map[string][]int64, err error) {
+	m.mu.Lock()
+	m.synDumpCalls = append(m.synDumpCalls, SynDumpCall{IndexName: indexName})
+	fn := m.SynDumpFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(indexName)
+}
+
+// SynDumpReturns makes SynDump reply with the given values
+func (m *IndexAdmin) SynDumpReturns(synonyms map[string][]int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SynDumpFunc = func(string) (map[string][]int64, error) {
+		return synonyms, err
+	}
+}
+
+// SynDumpCalls returns the calls of SynDump
+func (m *IndexAdmin) SynDumpCalls() []SynDumpCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SynDumpCall(nil), m.synDumpCalls...)
+}
+
+// AssertSynDumpCalled checks that SynDump was called with the given arguments
+func (m *IndexAdmin) AssertSynDumpCalled(t TestingT, indexName string) bool {
+	t.Helper()
+	want := SynDumpCall{IndexName: indexName}
+	return m.AssertSynDumpCalledMatching(t, func(call SynDumpCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertSynDumpCalledMatching checks that SynDump was called with arguments matching the given function
+func (m *IndexAdmin) AssertSynDumpCalledMatching(t TestingT, match func(call SynDumpCall) bool) bool {
+	t.Helper()
+	calls := m.SynDumpCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of IndexAdmin.SynDump, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertSynDumpNotCalled checks that SynDump was not called
+func (m *IndexAdmin) AssertSynDumpNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.SynDumpCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of IndexAdmin.SynDump: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// Suggester is a mock of redisearch.Suggester. Its zero value is ready to use and replies with zero values.
+type Suggester struct {
+	mu sync.Mutex
+	// AddTermsFunc replies to AddTerms, when set
+	AddTermsFunc  func(...redisearch.Suggestion) error
+	addTermsCalls []AddTermsCall
+	// DeleteTermsFunc replies to DeleteTerms, when set
+	DeleteTermsFunc  func(...redisearch.Suggestion) error
+	deleteTermsCalls []DeleteTermsCall
+	// SuggestFunc replies to Suggest, when set
+	SuggestFunc  func(string, int, bool) ([]redisearch.Suggestion, error)
+	suggestCalls []SuggestCall
+	// SuggestOptsFunc replies to SuggestOpts, when set
+	SuggestOptsFunc  func(string, redisearch.SuggestOptions) ([]redisearch.Suggestion, error)
+	suggestOptsCalls []SuggestOptsCall
+	// LengthFunc replies to Length, when set
+	LengthFunc  func() (int64, error)
+	lengthCalls []LengthCall
+	// DeleteFunc replies to Delete, when set
+	DeleteFunc  func() error
+	deleteCalls []DeleteCall
+}
+
+var _ redisearch.Suggester = (*Suggester)(nil)
+
+// AddTermsCall is a call of Suggester.AddTerms
+type AddTermsCall struct {
+	Terms []redisearch.Suggestion
+}
+
+// AddTerms records the call and replies with AddTermsFunc
+func (m *Suggester) AddTerms(terms ...redisearch.Suggestion) (r0 error) {
+	m.mu.Lock()
+	m.addTermsCalls = append(m.addTermsCalls, AddTermsCall{Terms: terms})
+	fn := m.AddTermsFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(terms...)
+}
+
+// AddTermsReturns makes AddTerms reply with the given values
+func (m *Suggester) AddTermsReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.AddTermsFunc = func(...redisearch.Suggestion) error {
+		return r0
+	}
+}
+
+// AddTermsCalls returns the calls of AddTerms
+func (m *Suggester) AddTermsCalls() []AddTermsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]AddTermsCall(nil), m.addTermsCalls...)
+}
+
+// AssertAddTermsCalled checks that AddTerms was called with the given arguments
+func (m *Suggester) AssertAddTermsCalled(t TestingT, terms []redisearch.Suggestion) bool {
+	t.Helper()
+	want := AddTermsCall{Terms: terms}
+	return m.AssertAddTermsCalledMatching(t, func(call AddTermsCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertAddTermsCalledMatching checks that AddTerms was called with arguments matching the given function
+func (m *Suggester) AssertAddTermsCalledMatching(t TestingT, match func(call AddTermsCall) bool) bool {
+	t.Helper()
+	calls := m.AddTermsCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.AddTerms, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertAddTermsNotCalled checks that AddTerms was not called
+func (m *Suggester) AssertAddTermsNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.AddTermsCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.AddTerms: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// DeleteTermsCall is a call of Suggester.DeleteTerms
+type DeleteTermsCall struct {
+	Terms []redisearch.Suggestion
+}
+
+// DeleteTerms records the call and replies with DeleteTermsFunc
+func (m *Suggester) DeleteTerms(terms ...redisearch.Suggestion) (r0 error) {
+	m.mu.Lock()
+	m.deleteTermsCalls = append(m.deleteTermsCalls, DeleteTermsCall{Terms: terms})
+	fn := m.DeleteTermsFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(terms...)
+}
+
+// DeleteTermsReturns makes DeleteTerms reply with the given values
+func (m *Suggester) DeleteTermsReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DeleteTermsFunc = func(...redisearch.Suggestion) error {
+		return r0
+	}
+}
+
+// DeleteTermsCalls returns the calls of DeleteTerms
+func (m *Suggester) DeleteTermsCalls() []DeleteTermsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DeleteTermsCall(nil), m.deleteTermsCalls...)
+}
+
+// AssertDeleteTermsCalled checks that DeleteTerms was called with the given arguments
+func (m *Suggester) AssertDeleteTermsCalled(t TestingT, terms []redisearch.Suggestion) bool {
+	t.Helper()
+	want := DeleteTermsCall{Terms: terms}
+	return m.AssertDeleteTermsCalledMatching(t, func(call DeleteTermsCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertDeleteTermsCalledMatching checks that DeleteTerms was called with arguments matching the given function
+func (m *Suggester) AssertDeleteTermsCalledMatching(t TestingT, match func(call DeleteTermsCall) bool) bool {
+	t.Helper()
+	calls := m.DeleteTermsCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.DeleteTerms, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertDeleteTermsNotCalled checks that DeleteTerms was not called
+func (m *Suggester) AssertDeleteTermsNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.DeleteTermsCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.DeleteTerms: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// SuggestCall is a call of Suggester.Suggest
+type SuggestCall struct {
+	Prefix string
+	Num    int
+	Fuzzy  bool
+}
+
+// Suggest records the call and replies with SuggestFunc
+func (m *Suggester) Suggest(prefix string, num int, fuzzy bool) (suggestions []redisearch.Suggestion, err error) {
+	m.mu.Lock()
+	m.suggestCalls = append(m.suggestCalls, SuggestCall{Prefix: prefix, Num: num, Fuzzy: fuzzy})
+	fn := m.SuggestFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(prefix, num, fuzzy)
+}
+
+// SuggestReturns makes Suggest reply with the given values
+func (m *Suggester) SuggestReturns(suggestions []redisearch.Suggestion, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SuggestFunc = func(string, int, bool) ([]redisearch.Suggestion, error) {
+		return suggestions, err
+	}
+}
+
+// SuggestCalls returns the calls of Suggest
+func (m *Suggester) SuggestCalls() []SuggestCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SuggestCall(nil), m.suggestCalls...)
+}
+
+// AssertSuggestCalled checks that Suggest was called with the given arguments
+func (m *Suggester) AssertSuggestCalled(t TestingT, prefix string, num int, fuzzy bool) bool {
+	t.Helper()
+	want := SuggestCall{Prefix: prefix, Num: num, Fuzzy: fuzzy}
+	return m.AssertSuggestCalledMatching(t, func(call SuggestCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertSuggestCalledMatching checks that Suggest was called with arguments matching the given function
+func (m *Suggester) AssertSuggestCalledMatching(t TestingT, match func(call SuggestCall) bool) bool {
+	t.Helper()
+	calls := m.SuggestCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.Suggest, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertSuggestNotCalled checks that Suggest was not called
+func (m *Suggester) AssertSuggestNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.SuggestCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.Suggest: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// SuggestOptsCall is a call of Suggester.SuggestOpts
+type SuggestOptsCall struct {
+	Prefix string
+	Opts   redisearch.SuggestOptions
+}
+
+// SuggestOpts records the call and replies with SuggestOptsFunc
+func (m *Suggester) SuggestOpts(prefix string, opts redisearch.SuggestOptions) (suggestions []redisearch.Suggestion, err error) {
+	m.mu.Lock()
+	m.suggestOptsCalls = append(m.suggestOptsCalls, SuggestOptsCall{Prefix: prefix, Opts: opts})
+	fn := m.SuggestOptsFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn(prefix, opts)
+}
+
+// SuggestOptsReturns makes SuggestOpts reply with the given values
+func (m *Suggester) SuggestOptsReturns(suggestions []redisearch.Suggestion, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SuggestOptsFunc = func(string, redisearch.SuggestOptions) ([]redisearch.Suggestion, error) {
+		return suggestions, err
+	}
+}
+
+// SuggestOptsCalls returns the calls of SuggestOpts
+func (m *Suggester) SuggestOptsCalls() []SuggestOptsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SuggestOptsCall(nil), m.suggestOptsCalls...)
+}
+
+// AssertSuggestOptsCalled checks that SuggestOpts was called with the given arguments
+func (m *Suggester) AssertSuggestOptsCalled(t TestingT, prefix string, opts redisearch.SuggestOptions) bool {
+	t.Helper()
+	want := SuggestOptsCall{Prefix: prefix, Opts: opts}
+	return m.AssertSuggestOptsCalledMatching(t, func(call SuggestOptsCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertSuggestOptsCalledMatching checks that SuggestOpts was called with arguments matching the given function
+func (m *Suggester) AssertSuggestOptsCalledMatching(t TestingT, match func(call SuggestOptsCall) bool) bool {
+	t.Helper()
+	calls := m.SuggestOptsCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.SuggestOpts, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertSuggestOptsNotCalled checks that SuggestOpts was not called
+func (m *Suggester) AssertSuggestOptsNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.SuggestOptsCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.SuggestOpts: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// LengthCall is a call of Suggester.Length
+type LengthCall struct {
+}
+
+// Length records the call and replies with LengthFunc
+func (m *Suggester) Length() (length int64, err error) {
+	m.mu.Lock()
+	m.lengthCalls = append(m.lengthCalls, LengthCall{})
+	fn := m.LengthFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn()
+}
+
+// LengthReturns makes Length reply with the given values
+func (m *Suggester) LengthReturns(length int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.LengthFunc = func() (int64, error) {
+		return length, err
+	}
+}
+
+// LengthCalls returns the calls of Length
+func (m *Suggester) LengthCalls() []LengthCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]LengthCall(nil), m.lengthCalls...)
+}
+
+// AssertLengthCalled checks that Length was called with the given arguments
+func (m *Suggester) AssertLengthCalled(t TestingT) bool {
+	t.Helper()
+	want := LengthCall{}
+	return m.AssertLengthCalledMatching(t, func(call LengthCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertLengthCalledMatching checks that Length was called with arguments matching the given function
+func (m *Suggester) AssertLengthCalledMatching(t TestingT, match func(call LengthCall) bool) bool {
+	t.Helper()
+	calls := m.LengthCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.Length, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertLengthNotCalled checks that Length was not called
+func (m *Suggester) AssertLengthNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.LengthCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.Length: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
+
+// DeleteCall is a call of Suggester.Delete
+type DeleteCall struct {
+}
+
+// Delete records the call and replies with DeleteFunc
+func (m *Suggester) Delete() (r0 error) {
+	m.mu.Lock()
+	m.deleteCalls = append(m.deleteCalls, DeleteCall{})
+	fn := m.DeleteFunc
+	m.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	return fn()
+}
+
+// DeleteReturns makes Delete reply with the given values
+func (m *Suggester) DeleteReturns(r0 error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.DeleteFunc = func() error {
+		return r0
+	}
+}
+
+// DeleteCalls returns the calls of Delete
+func (m *Suggester) DeleteCalls() []DeleteCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DeleteCall(nil), m.deleteCalls...)
+}
+
+// AssertDeleteCalled checks that Delete was called with the given arguments
+func (m *Suggester) AssertDeleteCalled(t TestingT) bool {
+	t.Helper()
+	want := DeleteCall{}
+	return m.AssertDeleteCalledMatching(t, func(call DeleteCall) bool {
+		return reflect.DeepEqual(call, want)
+	})
+}
+
+// AssertDeleteCalledMatching checks that Delete was called with arguments matching the given function
+func (m *Suggester) AssertDeleteCalledMatching(t TestingT, match func(call DeleteCall) bool) bool {
+	t.Helper()
+	calls := m.DeleteCalls()
+	for _, call := range calls {
+		if match(call) {
+			return true
+		}
+	}
+	t.Errorf("redisearchmock: no matching call of Suggester.Delete, got %s", describeCalls(calls))
+	return false
+}
+
+// AssertDeleteNotCalled checks that Delete was not called
+func (m *Suggester) AssertDeleteNotCalled(t TestingT) bool {
+	t.Helper()
+	if calls := m.DeleteCalls(); len(calls) > 0 {
+		t.Errorf("redisearchmock: unexpected calls of Suggester.Delete: %s", describeCalls(calls))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+// Package redisearchmock provides in-memory mocks of the redisearch interfaces, to test the code searching with
+// redisearch without a server.
+//
+// Each method of a mock records its calls and replies with its Func field, or with the values given to its Returns
+// method. The calls can be checked with the Assert methods, for instance:
+//
+//	m := &redisearchmock.Client{}
+//	m.SearchReturns([]redisearch.Document{redisearch.NewDocument("doc1", 1)}, 1, nil)
+//	runCode(m)
+//	m.AssertSearchCalledMatching(t, func(call redisearchmock.SearchCall) bool {
+//		return call.Q.Raw == "hello" && call.Q.Paging.Num == 5
+//	})
+package redisearchmock
+
+//go:generate go run gen.go
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+)
+
+// TestingT is the subset of testing.TB used by the assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Client is a mock of all the interfaces implemented by redisearch.Client
+type Client struct {
+	Searcher
+	Indexer
+	Aggregator
+	IndexAdmin
+}
+
+var (
+	_ redisearch.Searcher   = (*Client)(nil)
+	_ redisearch.Indexer    = (*Client)(nil)
+	_ redisearch.Aggregator = (*Client)(nil)
+	_ redisearch.IndexAdmin = (*Client)(nil)
+)
+
+// Autocompleter is a mock of the interface implemented by redisearch.Autocompleter
+type Autocompleter = Suggester
+
+// describeCalls formats the arguments of calls, following the pointers
+func describeCalls(calls interface{}) string {
+	v := reflect.ValueOf(calls)
+	if v.Len() == 0 {
+		return "no calls"
+	}
+	ret := make([]string, v.Len())
+	for ii := range ret {
+		call := v.Index(ii)
+		fields := make([]string, call.NumField())
+		for jj := range fields {
+			fields[jj] = fmt.Sprintf("%s: %s", call.Type().Field(jj).Name, describe(call.Field(jj)))
+		}
+		ret[ii] = "(" + strings.Join(fields, ", ") + ")"
+	}
+	return strings.Join(ret, ", ")
+}
+
+func describe(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		return fmt.Sprintf("&%+v", v.Elem().Interface())
+	}
+	return fmt.Sprintf("%+v", v.Interface())
+}
@@ -0,0 +1,110 @@
+package redisearchmock_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingT records the failures of the assertions
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+// searchTitles is the code under test, using the interfaces of the redisearch package
+func searchTitles(s redisearch.Searcher, term string) ([]string, error) {
+	docs, _, err := s.Search(redisearch.NewQuery(term).Limit(0, 5).SetReturnFields("title"))
+	if err != nil {
+		return nil, err
+	}
+	titles := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		titles = append(titles, doc.Properties["title"].(string))
+	}
+	return titles, nil
+}
+
+func TestSearcher(t *testing.T) {
+	m := &redisearchmock.Client{}
+	m.SearchReturns([]redisearch.Document{redisearch.NewDocument("doc1", 1).Set("title", "Hello")}, 1, nil)
+
+	titles, err := searchTitles(m, "hello")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Hello"}, titles)
+
+	assert.True(t, m.AssertSearchCalled(t, redisearch.NewQuery("hello").Limit(0, 5).SetReturnFields("title")))
+	assert.True(t, m.AssertSearchCalledMatching(t, func(call redisearchmock.SearchCall) bool {
+		return call.Q.Raw == "hello" && call.Q.Paging.Num == 5
+	}))
+	assert.Len(t, m.SearchCalls(), 1)
+	assert.True(t, m.AssertAggregateNotCalled(t))
+
+	m.SearchFunc = func(q *redisearch.Query) ([]redisearch.Document, int, error) {
+		return nil, 0, errors.New("Unknown Index name")
+	}
+	_, err = searchTitles(m, "world")
+	assert.EqualError(t, err, "Unknown Index name")
+	assert.Len(t, m.SearchCalls(), 2)
+}
+
+func TestSearcher_failedAssertions(t *testing.T) {
+	m := &redisearchmock.Searcher{}
+	rt := &recordingT{}
+	assert.False(t, m.AssertSearchCalled(rt, redisearch.NewQuery("hello")))
+	assert.Equal(t, []string{"redisearchmock: no matching call of Searcher.Search, got no calls"}, rt.errors)
+
+	docs, total, err := m.Search(redisearch.NewQuery("world"))
+	assert.Nil(t, docs)
+	assert.Equal(t, 0, total)
+	assert.Nil(t, err)
+
+	rt = &recordingT{}
+	assert.False(t, m.AssertSearchCalled(rt, redisearch.NewQuery("hello")))
+	assert.False(t, m.AssertSearchNotCalled(rt))
+	assert.Len(t, rt.errors, 2)
+	assert.Contains(t, rt.errors[0], "Raw:world")
+}
+
+func TestAggregator(t *testing.T) {
+	m := &redisearchmock.Client{}
+	m.AggregateQueryReturns(1, []map[string]interface{}{{"brand": "sony", "count": "3"}}, nil)
+
+	q := redisearch.NewAggregateQuery().SetQuery(redisearch.NewQuery("*")).
+		GroupBy(*redisearch.NewGroupBy().AddFields("@brand").
+			Reduce(*redisearch.NewReducerAlias(redisearch.GroupByReducerCount, []string{}, "count")))
+	total, rows, err := m.AggregateQuery(q)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "sony", rows[0]["brand"])
+
+	assert.True(t, m.AssertAggregateQueryCalledMatching(t, func(call redisearchmock.AggregateQueryCall) bool {
+		return len(call.Q.AggregatePlan) > 0 && call.Q.AggregatePlan[0] == "GROUPBY"
+	}))
+}
+
+func TestIndexerAndSuggester(t *testing.T) {
+	m := &redisearchmock.Client{}
+	doc := redisearch.NewDocument("doc1", 1).Set("title", "Hello")
+	assert.Nil(t, m.Index(doc))
+	assert.True(t, m.AssertIndexCalled(t, []redisearch.Document{doc}))
+
+	m.InfoReturns(&redisearch.IndexInfo{Name: "idx", DocCount: 1}, nil)
+	info, err := m.Info()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), info.DocCount)
+	assert.True(t, m.AssertInfoCalled(t))
+
+	var s redisearch.Suggester = &redisearchmock.Autocompleter{}
+	assert.Nil(t, s.AddTerms(redisearch.Suggestion{Term: "hello", Score: 1}))
+	assert.True(t, s.(*redisearchmock.Suggester).AssertAddTermsCalled(t, []redisearch.Suggestion{{Term: "hello", Score: 1}}))
+}
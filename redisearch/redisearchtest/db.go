@@ -0,0 +1,534 @@
+package redisearchtest
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// db holds the keys and the search state of a server
+type db struct {
+	hashes  map[string]*hash
+	strings map[string]string
+	// scores and payloads of the documents added with FT.ADD
+	scores   map[string]float64
+	payloads map[string]string
+
+	indexes     map[string]*index
+	aliases     map[string]string
+	suggestions map[string]map[string]*suggestion
+	dicts       map[string]map[string]bool
+	config      map[string]string
+}
+
+func newDB() *db {
+	return &db{
+		hashes:      make(map[string]*hash),
+		strings:     make(map[string]string),
+		scores:      make(map[string]float64),
+		payloads:    make(map[string]string),
+		indexes:     make(map[string]*index),
+		aliases:     make(map[string]string),
+		suggestions: make(map[string]map[string]*suggestion),
+		dicts:       make(map[string]map[string]bool),
+		config:      defaultConfig(),
+	}
+}
+
+// hash is a redis hash, keeping the insertion order of its fields like redis does
+type hash struct {
+	fields []string
+	values map[string]string
+}
+
+func newHash() *hash {
+	return &hash{values: make(map[string]string)}
+}
+
+func (h *hash) set(field, value string) bool {
+	_, exists := h.values[field]
+	if !exists {
+		h.fields = append(h.fields, field)
+	}
+	h.values[field] = value
+	return !exists
+}
+
+func (h *hash) del(field string) bool {
+	if _, exists := h.values[field]; !exists {
+		return false
+	}
+	delete(h.values, field)
+	for i, f := range h.fields {
+		if f == field {
+			h.fields = append(h.fields[:i], h.fields[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (h *hash) get(field string) (string, bool) {
+	value, ok := h.values[field]
+	return value, ok
+}
+
+// flat returns the fields and values of the hash, in insertion order
+func (h *hash) flat() []interface{} {
+	ret := make([]interface{}, 0, 2*len(h.fields))
+	for _, field := range h.fields {
+		ret = append(ret, field, h.values[field])
+	}
+	return ret
+}
+
+// handler runs a command, args excluding the command name
+type handler func(d *db, args []string) interface{}
+
+// commands are the commands implemented by the server
+var commands map[string]handler
+
+func init() {
+	commands = map[string]handler{
+		"PING":     cmdPing,
+		"ECHO":     cmdEcho,
+		"AUTH":     cmdOK,
+		"SELECT":   cmdOK,
+		"QUIT":     cmdOK,
+		"CLIENT":   cmdOK,
+		"HELLO":    cmdHello,
+		"MODULE":   cmdModule,
+		"FLUSHALL": cmdFlushAll,
+		"FLUSHDB":  cmdFlushAll,
+		"DEL":      cmdDel,
+		"UNLINK":   cmdDel,
+		"EXISTS":   cmdExists,
+		"KEYS":     cmdKeys,
+		"SCAN":     cmdScan,
+		"TYPE":     cmdType,
+		"SET":      cmdSet,
+		"GET":      cmdGet,
+		"HSET":     cmdHSet,
+		"HMSET":    cmdHMSet,
+		"HGET":     cmdHGet,
+		"HMGET":    cmdHMGet,
+		"HGETALL":  cmdHGetAll,
+		"HDEL":     cmdHDel,
+		"HLEN":     cmdHLen,
+
+		"FT.CREATE":      cmdFTCreate,
+		"FT.ALTER":       cmdFTAlter,
+		"FT.INFO":        cmdFTInfo,
+		"FT.DROPINDEX":   cmdFTDropIndex,
+		"FT.DROP":        cmdFTDrop,
+		"FT._LIST":       cmdFTList,
+		"FT.ALIASADD":    cmdFTAliasAdd,
+		"FT.ALIASUPDATE": cmdFTAliasUpdate,
+		"FT.ALIASDEL":    cmdFTAliasDel,
+		"FT.ADD":         cmdFTAdd,
+		"FT.GET":         cmdFTGet,
+		"FT.MGET":        cmdFTMGet,
+		"FT.DEL":         cmdFTDel,
+		"FT.SEARCH":      cmdFTSearch,
+		"FT.EXPLAIN":     cmdFTExplain,
+		"FT.TAGVALS":     cmdFTTagVals,
+		"FT.CONFIG":      cmdFTConfig,
+		"FT.SYNUPDATE":   cmdFTSynUpdate,
+		"FT.SYNDUMP":     cmdFTSynDump,
+		"FT.SUGADD":      cmdFTSugAdd,
+		"FT.SUGGET":      cmdFTSugGet,
+		"FT.SUGDEL":      cmdFTSugDel,
+		"FT.SUGLEN":      cmdFTSugLen,
+		"FT.DICTADD":     cmdFTDictAdd,
+		"FT.DICTDEL":     cmdFTDictDel,
+		"FT.DICTDUMP":    cmdFTDictDump,
+	}
+}
+
+func cmdOK(d *db, args []string) interface{} {
+	return okReply
+}
+
+func cmdPing(d *db, args []string) interface{} {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return statusReply("PONG")
+}
+
+func cmdEcho(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("echo")
+	}
+	return args[0]
+}
+
+func cmdHello(d *db, args []string) interface{} {
+	if len(args) > 0 && args[0] != "2" {
+		return errorReply("NOPROTO unsupported protocol version")
+	}
+	return []interface{}{"server", "redis", "proto", int64(2)}
+}
+
+// searchVersion is the RediSearch version reported by MODULE LIST
+const searchVersion = 20809
+
+func cmdModule(d *db, args []string) interface{} {
+	if len(args) == 0 || !strings.EqualFold(args[0], "LIST") {
+		return errorReply("ERR only MODULE LIST is supported")
+	}
+	return []interface{}{[]interface{}{"name", "search", "ver", int64(searchVersion)}}
+}
+
+func cmdFlushAll(d *db, args []string) interface{} {
+	*d = *newDB()
+	return okReply
+}
+
+// exists returns whether the key exists, whatever its type
+func (d *db) exists(key string) bool {
+	if _, ok := d.hashes[key]; ok {
+		return true
+	}
+	if _, ok := d.strings[key]; ok {
+		return true
+	}
+	_, ok := d.suggestions[key]
+	return ok
+}
+
+// delete deletes a key, whatever its type
+func (d *db) delete(key string) bool {
+	existed := d.exists(key)
+	d.touch(key)
+	delete(d.hashes, key)
+	delete(d.strings, key)
+	delete(d.suggestions, key)
+	delete(d.scores, key)
+	delete(d.payloads, key)
+	return existed
+}
+
+func cmdDel(d *db, args []string) interface{} {
+	if len(args) == 0 {
+		return wrongArity("del")
+	}
+	n := int64(0)
+	for _, key := range args {
+		if d.delete(key) {
+			n++
+		}
+	}
+	return n
+}
+
+func cmdExists(d *db, args []string) interface{} {
+	if len(args) == 0 {
+		return wrongArity("exists")
+	}
+	n := int64(0)
+	for _, key := range args {
+		if d.exists(key) {
+			n++
+		}
+	}
+	return n
+}
+
+func cmdKeys(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("keys")
+	}
+	keys := make([]string, 0)
+	for key := range d.keySet() {
+		if ok, _ := path.Match(args[0], key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cmdScan iterates over the sorted keys, the cursor being the position of the next key
+func cmdScan(d *db, args []string) interface{} {
+	if len(args) < 1 || len(args)%2 != 1 {
+		return wrongArity("scan")
+	}
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil || cursor < 0 {
+		return errorReply("ERR invalid cursor")
+	}
+	pattern, count, typ := "*", 10, ""
+	for i := 1; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil || count < 1 {
+				return errorReply("ERR value is not an integer or out of range")
+			}
+		case "TYPE":
+			typ = args[i+1]
+		default:
+			return errorReply("ERR syntax error")
+		}
+	}
+	all := make([]string, 0)
+	for key := range d.keySet() {
+		all = append(all, key)
+	}
+	sort.Strings(all)
+	keys := make([]string, 0)
+	next := 0
+	if end := cursor + count; end < len(all) {
+		next = end
+		all = all[:end]
+	}
+	if cursor > len(all) {
+		cursor = len(all)
+	}
+	for _, key := range all[cursor:] {
+		if ok, _ := path.Match(pattern, key); !ok {
+			continue
+		}
+		if typ != "" && cmdType(d, []string{key}) != statusReply(typ) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return []interface{}{strconv.Itoa(next), keys}
+}
+
+func (d *db) keySet() map[string]bool {
+	keys := make(map[string]bool)
+	for key := range d.hashes {
+		keys[key] = true
+	}
+	for key := range d.strings {
+		keys[key] = true
+	}
+	for key := range d.suggestions {
+		keys[key] = true
+	}
+	return keys
+}
+
+func cmdType(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("type")
+	}
+	switch {
+	case d.hashes[args[0]] != nil:
+		return statusReply("hash")
+	case d.suggestions[args[0]] != nil:
+		return statusReply("trie_type")
+	}
+	if _, ok := d.strings[args[0]]; ok {
+		return statusReply("string")
+	}
+	return statusReply("none")
+}
+
+var wrongType = errorReply("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+func cmdSet(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("set")
+	}
+	key, value := args[0], args[1]
+	nx, xx := false, false
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX", "EXAT", "PXAT":
+			// expirations are not supported, the keys live until they are deleted
+			i++
+		case "KEEPTTL":
+		default:
+			return errorReply("ERR syntax error")
+		}
+	}
+	exists := d.exists(key)
+	if (nx && exists) || (xx && !exists) {
+		return nil
+	}
+	d.delete(key)
+	d.strings[key] = value
+	return okReply
+}
+
+func cmdGet(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("get")
+	}
+	if value, ok := d.strings[args[0]]; ok {
+		return value
+	}
+	if d.exists(args[0]) {
+		return wrongType
+	}
+	return nil
+}
+
+// hashForWrite returns the hash of the key, creating it if needed
+func (d *db) hashForWrite(key string) (*hash, errorReply) {
+	if h, ok := d.hashes[key]; ok {
+		return h, ""
+	}
+	if d.exists(key) {
+		return nil, wrongType
+	}
+	h := newHash()
+	d.hashes[key] = h
+	return h, ""
+}
+
+// hashForRead returns the hash of the key, nil if it does not exist
+func (d *db) hashForRead(key string) (*hash, errorReply) {
+	if h, ok := d.hashes[key]; ok {
+		return h, ""
+	}
+	if d.exists(key) {
+		return nil, wrongType
+	}
+	return nil, ""
+}
+
+func cmdHSet(d *db, args []string) interface{} {
+	if len(args) < 3 || len(args)%2 != 1 {
+		return wrongArity("hset")
+	}
+	h, err := d.hashForWrite(args[0])
+	if err != "" {
+		return err
+	}
+	d.touch(args[0])
+	n := int64(0)
+	for i := 1; i+1 < len(args); i += 2 {
+		if h.set(args[i], args[i+1]) {
+			n++
+		}
+	}
+	return n
+}
+
+func cmdHMSet(d *db, args []string) interface{} {
+	if reply := cmdHSet(d, args); isError(reply) {
+		return reply
+	}
+	return okReply
+}
+
+func isError(reply interface{}) bool {
+	_, ok := reply.(errorReply)
+	return ok
+}
+
+func cmdHGet(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("hget")
+	}
+	h, err := d.hashForRead(args[0])
+	if err != "" {
+		return err
+	}
+	if h == nil {
+		return nil
+	}
+	if value, ok := h.get(args[1]); ok {
+		return value
+	}
+	return nil
+}
+
+func cmdHMGet(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("hmget")
+	}
+	h, err := d.hashForRead(args[0])
+	if err != "" {
+		return err
+	}
+	ret := make([]interface{}, len(args)-1)
+	for i, field := range args[1:] {
+		if h != nil {
+			if value, ok := h.get(field); ok {
+				ret[i] = value
+			}
+		}
+	}
+	return ret
+}
+
+func cmdHGetAll(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("hgetall")
+	}
+	h, err := d.hashForRead(args[0])
+	if err != "" {
+		return err
+	}
+	if h == nil {
+		return []interface{}{}
+	}
+	return h.flat()
+}
+
+func cmdHDel(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("hdel")
+	}
+	h, err := d.hashForRead(args[0])
+	if err != "" {
+		return err
+	}
+	n := int64(0)
+	if h == nil {
+		return n
+	}
+	for _, field := range args[1:] {
+		if h.del(field) {
+			n++
+		}
+	}
+	if len(h.fields) == 0 {
+		d.delete(args[0])
+	}
+	return n
+}
+
+func cmdHLen(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("hlen")
+	}
+	h, err := d.hashForRead(args[0])
+	if err != "" {
+		return err
+	}
+	if h == nil {
+		return int64(0)
+	}
+	return int64(len(h.fields))
+}
+
+// parseInt parses an integer argument
+func parseInt(s string) (int64, errorReply) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errorReply("ERR value is not an integer or out of range")
+	}
+	return n, ""
+}
+
+// parseFloat parses a float argument
+func parseFloat(s string) (float64, errorReply) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errorReply("ERR value is not a valid float")
+	}
+	return f, ""
+}
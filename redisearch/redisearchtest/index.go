@@ -0,0 +1,876 @@
+package redisearchtest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// index is a search index. Documents are not copied into the index: the hashes matching its prefixes are
+// scanned on each query.
+type index struct {
+	name         string
+	prefixes     []string
+	fields       []*field
+	options      []string
+	stopwords    map[string]bool
+	defaultScore float64
+	language     string
+	scoreField   string
+	payloadField string
+	// maxDocID counts the documents added with FT.ADD
+	maxDocID int64
+	// skipped holds the keys that existed when the index was created with SKIPINITIALSCAN, until they are
+	// written again
+	skipped map[string]bool
+	// synonyms maps the terms to their synonym groups
+	synonyms map[string][]string
+}
+
+// field is an attribute of the index schema
+type field struct {
+	name          string
+	alias         string
+	typ           string
+	weight        float64
+	separator     string
+	phonetic      string
+	sortable      bool
+	unf           bool
+	noStem        bool
+	noIndex       bool
+	caseSensitive bool
+	suffixTrie    bool
+	// vector holds the algorithm and the attributes of vector fields
+	vector []string
+}
+
+// defaultStopwords are the stopwords of indexes created without STOPWORDS
+var defaultStopwords = []string{
+	"a", "is", "the", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in", "into", "it", "no",
+	"not", "of", "on", "or", "such", "that", "their", "then", "there", "these", "they", "this", "to", "was",
+	"will", "with",
+}
+
+func stopwordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(w)] = true
+	}
+	return set
+}
+
+// field returns the field with the given alias (or name when it has no alias)
+func (idx *index) field(name string) *field {
+	for _, f := range idx.fields {
+		if f.alias == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// matches returns whether the key is indexed
+func (idx *index) matches(key string) bool {
+	if len(idx.prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range idx.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// docs returns the keys of the indexed documents, sorted, and the number of hashes which failed to be indexed
+func (d *db) docs(idx *index) ([]string, int) {
+	keys := make([]string, 0)
+	failures := 0
+	for key, h := range d.hashes {
+		if !idx.matches(key) || idx.skipped[key] {
+			continue
+		}
+		if !idx.indexable(h) {
+			failures++
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, failures
+}
+
+// keys returns the keys of the indexed documents, sorted
+func (d *db) keys(idx *index) []string {
+	keys, _ := d.docs(idx)
+	return keys
+}
+
+// indexable returns whether the numeric and geo fields of a hash can be parsed
+func (idx *index) indexable(h *hash) bool {
+	for _, f := range idx.fields {
+		value, ok := h.get(f.name)
+		if !ok || f.noIndex {
+			continue
+		}
+		switch f.typ {
+		case "NUMERIC":
+			if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+				return false
+			}
+		case "GEO":
+			if _, _, ok := parseGeo(value); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// touch marks a key as written, so that indexes created with SKIPINITIALSCAN index it
+func (d *db) touch(key string) {
+	for _, idx := range d.indexes {
+		delete(idx.skipped, key)
+	}
+}
+
+// docScore returns the score of a document
+func (d *db) docScore(idx *index, key string) float64 {
+	if score, ok := d.scores[key]; ok {
+		return score
+	}
+	if idx.scoreField != "" {
+		if value, ok := d.hashes[key].get(idx.scoreField); ok {
+			if score, err := strconv.ParseFloat(value, 64); err == nil {
+				return score
+			}
+		}
+	}
+	return idx.defaultScore
+}
+
+// docPayload returns the payload of a document, and whether it has one
+func (d *db) docPayload(idx *index, key string) (string, bool) {
+	if payload, ok := d.payloads[key]; ok {
+		return payload, true
+	}
+	if idx.payloadField != "" {
+		return d.hashes[key].get(idx.payloadField)
+	}
+	return "", false
+}
+
+// lookupIndex resolves an index name or alias
+func (d *db) lookupIndex(name string) (*index, errorReply) {
+	if alias, ok := d.aliases[name]; ok {
+		name = alias
+	}
+	idx, ok := d.indexes[name]
+	if !ok {
+		return nil, errorReply("Unknown Index name")
+	}
+	return idx, ""
+}
+
+// argReader iterates over the arguments of a command
+type argReader struct {
+	args []string
+	pos  int
+}
+
+func (r *argReader) done() bool {
+	return r.pos >= len(r.args)
+}
+
+func (r *argReader) peek() string {
+	if r.done() {
+		return ""
+	}
+	return strings.ToUpper(r.args[r.pos])
+}
+
+// next returns the next argument, and false when there are no arguments left
+func (r *argReader) next() (string, bool) {
+	if r.done() {
+		return "", false
+	}
+	r.pos++
+	return r.args[r.pos-1], true
+}
+
+// nextN returns the n next arguments
+func (r *argReader) nextN(n int) ([]string, bool) {
+	if n < 0 || r.pos+n > len(r.args) {
+		return nil, false
+	}
+	r.pos += n
+	return r.args[r.pos-n : r.pos], true
+}
+
+// nextCount reads a count followed by as many arguments
+func (r *argReader) nextCount() ([]string, bool) {
+	s, ok := r.next()
+	if !ok {
+		return nil, false
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, false
+	}
+	return r.nextN(n)
+}
+
+func cmdFTCreate(d *db, args []string) interface{} {
+	if len(args) < 1 {
+		return wrongArity("ft.create")
+	}
+	name := args[0]
+	if _, ok := d.indexes[name]; ok {
+		return errorReply("Index already exists")
+	}
+	idx := &index{
+		name:         name,
+		stopwords:    stopwordSet(defaultStopwords),
+		defaultScore: 1,
+		language:     "english",
+	}
+	r := &argReader{args: args[1:]}
+	for !r.done() && r.peek() != "SCHEMA" {
+		opt, _ := r.next()
+		var ok = true
+		switch strings.ToUpper(opt) {
+		case "ON":
+			var typ string
+			if typ, ok = r.next(); ok && !strings.EqualFold(typ, "HASH") {
+				return errorf("ERR %s indexes are not supported", strings.ToUpper(typ))
+			}
+		case "PREFIX":
+			idx.prefixes, ok = r.nextCount()
+		case "FILTER", "LANGUAGE_FIELD":
+			// filter expressions and per-document languages are accepted and ignored
+			_, ok = r.next()
+		case "LANGUAGE":
+			idx.language, ok = r.next()
+		case "SCORE":
+			var s string
+			if s, ok = r.next(); ok {
+				if f, err := parseFloat(s); err == "" {
+					idx.defaultScore = f
+				} else {
+					return err
+				}
+			}
+		case "SCORE_FIELD":
+			idx.scoreField, ok = r.next()
+		case "PAYLOAD_FIELD":
+			idx.payloadField, ok = r.next()
+		case "TEMPORARY":
+			_, ok = r.next()
+		case "STOPWORDS":
+			var words []string
+			if words, ok = r.nextCount(); ok {
+				idx.stopwords = stopwordSet(words)
+			}
+		case "MAXTEXTFIELDS", "NOOFFSETS", "NOHL", "NOFIELDS", "NOFREQS", "SKIPINITIALSCAN", "ASYNC", "NOSAVE":
+			idx.options = append(idx.options, strings.ToUpper(opt))
+		default:
+			return errorf("Unknown argument `%s`", opt)
+		}
+		if !ok {
+			return errorf("Bad arguments for %s", strings.ToUpper(opt))
+		}
+	}
+	if _, ok := r.next(); !ok {
+		return errorReply("No schema found")
+	}
+	fields, err := parseSchema(r)
+	if err != "" {
+		return err
+	}
+	if len(fields) == 0 {
+		return errorReply("Fields arguments are missing")
+	}
+	idx.fields = fields
+	if idx.hasOption("SKIPINITIALSCAN") {
+		idx.skipped = make(map[string]bool)
+		for key := range d.hashes {
+			if idx.matches(key) {
+				idx.skipped[key] = true
+			}
+		}
+	}
+	d.indexes[name] = idx
+	return okReply
+}
+
+func (idx *index) hasOption(option string) bool {
+	return containsString(idx.options, option)
+}
+
+func cmdFTAlter(d *db, args []string) interface{} {
+	if len(args) < 3 {
+		return wrongArity("ft.alter")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	r := &argReader{args: args[1:]}
+	if r.peek() == "SKIPINITIALSCAN" {
+		r.next()
+	}
+	if opt, _ := r.next(); strings.ToUpper(opt) != "SCHEMA" {
+		return errorReply("Unknown action passed to ALTER SCHEMA")
+	}
+	if opt, _ := r.next(); strings.ToUpper(opt) != "ADD" {
+		return errorReply("Unknown action passed to ALTER SCHEMA")
+	}
+	fields, err := parseSchema(r)
+	if err != "" {
+		return err
+	}
+	for _, f := range fields {
+		if idx.field(f.alias) != nil {
+			return errorf("Duplicate field in schema - %s", f.alias)
+		}
+	}
+	idx.fields = append(idx.fields, fields...)
+	return okReply
+}
+
+// parseSchema parses the fields following SCHEMA
+func parseSchema(r *argReader) ([]*field, errorReply) {
+	fields := make([]*field, 0)
+	for !r.done() {
+		name, _ := r.next()
+		f := &field{name: name, alias: name, weight: 1, separator: ","}
+		if r.peek() == "AS" {
+			r.next()
+			alias, ok := r.next()
+			if !ok {
+				return nil, errorReply("Bad arguments for AS")
+			}
+			f.alias = alias
+		}
+		typ, ok := r.next()
+		if !ok {
+			return nil, errorf("Field `%s` does not have a type", name)
+		}
+		f.typ = strings.ToUpper(typ)
+		switch f.typ {
+		case "TEXT", "NUMERIC", "TAG", "GEO":
+		case "VECTOR":
+			algo, ok := r.next()
+			attrs, ok2 := r.nextCount()
+			if !ok || !ok2 {
+				return nil, errorf("Bad arguments for vector similarity %s", name)
+			}
+			f.vector = append([]string{strings.ToUpper(algo)}, attrs...)
+		default:
+			return nil, errorf("Invalid field type for field `%s`", name)
+		}
+	options:
+		for !r.done() {
+			opt := r.peek()
+			switch opt {
+			case "WEIGHT", "SEPARATOR", "PHONETIC":
+				r.next()
+				value, ok := r.next()
+				if !ok {
+					return nil, errorf("Bad arguments for %s", opt)
+				}
+				switch opt {
+				case "WEIGHT":
+					w, err := parseFloat(value)
+					if err != "" {
+						return nil, err
+					}
+					f.weight = w
+				case "SEPARATOR":
+					f.separator = value
+				case "PHONETIC":
+					f.phonetic = value
+				}
+				continue
+			case "SORTABLE":
+				f.sortable = true
+			case "UNF":
+				f.unf = true
+			case "NOSTEM":
+				f.noStem = true
+			case "NOINDEX":
+				f.noIndex = true
+			case "CASESENSITIVE":
+				f.caseSensitive = true
+			case "WITHSUFFIXTRIE":
+				f.suffixTrie = true
+			case "INDEXEMPTY", "INDEXMISSING":
+			default:
+				break options
+			}
+			r.next()
+		}
+		for _, other := range fields {
+			if other.alias == f.alias {
+				return nil, errorf("Duplicate field in schema - %s", f.alias)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, ""
+}
+
+// info returns the attribute as reported by FT.INFO
+func (f *field) info() []interface{} {
+	ret := []interface{}{"identifier", f.name, "attribute", f.alias, "type", f.typ}
+	switch f.typ {
+	case "TEXT":
+		ret = append(ret, "WEIGHT", formatFloat(f.weight))
+		if f.phonetic != "" {
+			ret = append(ret, "PHONETIC", f.phonetic)
+		}
+	case "TAG":
+		ret = append(ret, "SEPARATOR", f.separator)
+	case "VECTOR":
+		ret = append(ret, "algorithm", f.vector[0])
+		for i := 1; i+1 < len(f.vector); i += 2 {
+			ret = append(ret, strings.ToLower(f.vector[i]), f.vector[i+1])
+		}
+	}
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{f.caseSensitive, "CASESENSITIVE"},
+		{f.suffixTrie, "WITHSUFFIXTRIE"},
+		{f.sortable, "SORTABLE"},
+		{f.unf, "UNF"},
+		{f.noStem, "NOSTEM"},
+		{f.noIndex, "NOINDEX"},
+	} {
+		if flag.set {
+			ret = append(ret, flag.name)
+		}
+	}
+	return ret
+}
+
+func cmdFTInfo(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("ft.info")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	prefixes := idx.prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	definition := []interface{}{"key_type", "HASH", "prefixes", prefixes, "default_score", formatFloat(idx.defaultScore)}
+	if idx.scoreField != "" {
+		definition = append(definition, "score_field", idx.scoreField)
+	}
+	if idx.payloadField != "" {
+		definition = append(definition, "payload_field", idx.payloadField)
+	}
+	attributes := make([]interface{}, len(idx.fields))
+	for i, f := range idx.fields {
+		attributes[i] = f.info()
+	}
+
+	docs, failures := d.docs(idx)
+	terms := make(map[string]bool)
+	records := 0
+	for _, key := range docs {
+		for _, f := range idx.fields {
+			if f.typ != "TEXT" {
+				continue
+			}
+			value, _ := d.hashes[key].get(f.name)
+			for _, token := range tokenize(value) {
+				if !idx.stopwords[token] {
+					terms[token] = true
+					records++
+				}
+			}
+		}
+	}
+	maxDocID := idx.maxDocID
+	if n := int64(len(docs)); n > maxDocID {
+		maxDocID = n
+	}
+	options := make([]string, len(idx.options))
+	copy(options, idx.options)
+	return []interface{}{
+		"index_name", idx.name,
+		"index_options", options,
+		"index_definition", definition,
+		"attributes", attributes,
+		"num_docs", strconv.Itoa(len(docs)),
+		"max_doc_id", strconv.FormatInt(maxDocID, 10),
+		"num_terms", strconv.Itoa(len(terms)),
+		"num_records", strconv.Itoa(records),
+		"indexing", "0",
+		"percent_indexed", "1",
+		"hash_indexing_failures", strconv.Itoa(failures),
+	}
+}
+
+func cmdFTDropIndex(d *db, args []string) interface{} {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArity("ft.dropindex")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	if len(args) == 2 && strings.EqualFold(args[1], "DD") {
+		for _, key := range d.keys(idx) {
+			d.delete(key)
+		}
+	}
+	d.dropIndex(idx)
+	return okReply
+}
+
+// cmdFTDrop implements the deprecated FT.DROP, which deletes the documents unless KEEPDOCS is given
+func cmdFTDrop(d *db, args []string) interface{} {
+	if len(args) < 1 || len(args) > 2 {
+		return wrongArity("ft.drop")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	if len(args) == 1 || !strings.EqualFold(args[1], "KEEPDOCS") {
+		for _, key := range d.keys(idx) {
+			d.delete(key)
+		}
+	}
+	d.dropIndex(idx)
+	return okReply
+}
+
+func (d *db) dropIndex(idx *index) {
+	delete(d.indexes, idx.name)
+	for alias, name := range d.aliases {
+		if name == idx.name {
+			delete(d.aliases, alias)
+		}
+	}
+}
+
+func cmdFTList(d *db, args []string) interface{} {
+	names := make([]string, 0, len(d.indexes))
+	for name := range d.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cmdFTAliasAdd(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("ft.aliasadd")
+	}
+	if _, ok := d.aliases[args[0]]; ok {
+		return errorReply("Alias already exists")
+	}
+	return d.setAlias(args[0], args[1])
+}
+
+func cmdFTAliasUpdate(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("ft.aliasupdate")
+	}
+	return d.setAlias(args[0], args[1])
+}
+
+func (d *db) setAlias(alias, name string) interface{} {
+	idx, ok := d.indexes[name]
+	if !ok {
+		return errorReply("Unknown index name (or name is an alias itself)")
+	}
+	d.aliases[alias] = idx.name
+	return okReply
+}
+
+func cmdFTAliasDel(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("ft.aliasdel")
+	}
+	if _, ok := d.aliases[args[0]]; !ok {
+		return errorReply("Alias does not exist")
+	}
+	delete(d.aliases, args[0])
+	return okReply
+}
+
+func cmdFTAdd(d *db, args []string) interface{} {
+	if len(args) < 3 {
+		return wrongArity("ft.add")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	docID := args[1]
+	score, err := parseFloat(args[2])
+	if err != "" {
+		return err
+	}
+	r := &argReader{args: args[3:]}
+	replace, partial := false, false
+	payload, hasPayload := "", false
+	for !r.done() && r.peek() != "FIELDS" {
+		opt, _ := r.next()
+		switch strings.ToUpper(opt) {
+		case "REPLACE":
+			replace = true
+		case "PARTIAL":
+			partial = true
+		case "NOSAVE", "NOCREATE":
+		case "LANGUAGE", "IF":
+			r.next()
+		case "PAYLOAD":
+			payload, hasPayload = r.next()
+		default:
+			return errorf("Unknown keyword `%s` provided", opt)
+		}
+	}
+	if _, ok := r.next(); !ok {
+		return errorReply("No field list found")
+	}
+	values := r.args[r.pos:]
+	if len(values)%2 != 0 {
+		return errorReply("Fields must be specified in FIELD VALUE pairs")
+	}
+	if d.exists(docID) && !replace {
+		return errorReply("Document already exists")
+	}
+	if !partial {
+		d.delete(docID)
+	}
+	h, err := d.hashForWrite(docID)
+	if err != "" {
+		return err
+	}
+	d.touch(docID)
+	for i := 0; i+1 < len(values); i += 2 {
+		h.set(values[i], values[i+1])
+	}
+	d.scores[docID] = score
+	if hasPayload {
+		d.payloads[docID] = payload
+	}
+	idx.maxDocID++
+	return okReply
+}
+
+func cmdFTGet(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("ft.get")
+	}
+	if _, err := d.lookupIndex(args[0]); err != "" {
+		return err
+	}
+	if h := d.hashes[args[1]]; h != nil {
+		return h.flat()
+	}
+	return nil
+}
+
+func cmdFTMGet(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.mget")
+	}
+	if _, err := d.lookupIndex(args[0]); err != "" {
+		return err
+	}
+	ret := make([]interface{}, len(args)-1)
+	for i, key := range args[1:] {
+		if h := d.hashes[key]; h != nil {
+			ret[i] = h.flat()
+		}
+	}
+	return ret
+}
+
+// cmdFTDel deletes a document. The hash is deleted too, as FT.DEL does since RediSearch 2.0.
+func cmdFTDel(d *db, args []string) interface{} {
+	if len(args) < 2 || len(args) > 3 {
+		return wrongArity("ft.del")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	if d.hashes[args[1]] == nil || !idx.matches(args[1]) {
+		return int64(0)
+	}
+	d.delete(args[1])
+	return int64(1)
+}
+
+func cmdFTTagVals(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("ft.tagvals")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	f := idx.field(args[1])
+	if f == nil || f.typ != "TAG" {
+		return errorReply("No such field")
+	}
+	set := make(map[string]bool)
+	for _, key := range d.keys(idx) {
+		for _, tag := range f.tags(d.hashes[key]) {
+			set[tag] = true
+		}
+	}
+	tags := make([]string, 0, len(set))
+	for tag := range set {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func cmdFTExplain(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.explain")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	q, perr := parseQuery(args[1], idx, nil)
+	if perr != nil {
+		return errorf("Syntax error: %v", perr)
+	}
+	return q.String() + "\n"
+}
+
+// defaultConfig returns the FT.CONFIG options and their default values
+func defaultConfig() map[string]string {
+	return map[string]string{
+		"TIMEOUT":             "500",
+		"ON_TIMEOUT":          "return",
+		"MINPREFIX":           "2",
+		"MINSTEMLEN":          "4",
+		"MAXEXPANSIONS":       "200",
+		"MAXPREFIXEXPANSIONS": "200",
+		"MAXDOCTABLESIZE":     "1000000",
+		"MAXSEARCHRESULTS":    "1000000",
+		"MAXAGGREGATERESULTS": "unlimited",
+		"DEFAULT_DIALECT":     "1",
+		"UNION_ITERATOR_HEAP": "20",
+		"GC_POLICY":           "fork",
+		"GCSCANSIZE":          "100",
+		"NOGC":                "false",
+	}
+}
+
+func cmdFTConfig(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.config")
+	}
+	option := strings.ToUpper(args[1])
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		names := make([]string, 0)
+		for name := range d.config {
+			if option == "*" || name == option {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		ret := make([]interface{}, len(names))
+		for i, name := range names {
+			ret[i] = []interface{}{name, d.config[name]}
+		}
+		return ret
+	case "SET":
+		if len(args) != 3 {
+			return wrongArity("ft.config")
+		}
+		if _, ok := d.config[option]; !ok {
+			return errorReply("Invalid option")
+		}
+		d.config[option] = args[2]
+		return okReply
+	case "HELP":
+		return []interface{}{}
+	}
+	return errorReply("No such configuration action")
+}
+
+func cmdFTSynUpdate(d *db, args []string) interface{} {
+	if len(args) < 3 {
+		return wrongArity("ft.synupdate")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	group := args[1]
+	terms := args[2:]
+	if strings.EqualFold(terms[0], "SKIPINITIALSCAN") {
+		terms = terms[1:]
+	}
+	if idx.synonyms == nil {
+		idx.synonyms = make(map[string][]string)
+	}
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		if !containsString(idx.synonyms[term], group) {
+			idx.synonyms[term] = append(idx.synonyms[term], group)
+		}
+	}
+	return okReply
+}
+
+func cmdFTSynDump(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("ft.syndump")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	terms := make([]string, 0, len(idx.synonyms))
+	for term := range idx.synonyms {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	ret := make([]interface{}, 0, 2*len(terms))
+	for _, term := range terms {
+		ret = append(ret, term, idx.synonyms[term])
+	}
+	return ret
+}
+
+// synonymsOf returns the terms sharing a synonym group with the term
+func (idx *index) synonymsOf(term string) []string {
+	groups := idx.synonyms[term]
+	if len(groups) == 0 {
+		return nil
+	}
+	synonyms := make([]string, 0)
+	for other, otherGroups := range idx.synonyms {
+		if other == term {
+			continue
+		}
+		for _, group := range otherGroups {
+			if containsString(groups, group) {
+				synonyms = append(synonyms, other)
+				break
+			}
+		}
+	}
+	return synonyms
+}
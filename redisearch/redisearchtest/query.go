@@ -0,0 +1,931 @@
+package redisearchtest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenize splits text in lower-cased terms, on punctuation and spaces
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// tags returns the tags of a document for a tag field
+func (f *field) tags(h *hash) []string {
+	value, ok := h.get(f.name)
+	if !ok {
+		return nil
+	}
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(value, f.separator) {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		if !f.caseSensitive {
+			tag = strings.ToLower(tag)
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// docView is a document being matched against a query
+type docView struct {
+	key    string
+	hash   *hash
+	idx    *index
+	tokens map[string][]string
+}
+
+// fieldTokens returns the indexed terms of a text field, stopwords excluded
+func (v *docView) fieldTokens(f *field) []string {
+	if tokens, ok := v.tokens[f.name]; ok {
+		return tokens
+	}
+	value, _ := v.hash.get(f.name)
+	tokens := make([]string, 0)
+	for _, token := range tokenize(value) {
+		if !v.idx.stopwords[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	if v.tokens == nil {
+		v.tokens = make(map[string][]string)
+	}
+	v.tokens[f.name] = tokens
+	return tokens
+}
+
+// textFields returns the indexed text fields among the given aliases, all of them when the list is empty
+func (v *docView) textFields(aliases []string) []*field {
+	fields := make([]*field, 0)
+	for _, f := range v.idx.fields {
+		if f.typ != "TEXT" || f.noIndex {
+			continue
+		}
+		if len(aliases) > 0 && !containsString(aliases, f.alias) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func containsString(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
+// node is a parsed query node
+type node interface {
+	// match returns whether the document matches, and the score of the match
+	match(v *docView) (bool, float64)
+	// terms appends the text terms that the node looks for, used to highlight the results
+	terms(dst []*termNode) []*termNode
+	String() string
+}
+
+type unionNode struct {
+	children []node
+}
+
+func (n *unionNode) match(v *docView) (bool, float64) {
+	matched, score := false, 0.0
+	for _, child := range n.children {
+		if ok, s := child.match(v); ok {
+			matched = true
+			score += s
+		}
+	}
+	return matched, score
+}
+
+func (n *unionNode) terms(dst []*termNode) []*termNode {
+	for _, child := range n.children {
+		dst = child.terms(dst)
+	}
+	return dst
+}
+
+func (n *unionNode) String() string {
+	return groupString("UNION", n.children)
+}
+
+type intersectNode struct {
+	children []node
+}
+
+func (n *intersectNode) match(v *docView) (bool, float64) {
+	score := 0.0
+	for _, child := range n.children {
+		ok, s := child.match(v)
+		if !ok {
+			return false, 0
+		}
+		score += s
+	}
+	return true, score
+}
+
+func (n *intersectNode) terms(dst []*termNode) []*termNode {
+	for _, child := range n.children {
+		dst = child.terms(dst)
+	}
+	return dst
+}
+
+func (n *intersectNode) String() string {
+	return groupString("INTERSECT", n.children)
+}
+
+func groupString(name string, children []node) string {
+	var sb strings.Builder
+	sb.WriteString(name + " {\n")
+	for _, child := range children {
+		for _, line := range strings.Split(child.String(), "\n") {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+type notNode struct {
+	child node
+}
+
+func (n *notNode) match(v *docView) (bool, float64) {
+	ok, _ := n.child.match(v)
+	return !ok, 0
+}
+
+func (n *notNode) terms(dst []*termNode) []*termNode {
+	return dst
+}
+
+func (n *notNode) String() string {
+	return "NOT{\n  " + strings.Replace(n.child.String(), "\n", "\n  ", -1) + "\n}"
+}
+
+type optionalNode struct {
+	child node
+}
+
+func (n *optionalNode) match(v *docView) (bool, float64) {
+	_, score := n.child.match(v)
+	return true, score
+}
+
+func (n *optionalNode) terms(dst []*termNode) []*termNode {
+	return n.child.terms(dst)
+}
+
+func (n *optionalNode) String() string {
+	return "OPTIONAL{\n  " + strings.Replace(n.child.String(), "\n", "\n  ", -1) + "\n}"
+}
+
+type wildcardNode struct{}
+
+func (n *wildcardNode) match(v *docView) (bool, float64) {
+	return true, 1
+}
+
+func (n *wildcardNode) terms(dst []*termNode) []*termNode {
+	return dst
+}
+
+func (n *wildcardNode) String() string {
+	return "<WILDCARD>"
+}
+
+// termNode matches a term, a prefix or a fuzzy term in text fields
+type termNode struct {
+	fields []string
+	term   string
+	prefix bool
+	fuzzy  int
+	// synonyms are the terms of the synonym groups of the term
+	synonyms []string
+}
+
+func (n *termNode) matches(token string) bool {
+	switch {
+	case n.prefix:
+		return strings.HasPrefix(token, n.term)
+	case n.fuzzy > 0:
+		return levenshtein(token, n.term) <= n.fuzzy
+	}
+	return token == n.term || containsString(n.synonyms, token)
+}
+
+// match scores the matching terms with the weight of their field. In fields with a phonetic matcher, the
+// terms sounding like the query term score half of it.
+func (n *termNode) match(v *docView) (bool, float64) {
+	score := 0.0
+	for _, f := range v.textFields(n.fields) {
+		phonetic := f.phonetic != "" && !n.prefix && n.fuzzy == 0
+		for _, token := range v.fieldTokens(f) {
+			if n.matches(token) {
+				score += f.weight
+			} else if phonetic && soundex(token) == soundex(n.term) {
+				score += f.weight / 2
+			}
+		}
+	}
+	return score > 0, score
+}
+
+// soundex returns the Soundex code of a term. It approximates the phonetic matchers of RediSearch, which
+// use Double Metaphone.
+func soundex(term string) string {
+	const codes = "01230120022455012623010202"
+	var sb strings.Builder
+	var last byte
+	for _, r := range strings.ToUpper(term) {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		code := codes[r-'A']
+		if sb.Len() == 0 {
+			sb.WriteRune(r)
+		} else if code != '0' && code != last {
+			sb.WriteByte(code)
+		}
+		// H and W do not separate consonants with the same code
+		if r != 'H' && r != 'W' {
+			last = code
+		}
+		if sb.Len() == 4 {
+			break
+		}
+	}
+	for sb.Len() > 0 && sb.Len() < 4 {
+		sb.WriteByte('0')
+	}
+	return sb.String()
+}
+
+func (n *termNode) terms(dst []*termNode) []*termNode {
+	return append(dst, n)
+}
+
+func (n *termNode) String() string {
+	s := n.term
+	switch {
+	case n.prefix:
+		s = "PREFIX{" + s + "*}"
+	case n.fuzzy > 0:
+		s = "FUZZY{" + s + "}"
+	}
+	if len(n.fields) > 0 {
+		s = "@" + strings.Join(n.fields, "|") + ":" + s
+	}
+	return s
+}
+
+// phraseNode matches consecutive terms in a text field
+type phraseNode struct {
+	fields []string
+	words  []*termNode
+}
+
+func (n *phraseNode) match(v *docView) (bool, float64) {
+	score := 0.0
+	for _, f := range v.textFields(n.fields) {
+		tokens := v.fieldTokens(f)
+		for i := 0; i+len(n.words) <= len(tokens); i++ {
+			found := true
+			for j, word := range n.words {
+				if !word.matches(tokens[i+j]) {
+					found = false
+					break
+				}
+			}
+			if found {
+				score += f.weight * float64(len(n.words))
+			}
+		}
+	}
+	return score > 0, score
+}
+
+func (n *phraseNode) terms(dst []*termNode) []*termNode {
+	return append(dst, n.words...)
+}
+
+func (n *phraseNode) String() string {
+	children := make([]node, len(n.words))
+	for i, word := range n.words {
+		children[i] = word
+	}
+	return groupString("EXACT", children)
+}
+
+// tagNode matches any of the tags of a tag field
+type tagNode struct {
+	field *field
+	tags  []string
+}
+
+func (n *tagNode) match(v *docView) (bool, float64) {
+	for _, tag := range n.field.tags(v.hash) {
+		for _, pattern := range n.tags {
+			if matchTag(pattern, tag) {
+				return true, 1
+			}
+		}
+	}
+	return false, 0
+}
+
+// matchTag matches a tag against an exact value, or a prefix, suffix or infix pattern
+func matchTag(pattern, tag string) bool {
+	prefix := strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, "\\*")
+	suffix := strings.HasPrefix(pattern, "*") && len(pattern) > 1
+	p := pattern
+	if prefix {
+		p = p[:len(p)-1]
+	}
+	if suffix {
+		p = p[1:]
+	}
+	switch {
+	case prefix && suffix:
+		return strings.Contains(tag, p)
+	case prefix:
+		return strings.HasPrefix(tag, p)
+	case suffix:
+		return strings.HasSuffix(tag, p)
+	}
+	return tag == pattern
+}
+
+func (n *tagNode) terms(dst []*termNode) []*termNode {
+	return dst
+}
+
+func (n *tagNode) String() string {
+	return "TAG:@" + n.field.alias + " {\n  " + strings.Join(n.tags, "\n  ") + "\n}"
+}
+
+// numericNode matches the values of a numeric field within a range
+type numericNode struct {
+	field          *field
+	min, max       float64
+	exclMin, exclM bool
+}
+
+func (n *numericNode) match(v *docView) (bool, float64) {
+	value, ok := v.hash.get(n.field.name)
+	if !ok {
+		return false, 0
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return false, 0
+	}
+	if f < n.min || (n.exclMin && f == n.min) || f > n.max || (n.exclM && f == n.max) {
+		return false, 0
+	}
+	return true, 0
+}
+
+func (n *numericNode) terms(dst []*termNode) []*termNode {
+	return dst
+}
+
+func (n *numericNode) String() string {
+	lower, upper := "<=", "<="
+	if n.exclMin {
+		lower = "<"
+	}
+	if n.exclM {
+		upper = "<"
+	}
+	return fmt.Sprintf("NUMERIC {%s %s @%s %s %s}", formatFloat(n.min), lower, n.field.alias, upper, formatFloat(n.max))
+}
+
+// geoNode matches the values of a geo field within a radius
+type geoNode struct {
+	field    *field
+	lon, lat float64
+	// radius is in meters
+	radius float64
+}
+
+func (n *geoNode) match(v *docView) (bool, float64) {
+	value, ok := v.hash.get(n.field.name)
+	if !ok {
+		return false, 0
+	}
+	lon, lat, ok := parseGeo(value)
+	if !ok {
+		return false, 0
+	}
+	return haversine(lon, lat, n.lon, n.lat) <= n.radius, 0
+}
+
+// parseGeo parses the "lon,lat" value of a geo field
+func parseGeo(value string) (float64, float64, bool) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	lon, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lat, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	return lon, lat, err1 == nil && err2 == nil
+}
+
+func (n *geoNode) terms(dst []*termNode) []*termNode {
+	return dst
+}
+
+func (n *geoNode) String() string {
+	return fmt.Sprintf("GEO @%s:{%s,%s --> %s m}", n.field.alias, formatFloat(n.lon), formatFloat(n.lat), formatFloat(n.radius))
+}
+
+// haversine returns the distance in meters between two points, using the earth radius of redis
+func haversine(lon1, lat1, lon2, lat2 float64) float64 {
+	const earthRadius = 6372797.560856
+	rad := math.Pi / 180
+	u := math.Sin((lat2 - lat1) * rad / 2)
+	v := math.Sin((lon2 - lon1) * rad / 2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(u*u+math.Cos(lat1*rad)*math.Cos(lat2*rad)*v*v))
+}
+
+// geoUnits converts the distance units to meters
+var geoUnits = map[string]float64{"m": 1, "km": 1000, "mi": 1609.34, "ft": 0.3048}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// queryParser is a recursive descent parser of the query syntax
+type queryParser struct {
+	input     []rune
+	pos       int
+	idx       *index
+	params    map[string]string
+	stopwords map[string]bool
+	// fields restricts the unscoped text terms, from INFIELDS
+	fields []string
+}
+
+// syntaxError is returned for invalid queries
+type syntaxError struct {
+	offset int
+	msg    string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.msg, e.offset)
+}
+
+// parseQuery parses a query. A nil node is returned for queries made of stopwords only, which match nothing.
+func parseQuery(q string, idx *index, params map[string]string) (node, error) {
+	p := &queryParser{input: []rune(q), idx: idx, params: params, stopwords: idx.stopwords}
+	return p.parse()
+}
+
+func (p *queryParser) parse() (node, error) {
+	n, err := p.parseUnion(p.fields)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("Syntax error near %q", string(p.input[p.pos:]))
+	}
+	return n, nil
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return &syntaxError{offset: p.pos, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *queryParser) peek() rune {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *queryParser) parseUnion(fields []string) (node, error) {
+	children := make([]node, 0)
+	for {
+		n, err := p.parseIntersect(fields)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			children = append(children, n)
+		}
+		p.skipSpaces()
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &unionNode{children: children}, nil
+}
+
+func (p *queryParser) parseIntersect(fields []string) (node, error) {
+	children := make([]node, 0)
+	negative := true
+	for {
+		p.skipSpaces()
+		if c := p.peek(); c == 0 || c == ')' || c == '|' {
+			break
+		}
+		n, err := p.parseFactor(fields)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil {
+			continue
+		}
+		if _, ok := n.(*notNode); !ok {
+			negative = false
+		}
+		children = append(children, n)
+	}
+	// a purely negative query matches all the other documents
+	if negative && len(children) > 0 {
+		children = append([]node{&wildcardNode{}}, children...)
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &intersectNode{children: children}, nil
+}
+
+func (p *queryParser) parseFactor(fields []string) (node, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		n, err := p.parseFactor(fields)
+		if n == nil || err != nil {
+			return nil, err
+		}
+		return &notNode{child: n}, nil
+	case '~':
+		p.pos++
+		n, err := p.parseFactor(fields)
+		if n == nil || err != nil {
+			return nil, err
+		}
+		return &optionalNode{child: n}, nil
+	case '(':
+		p.pos++
+		n, err := p.parseUnion(fields)
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return nil, p.errorf("Syntax error: missing ')'")
+		}
+		p.pos++
+		return n, nil
+	case '@':
+		return p.parseField()
+	case '"':
+		return p.parsePhrase(fields)
+	case '*':
+		p.pos++
+		return &wildcardNode{}, nil
+	case '%':
+		return p.parseFuzzy(fields)
+	case '=':
+		return nil, p.errorf("Syntax error: vector queries are not supported")
+	}
+	return p.parseWord(fields)
+}
+
+// readWord reads a word, honouring backslash escapes
+func (p *queryParser) readWord() string {
+	var sb strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '\\' && p.pos+1 < len(p.input) {
+			sb.WriteRune(p.input[p.pos+1])
+			p.pos += 2
+			continue
+		}
+		if unicode.IsSpace(c) || strings.ContainsRune("()|{}[]\"@:*%~=", c) {
+			break
+		}
+		sb.WriteRune(c)
+		p.pos++
+	}
+	return sb.String()
+}
+
+// param substitutes a $parameter
+func (p *queryParser) param(word string) (string, error) {
+	if !strings.HasPrefix(word, "$") {
+		return word, nil
+	}
+	value, ok := p.params[word[1:]]
+	if !ok {
+		return "", p.errorf("No such parameter `%s`", word[1:])
+	}
+	return value, nil
+}
+
+func (p *queryParser) parseWord(fields []string) (node, error) {
+	start := p.pos
+	// an escaped \$ is not a parameter
+	isParam := p.peek() == '$'
+	word := p.readWord()
+	if isParam {
+		var err error
+		if word, err = p.param(word); err != nil {
+			return nil, err
+		}
+	}
+	if p.pos == start {
+		return nil, p.errorf("Syntax error near %q", string(p.input[p.pos:]))
+	}
+	if p.peek() == '*' {
+		p.pos++
+		tokens := tokenize(word)
+		if len(tokens) == 0 {
+			return nil, nil
+		}
+		return &termNode{fields: fields, term: tokens[0], prefix: true}, nil
+	}
+	return p.termsNode(fields, word)
+}
+
+// termsNode returns the node matching the terms of a word, a phrase if the word tokenizes in several terms
+func (p *queryParser) termsNode(fields []string, word string) (node, error) {
+	words := make([]*termNode, 0)
+	for _, token := range tokenize(word) {
+		if p.stopwords[token] {
+			continue
+		}
+		words = append(words, &termNode{fields: fields, term: token, synonyms: p.idx.synonymsOf(token)})
+	}
+	switch len(words) {
+	case 0:
+		return nil, nil
+	case 1:
+		return words[0], nil
+	}
+	return &phraseNode{fields: fields, words: words}, nil
+}
+
+func (p *queryParser) parsePhrase(fields []string) (node, error) {
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != '"' {
+		if p.input[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("Syntax error: unterminated phrase")
+	}
+	text := string(p.input[start:p.pos])
+	p.pos++
+	return p.termsNode(fields, text)
+}
+
+func (p *queryParser) parseFuzzy(fields []string) (node, error) {
+	distance := 0
+	for p.peek() == '%' {
+		distance++
+		p.pos++
+	}
+	if distance > 3 {
+		return nil, p.errorf("Syntax error: fuzzy distance must be at most 3")
+	}
+	word := p.readWord()
+	for i := 0; i < distance; i++ {
+		if p.peek() != '%' {
+			return nil, p.errorf("Syntax error: unterminated fuzzy term")
+		}
+		p.pos++
+	}
+	tokens := tokenize(word)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return &termNode{fields: fields, term: tokens[0], fuzzy: distance}, nil
+}
+
+// parseField parses @field:..., for any type of field
+func (p *queryParser) parseField() (node, error) {
+	p.pos++
+	names := make([]string, 0)
+	for {
+		name := p.readWord()
+		if name == "" {
+			return nil, p.errorf("Syntax error: missing field name")
+		}
+		names = append(names, name)
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if p.peek() != ':' {
+		return nil, p.errorf("Syntax error: missing ':' after field name")
+	}
+	p.pos++
+	p.skipSpaces()
+
+	fields := make([]*field, len(names))
+	for i, name := range names {
+		if fields[i] = p.idx.field(name); fields[i] == nil {
+			return nil, p.errorf("Unknown field `%s`", name)
+		}
+	}
+	switch p.peek() {
+	case '{':
+		if len(fields) != 1 || fields[0].typ != "TAG" {
+			return nil, p.errorf("Syntax error: tag query on a non tag field")
+		}
+		return p.parseTags(fields[0])
+	case '[':
+		if len(fields) != 1 || (fields[0].typ != "NUMERIC" && fields[0].typ != "GEO") {
+			return nil, p.errorf("Syntax error: range query on a non numeric field")
+		}
+		return p.parseRange(fields[0])
+	}
+	for _, f := range fields {
+		if f.typ != "TEXT" {
+			return nil, p.errorf("Syntax error: text query on a non text field `%s`", f.alias)
+		}
+	}
+	return p.parseFactor(names)
+}
+
+func (p *queryParser) parseTags(f *field) (node, error) {
+	p.pos++
+	tags := make([]string, 0)
+	var sb strings.Builder
+	isParam := false
+	flush := func() error {
+		tag := strings.TrimSpace(sb.String())
+		sb.Reset()
+		if isParam {
+			isParam = false
+			var err error
+			if tag, err = p.param(tag); err != nil {
+				return err
+			}
+		}
+		if tag == "" {
+			return nil
+		}
+		if !f.caseSensitive {
+			tag = strings.ToLower(tag)
+		}
+		tags = append(tags, tag)
+		return nil
+	}
+	for {
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("Syntax error: unterminated tag list")
+		}
+		c := p.input[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.input):
+			sb.WriteRune(p.input[p.pos])
+			p.pos++
+		case c == '|' || c == '}':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			if c == '}' {
+				return &tagNode{field: f, tags: tags}, nil
+			}
+		default:
+			if c == '$' && strings.TrimSpace(sb.String()) == "" {
+				isParam = true
+			}
+			sb.WriteRune(c)
+		}
+	}
+}
+
+// parseRange parses [min max] for numeric fields, and [lon lat radius unit] for geo fields
+func (p *queryParser) parseRange(f *field) (node, error) {
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != ']' {
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("Syntax error: unterminated range")
+	}
+	parts := strings.FieldsFunc(string(p.input[start:p.pos]), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	p.pos++
+	for i, part := range parts {
+		exclusive := strings.HasPrefix(part, "(")
+		value, err := p.param(strings.TrimPrefix(part, "("))
+		if err != nil {
+			return nil, err
+		}
+		if exclusive {
+			value = "(" + value
+		}
+		parts[i] = value
+	}
+	if f.typ == "GEO" {
+		if len(parts) != 4 {
+			return nil, p.errorf("Syntax error: invalid geo range")
+		}
+		values := make([]float64, 3)
+		for i := range values {
+			v, err := strconv.ParseFloat(parts[i], 64)
+			if err != nil {
+				return nil, p.errorf("Syntax error: invalid geo range")
+			}
+			values[i] = v
+		}
+		unit, ok := geoUnits[strings.ToLower(parts[3])]
+		if !ok {
+			return nil, p.errorf("Syntax error: invalid geo unit")
+		}
+		return &geoNode{field: f, lon: values[0], lat: values[1], radius: values[2] * unit}, nil
+	}
+	if len(parts) != 2 {
+		return nil, p.errorf("Syntax error: invalid numeric range")
+	}
+	n := &numericNode{field: f}
+	var ok1, ok2 bool
+	n.min, n.exclMin, ok1 = parseRangeBound(parts[0])
+	n.max, n.exclM, ok2 = parseRangeBound(parts[1])
+	if !ok1 || !ok2 {
+		return nil, p.errorf("Syntax error: invalid numeric range")
+	}
+	return n, nil
+}
+
+// parseRangeBound parses a bound of a numeric range: a number or inf, prefixed with ( when exclusive
+func parseRangeBound(s string) (float64, bool, bool) {
+	exclusive := strings.HasPrefix(s, "(")
+	s = strings.TrimPrefix(s, "(")
+	switch strings.ToLower(s) {
+	case "inf", "+inf":
+		return math.Inf(1), exclusive, true
+	case "-inf":
+		return math.Inf(-1), exclusive, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, exclusive, err == nil
+}
@@ -0,0 +1,488 @@
+package redisearchtest
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// searchRequest holds the parsed options of FT.SEARCH
+type searchRequest struct {
+	query        string
+	noContent    bool
+	noStopwords  bool
+	withScores   bool
+	withPayloads bool
+	withSortKeys bool
+	offset       int
+	num          int
+	filters      []node
+	inKeys       map[string]bool
+	inFields     []string
+	returnFields [][2]string
+	hasReturn    bool
+	sortBy       string
+	sortAsc      bool
+	highlight    *highlightOptions
+	summarize    *summarizeOptions
+	params       map[string]string
+}
+
+type highlightOptions struct {
+	fields      []string
+	open, close string
+}
+
+type summarizeOptions struct {
+	fields    []string
+	frags     int
+	length    int
+	separator string
+}
+
+// searchResult is a matching document
+type searchResult struct {
+	key   string
+	score float64
+}
+
+func cmdFTSearch(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.search")
+	}
+	idx, err := d.lookupIndex(args[0])
+	if err != "" {
+		return err
+	}
+	req, err := parseSearchRequest(idx, args[1], args[2:])
+	if err != "" {
+		return err
+	}
+	if req.highlight != nil || req.summarize != nil {
+		for _, option := range []string{"NOOFFSETS", "NOHL"} {
+			if idx.hasOption(option) {
+				return errorf("Cannot use highlight/summarize because %s was specified at index level", option)
+			}
+		}
+	}
+
+	p := &queryParser{input: []rune(req.query), idx: idx, params: req.params, stopwords: idx.stopwords, fields: req.inFields}
+	if req.noStopwords {
+		p.stopwords = nil
+	}
+	root, perr := p.parse()
+	if perr != nil {
+		return errorf("Syntax error: %v", perr)
+	}
+
+	results := make([]searchResult, 0)
+	if root != nil {
+	docs:
+		for _, key := range d.keys(idx) {
+			if req.inKeys != nil && !req.inKeys[key] {
+				continue
+			}
+			v := &docView{key: key, hash: d.hashes[key], idx: idx}
+			ok, score := root.match(v)
+			if !ok {
+				continue
+			}
+			for _, filter := range req.filters {
+				if ok, _ := filter.match(v); !ok {
+					continue docs
+				}
+			}
+			results = append(results, searchResult{key: key, score: score * d.docScore(idx, key)})
+		}
+	}
+	d.sortResults(idx, req, results)
+
+	reply := []interface{}{int64(len(results))}
+	if req.offset > len(results) {
+		req.offset = len(results)
+	}
+	end := req.offset + req.num
+	if end > len(results) {
+		end = len(results)
+	}
+	var terms []*termNode
+	if root != nil {
+		terms = root.terms(nil)
+	}
+	for _, res := range results[req.offset:end] {
+		reply = append(reply, res.key)
+		if req.withScores {
+			reply = append(reply, formatFloat(res.score))
+		}
+		if req.withPayloads {
+			if payload, ok := d.docPayload(idx, res.key); ok {
+				reply = append(reply, payload)
+			} else {
+				reply = append(reply, nil)
+			}
+		}
+		if req.withSortKeys {
+			reply = append(reply, d.sortKey(idx, req.sortBy, res.key))
+		}
+		if !req.noContent {
+			reply = append(reply, d.content(idx, req, res.key, terms))
+		}
+	}
+	return reply
+}
+
+// parseSearchRequest parses the arguments of FT.SEARCH following the query
+func parseSearchRequest(idx *index, query string, args []string) (*searchRequest, errorReply) {
+	req := &searchRequest{query: query, num: 10, sortAsc: true}
+	r := &argReader{args: args}
+	for !r.done() {
+		opt, _ := r.next()
+		opt = strings.ToUpper(opt)
+		ok := true
+		switch opt {
+		case "NOCONTENT":
+			req.noContent = true
+		case "NOSTOPWORDS":
+			req.noStopwords = true
+		case "WITHSCORES":
+			req.withScores = true
+		case "WITHPAYLOADS":
+			req.withPayloads = true
+		case "WITHSORTKEYS":
+			req.withSortKeys = true
+		case "VERBATIM", "INORDER", "EXPLAINSCORE":
+		case "SLOP", "LANGUAGE", "EXPANDER", "SCORER", "PAYLOAD", "TIMEOUT", "DIALECT":
+			_, ok = r.next()
+		case "LIMIT":
+			var values []string
+			if values, ok = r.nextN(2); ok {
+				offset, err1 := strconv.Atoi(values[0])
+				num, err2 := strconv.Atoi(values[1])
+				if err1 != nil || err2 != nil || offset < 0 || num < 0 {
+					return nil, errorReply("Bad arguments for LIMIT")
+				}
+				req.offset, req.num = offset, num
+			}
+		case "FILTER":
+			var values []string
+			if values, ok = r.nextN(3); ok {
+				f := idx.field(values[0])
+				if f == nil || f.typ != "NUMERIC" {
+					return nil, errorf("Unknown field `%s`", values[0])
+				}
+				n := &numericNode{field: f}
+				var ok1, ok2 bool
+				n.min, n.exclMin, ok1 = parseRangeBound(values[1])
+				n.max, n.exclM, ok2 = parseRangeBound(values[2])
+				if !ok1 || !ok2 {
+					return nil, errorReply("Bad upper or lower range for numeric filter")
+				}
+				req.filters = append(req.filters, n)
+			}
+		case "GEOFILTER":
+			var values []string
+			if values, ok = r.nextN(5); ok {
+				f := idx.field(values[0])
+				if f == nil || f.typ != "GEO" {
+					return nil, errorf("Unknown field `%s`", values[0])
+				}
+				n := &geoNode{field: f}
+				var err1, err2, err3 error
+				n.lon, err1 = strconv.ParseFloat(values[1], 64)
+				n.lat, err2 = strconv.ParseFloat(values[2], 64)
+				n.radius, err3 = strconv.ParseFloat(values[3], 64)
+				unit, known := geoUnits[strings.ToLower(values[4])]
+				if err1 != nil || err2 != nil || err3 != nil || !known {
+					return nil, errorReply("Bad arguments for GEOFILTER")
+				}
+				n.radius *= unit
+				req.filters = append(req.filters, n)
+			}
+		case "INKEYS":
+			var keys []string
+			if keys, ok = r.nextCount(); ok {
+				req.inKeys = make(map[string]bool, len(keys))
+				for _, key := range keys {
+					req.inKeys[key] = true
+				}
+			}
+		case "INFIELDS":
+			req.inFields, ok = r.nextCount()
+		case "RETURN":
+			var fields []string
+			if fields, ok = r.nextCount(); ok {
+				req.hasReturn = true
+				for i := 0; i < len(fields); i++ {
+					name, as := fields[i], fields[i]
+					if i+2 < len(fields) && strings.EqualFold(fields[i+1], "AS") {
+						as = fields[i+2]
+						i += 2
+					}
+					req.returnFields = append(req.returnFields, [2]string{name, as})
+				}
+			}
+		case "SORTBY":
+			if req.sortBy, ok = r.next(); ok {
+				switch r.peek() {
+				case "ASC":
+					r.next()
+				case "DESC":
+					r.next()
+					req.sortAsc = false
+				}
+			}
+		case "HIGHLIGHT":
+			req.highlight = &highlightOptions{open: "<b>", close: "</b>"}
+			for r.peek() == "FIELDS" || r.peek() == "TAGS" {
+				sub, _ := r.next()
+				if strings.EqualFold(sub, "FIELDS") {
+					req.highlight.fields, ok = r.nextCount()
+				} else {
+					var tags []string
+					if tags, ok = r.nextN(2); ok {
+						req.highlight.open, req.highlight.close = tags[0], tags[1]
+					}
+				}
+				if !ok {
+					break
+				}
+			}
+		case "SUMMARIZE":
+			req.summarize = &summarizeOptions{frags: 3, length: 20, separator: "... "}
+		summarize:
+			for ok {
+				switch r.peek() {
+				case "FIELDS":
+					r.next()
+					req.summarize.fields, ok = r.nextCount()
+				case "FRAGS", "LEN":
+					sub, _ := r.next()
+					s, _ := r.next()
+					n, err := strconv.Atoi(s)
+					if err != nil || n <= 0 {
+						return nil, errorf("Bad arguments for %s", sub)
+					}
+					if strings.EqualFold(sub, "FRAGS") {
+						req.summarize.frags = n
+					} else {
+						req.summarize.length = n
+					}
+				case "SEPARATOR":
+					r.next()
+					req.summarize.separator, ok = r.next()
+				default:
+					break summarize
+				}
+			}
+		case "PARAMS":
+			var values []string
+			if values, ok = r.nextCount(); ok {
+				req.params = make(map[string]string, len(values)/2)
+				for i := 0; i+1 < len(values); i += 2 {
+					req.params[values[i]] = values[i+1]
+				}
+			}
+		default:
+			return nil, errorf("Unknown argument `%s`", opt)
+		}
+		if !ok {
+			return nil, errorf("Bad arguments for %s", opt)
+		}
+	}
+	if req.sortBy != "" && idx.field(req.sortBy) == nil {
+		return nil, errorf("Property `%s` not loaded nor in schema", req.sortBy)
+	}
+	return req, ""
+}
+
+// sortResults sorts by the SORTBY field, or by descending score
+func (d *db) sortResults(idx *index, req *searchRequest, results []searchResult) {
+	if req.sortBy == "" {
+		sort.SliceStable(results, func(i, j int) bool {
+			if results[i].score != results[j].score {
+				return results[i].score > results[j].score
+			}
+			return results[i].key < results[j].key
+		})
+		return
+	}
+	f := idx.field(req.sortBy)
+	numeric := f.typ == "NUMERIC"
+	values := make(map[string]string, len(results))
+	for _, res := range results {
+		if value, ok := d.hashes[res.key].get(f.name); ok {
+			values[res.key] = value
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, okA := values[results[i].key]
+		b, okB := values[results[j].key]
+		// documents without the field come last
+		if !okA || !okB {
+			if okA != okB {
+				return okA
+			}
+			return results[i].key < results[j].key
+		}
+		var less, equal bool
+		if numeric {
+			fa, _ := strconv.ParseFloat(a, 64)
+			fb, _ := strconv.ParseFloat(b, 64)
+			less, equal = fa < fb, fa == fb
+		} else {
+			a, b = strings.ToLower(a), strings.ToLower(b)
+			less, equal = a < b, a == b
+		}
+		if equal {
+			return results[i].key < results[j].key
+		}
+		return less == req.sortAsc
+	})
+}
+
+// sortKey returns the sort key reported by WITHSORTKEYS
+func (d *db) sortKey(idx *index, sortBy, key string) interface{} {
+	if sortBy == "" {
+		return nil
+	}
+	f := idx.field(sortBy)
+	value, ok := d.hashes[key].get(f.name)
+	if !ok {
+		return nil
+	}
+	if f.typ == "NUMERIC" {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil
+		}
+		return "#" + formatFloat(n)
+	}
+	return "$" + strings.ToLower(value)
+}
+
+// content returns the fields of a result, as selected by RETURN, with highlighting and summarization
+func (d *db) content(idx *index, req *searchRequest, key string, terms []*termNode) []interface{} {
+	h := d.hashes[key]
+	var fields [][2]string
+	if req.hasReturn {
+		for _, ret := range req.returnFields {
+			name := ret[0]
+			if f := idx.field(name); f != nil {
+				name = f.name
+			}
+			fields = append(fields, [2]string{name, ret[1]})
+		}
+	} else {
+		for _, name := range h.fields {
+			fields = append(fields, [2]string{name, name})
+		}
+	}
+	ret := make([]interface{}, 0, 2*len(fields))
+	for _, pair := range fields {
+		value, ok := h.get(pair[0])
+		if !ok {
+			continue
+		}
+		f := idx.fieldByName(pair[0])
+		if f != nil && f.typ == "TEXT" {
+			if req.summarize != nil && (len(req.summarize.fields) == 0 || containsString(req.summarize.fields, f.alias)) {
+				value = summarize(value, terms, req.summarize)
+			}
+			if req.highlight != nil && (len(req.highlight.fields) == 0 || containsString(req.highlight.fields, f.alias)) {
+				value = highlight(value, terms, req.highlight.open, req.highlight.close)
+			}
+		}
+		ret = append(ret, pair[1], value)
+	}
+	return ret
+}
+
+// fieldByName returns the field indexing the given hash field
+func (idx *index) fieldByName(name string) *field {
+	for _, f := range idx.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// wordSpans returns the start and end offsets of the words of a text, as split by tokenize
+func wordSpans(text string) [][2]int {
+	spans := make([][2]int, 0)
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+func matchesAny(terms []*termNode, word string) bool {
+	word = strings.ToLower(word)
+	for _, term := range terms {
+		if term.matches(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight wraps the words matching the query terms with the open and close tags
+func highlight(text string, terms []*termNode, open, close string) string {
+	var sb strings.Builder
+	last := 0
+	for _, span := range wordSpans(text) {
+		if !matchesAny(terms, text[span[0]:span[1]]) {
+			continue
+		}
+		sb.WriteString(text[last:span[0]])
+		sb.WriteString(open + text[span[0]:span[1]] + close)
+		last = span[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// summarize returns fragments of the text around the words matching the query terms
+func summarize(text string, terms []*termNode, opts *summarizeOptions) string {
+	spans := wordSpans(text)
+	var sb strings.Builder
+	frags := 0
+	next := 0
+	for i := 0; i < len(spans) && frags < opts.frags; i++ {
+		if i < next || !matchesAny(terms, text[spans[i][0]:spans[i][1]]) {
+			continue
+		}
+		start := i - opts.length/2
+		if start < next {
+			start = next
+		}
+		end := start + opts.length
+		if end > len(spans) {
+			end = len(spans)
+		}
+		sb.WriteString(text[spans[start][0]:spans[end-1][1]])
+		sb.WriteString(opts.separator)
+		frags++
+		next = end
+	}
+	if frags == 0 {
+		end := int(math.Min(float64(opts.length), float64(len(spans))))
+		if end == 0 {
+			return text
+		}
+		return text[:spans[end-1][1]] + opts.separator
+	}
+	return sb.String()
+}
@@ -0,0 +1,281 @@
+// Package redisearchtest provides an in-memory RediSearch server for unit tests.
+//
+// The server speaks RESP2 on a local port, so that the Client, Autocompleter and Admin of the redisearch
+// package (or any other redis driver) can be pointed at it:
+//
+//	srv := redisearchtest.NewServer()
+//	defer srv.Close()
+//	c := redisearch.NewClient(srv.Addr, "idx")
+//
+// It implements a subset of the redis and RediSearch commands: the hash, string and key commands (SCAN included)
+// used to store documents, FT.CREATE over hashes with prefixes, FT.ADD, FT.GET, FT.MGET, FT.DEL, FT.SEARCH with terms,
+// prefixes, phrases, tags, numeric and geo ranges, negation, unions, LIMIT, SORTBY, RETURN, FILTER, GEOFILTER,
+// INKEYS, INFIELDS, HIGHLIGHT and PARAMS, FT.INFO, FT.DROPINDEX, FT._LIST, FT.ALIAS*, FT.TAGVALS, FT.CONFIG,
+// FT.SYNUPDATE, FT.SYNDUMP, FT.SUG* and FT.DICT*. Text is tokenized on punctuation and lower-cased, without
+// stemming; scores are weighted term frequencies, and phonetic matching is approximated with Soundex.
+// FT.AGGREGATE, FT.SPELLCHECK, JSON indexes, vector queries and scripting are not supported: unknown commands
+// reply with an error, as redis does.
+package redisearchtest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory RediSearch server listening on a local port
+type Server struct {
+	// Addr is the host:port the server listens on
+	Addr string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	db     *db
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// NewServer starts a server on a random local port. It panics if the server cannot listen, like httptest.NewServer.
+func NewServer() *Server {
+	s, err := StartServer("127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redisearchtest: failed to listen on a port: %v", err))
+	}
+	return s
+}
+
+// StartServer starts a server listening on the given address
+func StartServer(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		listener: listener,
+		db:       newDB(),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server and closes its connections
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// FlushAll deletes all the keys, indexes, suggestions and dictionaries
+func (s *Server) FlushAll() {
+	s.mu.Lock()
+	s.db = newDB()
+	s.mu.Unlock()
+}
+
+// Do runs a command on the server without a connection, and returns its reply: nil, int64, string,
+// []interface{}, or an error for error replies
+func (s *Server) Do(args ...string) (interface{}, error) {
+	reply := s.exec(args)
+	switch r := reply.(type) {
+	case errorReply:
+		return nil, errors.New(string(r))
+	case statusReply:
+		return string(r), nil
+	}
+	return reply, nil
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	br := bufio.NewReader(conn)
+	bw := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(br)
+		if err != nil {
+			if _, ok := err.(protocolError); ok {
+				writeReply(bw, errorReply("ERR Protocol error: "+err.Error()))
+				bw.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		quit := strings.EqualFold(args[0], "QUIT")
+		writeReply(bw, s.exec(args))
+		// flush once all the pipelined commands have been processed
+		if br.Buffered() == 0 || quit {
+			if err := bw.Flush(); err != nil || quit {
+				return
+			}
+		}
+	}
+}
+
+// exec runs a command under the server lock
+func (s *Server) exec(args []string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := strings.ToUpper(args[0])
+	handler, ok := commands[name]
+	if !ok {
+		var sb strings.Builder
+		for _, arg := range args[1:] {
+			sb.WriteString("`" + arg + "`, ")
+		}
+		return errorf("ERR unknown command `%s`, with args beginning with: %s", args[0], sb.String())
+	}
+	return handler(s.db, args[1:])
+}
+
+// statusReply is a simple string reply
+type statusReply string
+
+// errorReply is an error reply
+type errorReply string
+
+var okReply = statusReply("OK")
+
+func errorf(format string, args ...interface{}) errorReply {
+	return errorReply(fmt.Sprintf(format, args...))
+}
+
+func wrongArity(name string) errorReply {
+	return errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(name))
+}
+
+type protocolError string
+
+func (e protocolError) Error() string {
+	return string(e)
+}
+
+// readCommand reads a command sent as a RESP array of bulk strings, or as an inline command
+func readCommand(br *bufio.Reader) ([]string, error) {
+	line, err := readLine(br)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 {
+		return nil, protocolError("invalid multibulk length")
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(br)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, protocolError(fmt.Sprintf("expected '$', got '%s'", line))
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, protocolError("invalid bulk length")
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// writeReply encodes a reply in RESP2
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch r := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case statusReply:
+		w.WriteString("+" + string(r) + "\r\n")
+	case errorReply:
+		w.WriteString("-" + strings.NewReplacer("\r", " ", "\n", " ").Replace(string(r)) + "\r\n")
+	case int:
+		w.WriteString(":" + strconv.Itoa(r) + "\r\n")
+	case int64:
+		w.WriteString(":" + strconv.FormatInt(r, 10) + "\r\n")
+	case float64:
+		writeBulk(w, formatFloat(r))
+	case string:
+		writeBulk(w, r)
+	case []string:
+		w.WriteString("*" + strconv.Itoa(len(r)) + "\r\n")
+		for _, s := range r {
+			writeBulk(w, s)
+		}
+	case []interface{}:
+		w.WriteString("*" + strconv.Itoa(len(r)) + "\r\n")
+		for _, elem := range r {
+			writeReply(w, elem)
+		}
+	default:
+		writeBulk(w, fmt.Sprint(r))
+	}
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	w.WriteString("$" + strconv.Itoa(len(s)) + "\r\n")
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,239 @@
+package redisearchtest_test
+
+import (
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch"
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchtest"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func createIndex(t *testing.T, srv *redisearchtest.Server) *redisearch.Client {
+	c := redisearch.NewClient(srv.Addr, "products")
+	sc := redisearch.NewSchema(redisearch.DefaultOptions).
+		AddField(redisearch.NewTextFieldOptions("name", redisearch.TextFieldOptions{Weight: 2})).
+		AddField(redisearch.NewTextField("description")).
+		AddField(redisearch.NewTagField("tags")).
+		AddField(redisearch.NewSortableNumericField("price")).
+		AddField(redisearch.NewGeoField("location"))
+	assert.Nil(t, c.CreateIndexWithIndexDefinition(sc, redisearch.NewIndexDefinition().AddPrefix("product:")))
+
+	for _, doc := range [][]string{
+		{"product:1", "name", "Red running shoes", "description", "Light shoes for the road", "tags", "Sport,Shoes", "price", "80", "location", "-0.1276,51.5072"},
+		{"product:2", "name", "Blue hiking boots", "description", "Waterproof boots, great for running in the mud", "tags", "Outdoor,Shoes", "price", "120", "location", "2.3522,48.8566"},
+		{"product:3", "name", "Running socks", "description", "A pair of socks", "tags", "Sport", "price", "10", "location", "-0.1276,51.5072"},
+		{"other:1", "name", "Running man", "price", "1"},
+	} {
+		_, err := srv.Do(append([]string{"HSET"}, doc...)...)
+		assert.Nil(t, err)
+	}
+	return c
+}
+
+func searchIds(t *testing.T, c *redisearch.Client, q *redisearch.Query) []string {
+	docs, total, err := c.Search(q)
+	assert.Nil(t, err)
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.Id)
+	}
+	assert.True(t, total >= len(ids))
+	return ids
+}
+
+func TestServer_Search(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	c := createIndex(t, srv)
+
+	// the name field has a higher weight
+	assert.Equal(t, []string{"product:1", "product:3", "product:2"}, searchIds(t, c, redisearch.NewQuery("running")))
+	assert.Equal(t, []string{"product:1", "product:2"}, searchIds(t, c, redisearch.NewQuery("shoes|boots")))
+	assert.Equal(t, []string{"product:3"}, searchIds(t, c, redisearch.NewQuery("running -shoes -@description:mud")))
+	assert.Equal(t, []string{"product:1"}, searchIds(t, c, redisearch.NewQuery(`"running shoes"`)))
+	assert.Equal(t, []string{"product:2"}, searchIds(t, c, redisearch.NewQuery("hik*")))
+	assert.Equal(t, []string{"product:3"}, searchIds(t, c, redisearch.NewQuery("%sock%")))
+	assert.Equal(t, []string{"product:1", "product:2"}, searchIds(t, c, redisearch.NewQuery("@tags:{shoes}").SetSortBy("price", true)))
+	assert.Equal(t, []string{"product:1"}, searchIds(t, c, redisearch.NewQuery("@tags:{sport | outdoor} @price:[(10 (120]")))
+	assert.Equal(t, []string{"product:2", "product:3"}, searchIds(t, c, redisearch.NewQuery("@tags:{outdoor} | @price:[-inf (80]").SetSortBy("price", false)))
+	assert.Equal(t, []string{"product:1", "product:3"}, searchIds(t, c, redisearch.NewQuery("@location:[-0.12 51.5 10 km]").SetSortBy("price", false)))
+	assert.Equal(t, []string{"product:2"}, searchIds(t, c, redisearch.NewQuery("@tags:{$tag}").
+		SetParams(map[string]interface{}{"tag": "outdoor"}).SetDialect(2)))
+	// an escaped $ is not a parameter
+	assert.Empty(t, searchIds(t, c, redisearch.NewQuery(`@tags:{\$tag} | \$tag`).SetDialect(2)))
+
+	q := redisearch.NewQuery("*").
+		SetSortBy("price", true).
+		Limit(1, 1).
+		SetReturnFields("name").
+		AddFilter(redisearch.Filter{Field: "price", Options: redisearch.NumericFilterOptions{Min: 10, Max: 100}})
+	docs, total, err := c.Search(q)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "product:1", docs[0].Id)
+	assert.Equal(t, map[string]interface{}{"name": "Red running shoes"}, docs[0].Properties)
+
+	docs, _, err = c.Search(redisearch.NewQuery("socks").Highlight([]string{"description"}, "[", "]"))
+	assert.Nil(t, err)
+	assert.Equal(t, "A pair of [socks]", docs[0].Properties["description"])
+
+	_, _, err = c.Search(redisearch.NewQuery("@unknown:foo"))
+	assert.NotNil(t, err)
+}
+
+func TestServer_Info(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	c := createIndex(t, srv)
+
+	info, err := c.Info()
+	assert.Nil(t, err)
+	assert.Equal(t, "products", info.Name)
+	assert.Equal(t, uint64(3), info.DocCount)
+	assert.Len(t, info.Schema.Fields, 5)
+	assert.Equal(t, redisearch.TagField, info.Schema.Fields[2].Type)
+
+	// documents with invalid numeric values are not indexed
+	_, err = srv.Do("HSET", "product:4", "name", "Broken", "price", "free")
+	assert.Nil(t, err)
+	info, err = c.Info()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), info.DocCount)
+	assert.Equal(t, uint64(1), info.HashIndexingFailures)
+
+	indexes, err := c.List()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"products"}, indexes)
+
+	assert.Nil(t, c.DropIndex(true))
+	_, err = c.Info()
+	assert.Equal(t, redis.Error("Unknown Index name"), err)
+	n, err := srv.Do("EXISTS", "product:1", "other:1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestServer_Documents(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	c := redisearch.NewClient(srv.Addr, "docs")
+	assert.Nil(t, c.CreateIndex(redisearch.NewSchema(redisearch.DefaultOptions).AddField(redisearch.NewTextField("body"))))
+
+	doc := redisearch.NewDocument("doc1", 0.5).Set("body", "hello world")
+	doc.SetPayload([]byte("payload"))
+	assert.Nil(t, c.Index(doc))
+	assert.NotNil(t, c.Index(doc))
+
+	docs, _, err := c.Search(redisearch.NewQuery("hello").SetFlags(redisearch.QueryWithScores | redisearch.QueryWithPayloads))
+	assert.Nil(t, err)
+	assert.Equal(t, float32(0.5), docs[0].Score)
+	assert.Equal(t, []byte("payload"), docs[0].Payload)
+
+	got, err := c.Get("doc1")
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", got.Properties["body"])
+
+	assert.Nil(t, c.DeleteDocument("doc1"))
+	got, err = c.Get("doc1")
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+}
+
+func TestServer_Suggestions(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	a := redisearch.NewAutocompleter(srv.Addr, "ac")
+
+	assert.Nil(t, a.AddTerms(
+		redisearch.Suggestion{Term: "hello", Score: 1},
+		redisearch.Suggestion{Term: "help", Score: 2, Payload: "p"},
+		redisearch.Suggestion{Term: "world", Score: 1},
+	))
+	n, err := a.Length()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+
+	sugs, err := a.SuggestOpts("hel", redisearch.SuggestOptions{Num: 5, WithScores: true, WithPayloads: true})
+	assert.Nil(t, err)
+	assert.Len(t, sugs, 2)
+	assert.Equal(t, "help", sugs[0].Term)
+	assert.Equal(t, "p", sugs[0].Payload)
+
+	sugs, err = a.SuggestOpts("wrld", redisearch.SuggestOptions{Num: 5, Fuzzy: true})
+	assert.Nil(t, err)
+	assert.Len(t, sugs, 1)
+
+	assert.Nil(t, a.DeleteTerms(redisearch.Suggestion{Term: "world"}))
+	n, err = a.Length()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestServer_Dictionaries(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	c := redisearch.NewClient(srv.Addr, "idx")
+
+	n, err := c.DictAdd("dict", []string{"foo", "bar", "foo"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	terms, err := c.DictDump("dict")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bar", "foo"}, terms)
+	n, err = c.DictDel("dict", []string{"foo", "baz"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestServer_Pipeline(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	conn, err := redis.Dial("tcp", srv.Addr)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	conn.Send("SET", "key", "value")
+	conn.Send("GET", "key")
+	conn.Send("HGET", "key", "field")
+	conn.Send("NOSUCHCOMMAND")
+	assert.Nil(t, conn.Flush())
+
+	reply, err := redis.String(conn.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	reply, err = redis.String(conn.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, "value", reply)
+	_, err = conn.Receive()
+	assert.Contains(t, err.Error(), "WRONGTYPE")
+	_, err = conn.Receive()
+	assert.Contains(t, err.Error(), "unknown command")
+
+	srv.FlushAll()
+	n, err := redis.Int(conn.Do("EXISTS", "key"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestServer_Scan(t *testing.T) {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	createIndex(t, srv)
+	conn, err := redis.Dial("tcp", srv.Addr)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	keys := make([]string, 0)
+	cursor := "0"
+	for {
+		reply, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", "product:*", "COUNT", "2", "TYPE", "hash"))
+		assert.Nil(t, err)
+		page, _ := redis.Strings(reply[1], nil)
+		keys = append(keys, page...)
+		if cursor, _ = redis.String(reply[0], nil); cursor == "0" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"product:1", "product:2", "product:3"}, keys)
+}
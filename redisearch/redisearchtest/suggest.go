@@ -0,0 +1,229 @@
+package redisearchtest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// suggestion is an entry of an autocomplete dictionary
+type suggestion struct {
+	term       string
+	score      float64
+	payload    string
+	hasPayload bool
+}
+
+func cmdFTSugAdd(d *db, args []string) interface{} {
+	if len(args) < 3 {
+		return wrongArity("ft.sugadd")
+	}
+	key, term := args[0], args[1]
+	score, err := parseFloat(args[2])
+	if err != "" {
+		return err
+	}
+	incr := false
+	payload, hasPayload := "", false
+	r := &argReader{args: args[3:]}
+	for !r.done() {
+		opt, _ := r.next()
+		switch strings.ToUpper(opt) {
+		case "INCR":
+			incr = true
+		case "PAYLOAD":
+			var ok bool
+			if payload, ok = r.next(); !ok {
+				return errorReply("Bad arguments for PAYLOAD")
+			}
+			hasPayload = true
+		default:
+			return errorf("Unknown argument `%s`", opt)
+		}
+	}
+	sugs, ok := d.suggestions[key]
+	if !ok {
+		if d.exists(key) {
+			return wrongType
+		}
+		sugs = make(map[string]*suggestion)
+		d.suggestions[key] = sugs
+	}
+	sug, ok := sugs[term]
+	if !ok {
+		sug = &suggestion{term: term}
+		sugs[term] = sug
+	}
+	if incr {
+		sug.score += score
+	} else {
+		sug.score = score
+	}
+	if hasPayload {
+		sug.payload, sug.hasPayload = payload, true
+	}
+	return int64(len(sugs))
+}
+
+func cmdFTSugGet(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.sugget")
+	}
+	key, prefix := args[0], strings.ToLower(args[1])
+	fuzzy, withScores, withPayloads := false, false, false
+	max := 5
+	r := &argReader{args: args[2:]}
+	for !r.done() {
+		opt, _ := r.next()
+		switch strings.ToUpper(opt) {
+		case "FUZZY":
+			fuzzy = true
+		case "WITHSCORES":
+			withScores = true
+		case "WITHPAYLOADS":
+			withPayloads = true
+		case "MAX":
+			s, _ := r.next()
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				return errorReply("Invalid value for MAX")
+			}
+			max = n
+		default:
+			return errorf("Unknown argument `%s`", opt)
+		}
+	}
+	sugs, ok := d.suggestions[key]
+	if !ok {
+		if d.exists(key) {
+			return wrongType
+		}
+		return nil
+	}
+	matches := make([]*suggestion, 0)
+	for _, sug := range sugs {
+		if matchesPrefix(strings.ToLower(sug.term), prefix, fuzzy) {
+			matches = append(matches, sug)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].term < matches[j].term
+	})
+	if len(matches) > max {
+		matches = matches[:max]
+	}
+	ret := make([]interface{}, 0)
+	for _, sug := range matches {
+		ret = append(ret, sug.term)
+		if withScores {
+			ret = append(ret, formatFloat(sug.score))
+		}
+		if withPayloads {
+			if sug.hasPayload {
+				ret = append(ret, sug.payload)
+			} else {
+				ret = append(ret, nil)
+			}
+		}
+	}
+	return ret
+}
+
+func cmdFTSugDel(d *db, args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArity("ft.sugdel")
+	}
+	sugs, ok := d.suggestions[args[0]]
+	if !ok {
+		return int64(0)
+	}
+	if _, ok := sugs[args[1]]; !ok {
+		return int64(0)
+	}
+	delete(sugs, args[1])
+	if len(sugs) == 0 {
+		delete(d.suggestions, args[0])
+	}
+	return int64(1)
+}
+
+func cmdFTSugLen(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("ft.suglen")
+	}
+	return int64(len(d.suggestions[args[0]]))
+}
+
+func cmdFTDictAdd(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.dictadd")
+	}
+	dict, ok := d.dicts[args[0]]
+	if !ok {
+		dict = make(map[string]bool)
+		d.dicts[args[0]] = dict
+	}
+	n := int64(0)
+	for _, term := range args[1:] {
+		if !dict[term] {
+			dict[term] = true
+			n++
+		}
+	}
+	return n
+}
+
+func cmdFTDictDel(d *db, args []string) interface{} {
+	if len(args) < 2 {
+		return wrongArity("ft.dictdel")
+	}
+	dict := d.dicts[args[0]]
+	n := int64(0)
+	for _, term := range args[1:] {
+		if dict[term] {
+			delete(dict, term)
+			n++
+		}
+	}
+	if dict != nil && len(dict) == 0 {
+		delete(d.dicts, args[0])
+	}
+	return n
+}
+
+func cmdFTDictDump(d *db, args []string) interface{} {
+	if len(args) != 1 {
+		return wrongArity("ft.dictdump")
+	}
+	dict, ok := d.dicts[args[0]]
+	if !ok {
+		return errorReply("could not open dict key")
+	}
+	terms := make([]string, 0, len(dict))
+	for term := range dict {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// matchesPrefix returns whether the term starts with the prefix, or with a string at a Levenshtein distance of 1
+// from the prefix when fuzzy
+func matchesPrefix(term, prefix string, fuzzy bool) bool {
+	if strings.HasPrefix(term, prefix) {
+		return true
+	}
+	if !fuzzy {
+		return false
+	}
+	runes, n := []rune(term), len([]rune(prefix))
+	for k := n - 1; k <= n+1; k++ {
+		if k >= 0 && k <= len(runes) && levenshtein(string(runes[:k]), prefix) <= 1 {
+			return true
+		}
+	}
+	return false
+}
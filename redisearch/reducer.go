@@ -1,6 +1,13 @@
 package redisearch
 
-import "github.com/gomodule/redigo/redis"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
 
 // GroupByReducers
 type GroupByReducers string
@@ -84,7 +91,7 @@ func (r *Reducer) SetAlias(a string) *Reducer {
 	return r
 }
 
-func (r Reducer) Serialize() redis.Args {
+func (r Reducer) Serialize() []interface{} {
 	ret := len(r.Args)
 	args := redis.Args{"REDUCE", string(r.Name), ret}.AddFlat(r.Args)
 	if r.Alias != "" {
@@ -92,3 +99,149 @@ func (r Reducer) Serialize() redis.Args {
 	}
 	return args
 }
+
+// SortDirection is the direction of a sorting key used inside a reducer, e.g. the BY clause of FIRST_VALUE
+type SortDirection int
+
+const (
+	// Asc sorts in ascending order
+	Asc SortDirection = iota
+
+	// Desc sorts in descending order
+	Desc
+)
+
+// By creates a sorting key for the given property and direction, to be used by FirstValue.
+// The property is prefixed with '@' if needed.
+func By(property string, direction SortDirection) SortingKey {
+	return SortingKey{
+		Field:     toProperty(property),
+		Ascending: direction == Asc,
+	}
+}
+
+// Count creates a COUNT reducer, aliased as "count"
+func Count() *Reducer {
+	return NewReducerAlias(GroupByReducerCount, []string{}, "count")
+}
+
+// CountDistinct creates a COUNT_DISTINCT reducer on the given property, aliased as "count_distinct_<property>"
+func CountDistinct(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerCountDistinct, property)
+}
+
+// CountDistinctish creates a COUNT_DISTINCTISH reducer on the given property, aliased as "count_distinctish_<property>"
+func CountDistinctish(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerCountDistinctish, property)
+}
+
+// Sum creates a SUM reducer on the given property, aliased as "sum_<property>"
+func Sum(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerSum, property)
+}
+
+// Min creates a MIN reducer on the given property, aliased as "min_<property>"
+func Min(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerMin, property)
+}
+
+// Max creates a MAX reducer on the given property, aliased as "max_<property>"
+func Max(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerMax, property)
+}
+
+// Avg creates an AVG reducer on the given property, aliased as "avg_<property>"
+func Avg(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerAvg, property)
+}
+
+// StdDev creates a STDDEV reducer on the given property, aliased as "stddev_<property>"
+func StdDev(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerStdDev, property)
+}
+
+// ToList creates a TOLIST reducer on the given property, aliased as "tolist_<property>"
+func ToList(property string) (*Reducer, error) {
+	return newPropertyReducer(GroupByReducerToList, property)
+}
+
+// Quantile creates a QUANTILE reducer on the given property, aliased as "quantile_<property>_<quantile>" with
+// the decimal point of the quantile replaced by an underscore (e.g. "quantile_price_0_95"), so that the alias
+// can be referenced as a property by the later steps of the pipeline.
+// The quantile must be between [0,1], e.g. 0.5 for the median.
+func Quantile(property string, quantile float64) (*Reducer, error) {
+	if quantile < 0 || quantile > 1 || math.IsNaN(quantile) {
+		return nil, fmt.Errorf("The quantile should be between [0,1]. Got %g", quantile)
+	}
+	r, err := newPropertyReducer(GroupByReducerQuantile, property)
+	if err != nil {
+		return nil, err
+	}
+	r.Args = append(r.Args, strconv.FormatFloat(quantile, 'g', -1, 64))
+	r.Alias = r.Alias + "_" + strings.Replace(strconv.FormatFloat(quantile, 'f', -1, 64), ".", "_", -1)
+	return r, nil
+}
+
+// FirstValue creates a FIRST_VALUE reducer on the given property, aliased as "first_value_<property>".
+// An optional sorting key, created with By(), selects which value of the group is returned.
+func FirstValue(property string, by ...SortingKey) (*Reducer, error) {
+	if len(by) > 1 {
+		return nil, fmt.Errorf("FIRST_VALUE accepts at most one BY clause. Got %d", len(by))
+	}
+	r, err := newPropertyReducer(GroupByReducerFirstValue, property)
+	if err != nil {
+		return nil, err
+	}
+	if len(by) == 1 {
+		if by[0].Field == "" || by[0].Field == "@" {
+			return nil, fmt.Errorf("FIRST_VALUE BY clause requires a property")
+		}
+		r.Args = append(r.Args, "BY", toProperty(by[0].Field))
+		if by[0].Ascending {
+			r.Args = append(r.Args, "ASC")
+		} else {
+			r.Args = append(r.Args, "DESC")
+		}
+	}
+	return r, nil
+}
+
+// RandomSample creates a RANDOM_SAMPLE reducer returning up to sampleSize values of the given property,
+// aliased as "random_sample_<property>_<sampleSize>"
+func RandomSample(property string, sampleSize int) (*Reducer, error) {
+	if sampleSize <= 0 {
+		return nil, fmt.Errorf("The sample size should be a positive integer. Got %d", sampleSize)
+	}
+	r, err := newPropertyReducer(GroupByReducerRandomSample, property)
+	if err != nil {
+		return nil, err
+	}
+	size := strconv.Itoa(sampleSize)
+	r.Args = append(r.Args, size)
+	r.Alias = r.Alias + "_" + size
+	return r, nil
+}
+
+// internal function
+// newPropertyReducer creates a reducer on a single property, with a generated alias
+func newPropertyReducer(name GroupByReducers, property string) (*Reducer, error) {
+	property = toProperty(property)
+	if property == "@" {
+		return nil, fmt.Errorf("%s reducer requires a property", name)
+	}
+	return NewReducerAlias(name, []string{property}, reducerAlias(name, property)), nil
+}
+
+// reducerAlias generates a predictable alias for a reducer applied on a property, e.g. "count_distinct_user"
+func reducerAlias(name GroupByReducers, property string) string {
+	return strings.ToLower(string(name)) + "_" + strings.TrimPrefix(property, "@")
+}
+
+// toProperty trims the given name and adds the '@' prefix if it is missing
+func toProperty(name string) string {
+	name = strings.TrimSpace(name)
+	if !strings.HasPrefix(name, "@") {
+		name = "@" + name
+	}
+	return name
+}
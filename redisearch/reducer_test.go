@@ -0,0 +1,90 @@
+package redisearch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReducerConstructors_Serialize(t *testing.T) {
+	mustReducer := func(r *Reducer, err error) *Reducer {
+		assert.Nil(t, err)
+		return r
+	}
+	tests := []struct {
+		name    string
+		reducer *Reducer
+		want    []interface{}
+	}{
+		{"Count", Count(), []interface{}{"REDUCE", "COUNT", 0, "AS", "count"}},
+		{"CountDistinct", mustReducer(CountDistinct("@user")), []interface{}{"REDUCE", "COUNT_DISTINCT", 1, "@user", "AS", "count_distinct_user"}},
+		{"CountDistinct-prefix", mustReducer(CountDistinct("user")), []interface{}{"REDUCE", "COUNT_DISTINCT", 1, "@user", "AS", "count_distinct_user"}},
+		{"CountDistinctish", mustReducer(CountDistinctish("user")), []interface{}{"REDUCE", "COUNT_DISTINCTISH", 1, "@user", "AS", "count_distinctish_user"}},
+		{"Sum", mustReducer(Sum("price")), []interface{}{"REDUCE", "SUM", 1, "@price", "AS", "sum_price"}},
+		{"Min", mustReducer(Min("price")), []interface{}{"REDUCE", "MIN", 1, "@price", "AS", "min_price"}},
+		{"Max", mustReducer(Max("price")), []interface{}{"REDUCE", "MAX", 1, "@price", "AS", "max_price"}},
+		{"Avg", mustReducer(Avg("price")), []interface{}{"REDUCE", "AVG", 1, "@price", "AS", "avg_price"}},
+		{"StdDev", mustReducer(StdDev("price")), []interface{}{"REDUCE", "STDDEV", 1, "@price", "AS", "stddev_price"}},
+		{"ToList", mustReducer(ToList("@tag")), []interface{}{"REDUCE", "TOLIST", 1, "@tag", "AS", "tolist_tag"}},
+		{"Quantile", mustReducer(Quantile("@price", 0.95)), []interface{}{"REDUCE", "QUANTILE", 2, "@price", "0.95", "AS", "quantile_price_0_95"}},
+		{"Quantile-small", mustReducer(Quantile("@price", 0.00001)), []interface{}{"REDUCE", "QUANTILE", 2, "@price", "1e-05", "AS", "quantile_price_0_00001"}},
+		{"Quantile-one", mustReducer(Quantile("@price", 1)), []interface{}{"REDUCE", "QUANTILE", 2, "@price", "1", "AS", "quantile_price_1"}},
+		{"FirstValue", mustReducer(FirstValue("@title")), []interface{}{"REDUCE", "FIRST_VALUE", 1, "@title", "AS", "first_value_title"}},
+		{"FirstValue-By", mustReducer(FirstValue("@title", By("ts", Desc))), []interface{}{"REDUCE", "FIRST_VALUE", 4, "@title", "BY", "@ts", "DESC", "AS", "first_value_title"}},
+		{"FirstValue-By-Asc", mustReducer(FirstValue("@title", By("@ts", Asc))), []interface{}{"REDUCE", "FIRST_VALUE", 4, "@title", "BY", "@ts", "ASC", "AS", "first_value_title"}},
+		{"RandomSample", mustReducer(RandomSample("@id", 10)), []interface{}{"REDUCE", "RANDOM_SAMPLE", 2, "@id", "10", "AS", "random_sample_id_10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reducer.Serialize(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Serialize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReducerConstructors_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"CountDistinct-empty", func() error { _, err := CountDistinct(""); return err }()},
+		{"Sum-only-prefix", func() error { _, err := Sum("@"); return err }()},
+		{"Quantile-negative", func() error { _, err := Quantile("@price", -0.1); return err }()},
+		{"Quantile-above-one", func() error { _, err := Quantile("@price", 1.5); return err }()},
+		{"Quantile-empty-property", func() error { _, err := Quantile("", 0.5); return err }()},
+		{"FirstValue-two-by", func() error { _, err := FirstValue("@title", By("@a", Asc), By("@b", Desc)); return err }()},
+		{"FirstValue-empty-by", func() error { _, err := FirstValue("@title", SortingKey{}); return err }()},
+		{"RandomSample-zero", func() error { _, err := RandomSample("@id", 0); return err }()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotNil(t, tt.err)
+		})
+	}
+}
+
+func TestQuantile_alias(t *testing.T) {
+	r, err := Quantile("price", 0.95)
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[A-Za-z_][A-Za-z0-9_]*$", r.Alias)
+
+	// the alias is referenced by the later steps of the pipeline
+	property := "@" + r.Alias
+	q := NewAggregateQuery().
+		GroupBy(*NewGroupBy().AddFields("@brand").Reduce(*r)).
+		Apply(*NewProjection(property+" * 100", "percent")).
+		Filter(property + " > 10").
+		SortBy([]SortingKey{*NewSortingKeyDir(property, false)})
+	assert.Equal(t, []interface{}{"*",
+		"GROUPBY", 1, "@brand", "REDUCE", "QUANTILE", 2, "@price", "0.95", "AS", "quantile_price_0_95",
+		"APPLY", "@quantile_price_0_95 * 100", "AS", "percent",
+		"FILTER", "@quantile_price_0_95 > 10",
+		"SORTBY", 2, "@quantile_price_0_95", "DESC"}, q.Serialize())
+}
+
+func TestBy(t *testing.T) {
+	assert.Equal(t, SortingKey{Field: "@ts", Ascending: false}, By("ts", Desc))
+	assert.Equal(t, SortingKey{Field: "@ts", Ascending: true}, By("@ts", Asc))
+}
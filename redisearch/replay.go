@@ -0,0 +1,637 @@
+package redisearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Interaction is a command and its reply, as recorded by a RecordingPool
+type Interaction struct {
+	// Command is the name of the command, upper cased
+	Command string
+	// Args are the arguments of the command, formatted the same way as they are sent to redis
+	Args []string
+	// Reply is the reply of the command, a redis.Error for the error replies
+	Reply interface{}
+	// Err is the message of the error which is not a reply of redis, such as a network error
+	Err string
+}
+
+// Recording is an ordered list of interactions, saved as a golden file with one JSON interaction per line.
+// The replies are encoded with the RESP type prefixes: "+" for the status replies, "$" for the bulk strings,
+// ":" for the integers and "-" for the errors, the arrays are JSON arrays and the RESP3 maps are {"map": [...]}.
+type Recording struct {
+	Interactions []Interaction
+	mu           sync.Mutex
+}
+
+// LoadRecording reads a recording from a golden file
+func LoadRecording(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+// ReadRecording reads a recording with one JSON interaction per line
+func ReadRecording(r io.Reader) (*Recording, error) {
+	rec := &Recording{Interactions: make([]Interaction, 0)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("redisearch: invalid interaction on line %d: %v", line, err)
+		}
+		rec.Interactions = append(rec.Interactions, i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// WriteTo writes the recording with one JSON interaction per line
+func (r *Recording) WriteTo(w io.Writer) (n int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.Interactions {
+		b, err := json.Marshal(i)
+		if err != nil {
+			return n, err
+		}
+		written, err := w.Write(append(b, '\n'))
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Save writes the recording to a golden file, creating its directory if needed
+func (r *Recording) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func (r *Recording) add(i Interaction) {
+	r.mu.Lock()
+	r.Interactions = append(r.Interactions, i)
+	r.mu.Unlock()
+}
+
+// newInteraction formats a command and its reply as an interaction
+func newInteraction(commandName string, args []interface{}, reply interface{}, err error) Interaction {
+	i := Interaction{Command: strings.ToUpper(commandName), Args: formatArgs(args), Reply: reply}
+	if err != nil {
+		if rerr, ok := err.(redis.Error); ok {
+			i.Reply = rerr
+		} else {
+			i.Err = err.Error()
+		}
+	}
+	return i
+}
+
+// formatArgs formats the arguments of a command the same way as redigo
+func formatArgs(args []interface{}) []string {
+	ret := make([]string, len(args))
+	for ii, arg := range args {
+		ret[ii] = string(formatRESP3Arg(arg, true))
+	}
+	return ret
+}
+
+type interactionJSON struct {
+	Command string            `json:"cmd"`
+	Args    []json.RawMessage `json:"args"`
+	Reply   json.RawMessage   `json:"reply"`
+	Err     string            `json:"err,omitempty"`
+}
+
+// binaryJSON is the encoding of the strings which are not valid UTF-8
+type binaryJSON struct {
+	Type   string `json:"type,omitempty"`
+	Base64 string `json:"base64"`
+}
+
+// mapJSON is the encoding of the RESP3 maps
+type mapJSON struct {
+	Map []json.RawMessage `json:"map"`
+}
+
+func (i Interaction) MarshalJSON() ([]byte, error) {
+	ret := interactionJSON{Command: i.Command, Args: make([]json.RawMessage, len(i.Args)), Err: i.Err}
+	var err error
+	for ii, arg := range i.Args {
+		if ret.Args[ii], err = encodeString("", arg); err != nil {
+			return nil, err
+		}
+	}
+	if ret.Reply, err = encodeReply(i.Reply); err != nil {
+		return nil, err
+	}
+	return json.Marshal(ret)
+}
+
+func (i *Interaction) UnmarshalJSON(b []byte) error {
+	var raw interactionJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	i.Command, i.Err = raw.Command, raw.Err
+	i.Args = make([]string, len(raw.Args))
+	for ii, arg := range raw.Args {
+		s, err := decodeString(arg)
+		if err != nil {
+			return err
+		}
+		i.Args[ii] = s
+	}
+	reply, err := decodeReply(raw.Reply)
+	if err != nil {
+		return err
+	}
+	i.Reply = reply
+	return nil
+}
+
+// encodeString encodes a prefixed string, as base64 if it is not valid UTF-8
+func encodeString(prefix, s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return json.Marshal(binaryJSON{Type: prefix, Base64: base64.StdEncoding.EncodeToString([]byte(s))})
+	}
+	return json.Marshal(prefix + s)
+}
+
+func decodeString(b []byte) (string, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return s, nil
+	}
+	var bin binaryJSON
+	if err := json.Unmarshal(b, &bin); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(bin.Base64)
+	if err != nil {
+		return "", err
+	}
+	return bin.Type + string(data), nil
+}
+
+func encodeReply(reply interface{}) ([]byte, error) {
+	switch reply := reply.(type) {
+	case nil:
+		return []byte("null"), nil
+	case []byte:
+		return encodeString("$", string(reply))
+	case string:
+		return encodeString("+", reply)
+	case int64:
+		return json.Marshal(":" + strconv.FormatInt(reply, 10))
+	case redis.Error:
+		return encodeString("-", string(reply))
+	case RESP3Map:
+		values, err := encodeReplies(reply)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(mapJSON{Map: values})
+	case []interface{}:
+		values, err := encodeReplies(reply)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(values)
+	}
+	return encodeString("$", string(formatRESP3Arg(reply, true)))
+}
+
+func encodeReplies(replies []interface{}) ([]json.RawMessage, error) {
+	ret := make([]json.RawMessage, len(replies))
+	for ii, reply := range replies {
+		b, err := encodeReply(reply)
+		if err != nil {
+			return nil, err
+		}
+		ret[ii] = b
+	}
+	return ret, nil
+}
+
+func decodeReply(b []byte) (interface{}, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil
+	}
+	switch b[0] {
+	case '[':
+		var raw []json.RawMessage
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return nil, err
+		}
+		return decodeReplies(raw)
+	case '{':
+		var m mapJSON
+		if err := json.Unmarshal(b, &m); err == nil && m.Map != nil {
+			values, err := decodeReplies(m.Map)
+			return RESP3Map(values), err
+		}
+	}
+	s, err := decodeString(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(s) == 0 {
+		return nil, errors.New("redisearch: reply without a type prefix")
+	}
+	switch s[0] {
+	case '$':
+		return []byte(s[1:]), nil
+	case '+':
+		return s[1:], nil
+	case '-':
+		return redis.Error(s[1:]), nil
+	case ':':
+		return strconv.ParseInt(s[1:], 10, 64)
+	}
+	return nil, fmt.Errorf("redisearch: invalid reply type prefix %q", s[0])
+}
+
+func decodeReplies(raw []json.RawMessage) ([]interface{}, error) {
+	ret := make([]interface{}, len(raw))
+	for ii, b := range raw {
+		reply, err := decodeReply(b)
+		if err != nil {
+			return nil, err
+		}
+		ret[ii] = reply
+	}
+	return ret, nil
+}
+
+// RecordingPool is a ConnPool recording the commands and the replies of the connections of the underlying pool.
+// Use NewClientFromExecutor(NewRedigoExecutor(pool), name) to create a client running its commands with it.
+type RecordingPool struct {
+	pool      ConnPool
+	recording *Recording
+}
+
+// NewRecordingPool creates a pool recording the commands run on the given pool
+func NewRecordingPool(pool ConnPool) *RecordingPool {
+	return &RecordingPool{pool: pool, recording: &Recording{Interactions: make([]Interaction, 0)}}
+}
+
+// Recording returns the interactions recorded so far
+func (p *RecordingPool) Recording() *Recording {
+	return p.recording
+}
+
+func (p *RecordingPool) Get() redis.Conn {
+	return &recordingConn{Conn: p.pool.Get(), recording: p.recording}
+}
+
+func (p *RecordingPool) Close() error {
+	return p.pool.Close()
+}
+
+// internal struct
+// recordingConn records the commands of the underlying connection with their replies. The pipelined commands are
+// recorded when their reply is received.
+type recordingConn struct {
+	redis.Conn
+	recording *Recording
+	pending   []Command
+}
+
+func (c *recordingConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if len(c.pending) > 0 || commandName == "" {
+		return doPending(c, commandName, args)
+	}
+	reply, err := c.Conn.Do(commandName, args...)
+	c.recording.add(newInteraction(commandName, args, reply, err))
+	return reply, err
+}
+
+func (c *recordingConn) Send(commandName string, args ...interface{}) error {
+	if err := c.Conn.Send(commandName, args...); err != nil {
+		return err
+	}
+	c.pending = append(c.pending, Command{Name: commandName, Args: args})
+	return nil
+}
+
+func (c *recordingConn) Receive() (interface{}, error) {
+	reply, err := c.Conn.Receive()
+	if len(c.pending) > 0 {
+		cmd := c.pending[0]
+		c.pending = c.pending[1:]
+		c.recording.add(newInteraction(cmd.Name, cmd.Args, reply, err))
+	}
+	return reply, err
+}
+
+// pendingConn is a connection tracking its pipelined commands
+type pendingConn interface {
+	redis.Conn
+	pendingCount() int
+}
+
+func (c *recordingConn) pendingCount() int {
+	return len(c.pending)
+}
+
+// doPending runs a command after the pipelined commands the same way as redigo: the replies of all the commands
+// are received, the last one is returned with the first error.
+func doPending(c pendingConn, commandName string, args []interface{}) (interface{}, error) {
+	if commandName != "" {
+		if err := c.Send(commandName, args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	var reply interface{}
+	var replyErr error
+	for c.pendingCount() > 0 {
+		r, err := c.Receive()
+		if err != nil && replyErr == nil {
+			replyErr = err
+		}
+		reply = r
+	}
+	return reply, replyErr
+}
+
+// ReplayMode sets the behavior of a ReplayPool when a command differs from the recording
+type ReplayMode int
+
+const (
+	// ReplayStrict fails the commands differing from the recorded ones
+	ReplayStrict ReplayMode = iota
+	// ReplayDiff replies to a command whose arguments differ from the recorded ones with the recorded reply, and
+	// reports the difference in the Diff of the pool. The commands missing from the recording still fail.
+	ReplayDiff
+)
+
+// ReplayMismatch is a command differing from the recorded one
+type ReplayMismatch struct {
+	// Index is the position of the expected interaction in the recording
+	Index int
+	// Expected is the recorded interaction, nil when all of them were replayed
+	Expected *Interaction
+	// Command and Args are the command which was run instead
+	Command string
+	Args    []string
+}
+
+func (m *ReplayMismatch) Error() string {
+	got := strings.TrimSpace(m.Command + " " + strings.Join(m.Args, " "))
+	if m.Expected == nil {
+		return fmt.Sprintf("redisearch: replay: unexpected command #%d %s, the recording is over", m.Index, got)
+	}
+	expected := strings.TrimSpace(m.Expected.Command + " " + strings.Join(m.Expected.Args, " "))
+	return fmt.Sprintf("redisearch: replay: command #%d is %s, expected %s", m.Index, got, expected)
+}
+
+// Diff returns the difference between the expected and the actual command, with [-removed] and [+added] arguments.
+// A command which is not the recorded one is reported as added, the recorded one is still expected.
+func (m *ReplayMismatch) Diff() string {
+	if m.Expected == nil || m.Expected.Command != strings.ToUpper(m.Command) {
+		return fmt.Sprintf("#%d: [+%s]", m.Index, strings.TrimSpace(m.Command+" "+strings.Join(m.Args, " ")))
+	}
+	expected := append([]string{m.Expected.Command}, m.Expected.Args...)
+	actual := append([]string{strings.ToUpper(m.Command)}, m.Args...)
+	return fmt.Sprintf("%s #%d: %s", m.Expected.Command, m.Index, diffArgs(expected, actual))
+}
+
+// diffArgs returns the arguments of a and b, with the longest common subsequence as is and the other arguments
+// grouped in [-removed] and [+added] runs
+func diffArgs(a, b []string) string {
+	lcs := make([][]int, len(a)+1)
+	for ii := range lcs {
+		lcs[ii] = make([]int, len(b)+1)
+	}
+	for ii := len(a) - 1; ii >= 0; ii-- {
+		for jj := len(b) - 1; jj >= 0; jj-- {
+			if a[ii] == b[jj] {
+				lcs[ii][jj] = lcs[ii+1][jj+1] + 1
+			} else if lcs[ii+1][jj] >= lcs[ii][jj+1] {
+				lcs[ii][jj] = lcs[ii+1][jj]
+			} else {
+				lcs[ii][jj] = lcs[ii][jj+1]
+			}
+		}
+	}
+	parts := make([]string, 0)
+	var removed, added []string
+	flush := func() {
+		if len(removed) > 0 {
+			parts = append(parts, "[-"+strings.Join(removed, " ")+"]")
+		}
+		if len(added) > 0 {
+			parts = append(parts, "[+"+strings.Join(added, " ")+"]")
+		}
+		removed, added = nil, nil
+	}
+	ii, jj := 0, 0
+	for ii < len(a) || jj < len(b) {
+		switch {
+		case ii < len(a) && jj < len(b) && a[ii] == b[jj]:
+			flush()
+			parts = append(parts, a[ii])
+			ii++
+			jj++
+		case jj == len(b) || (ii < len(a) && lcs[ii+1][jj] >= lcs[ii][jj+1]):
+			removed = append(removed, a[ii])
+			ii++
+		default:
+			added = append(added, b[jj])
+			jj++
+		}
+	}
+	flush()
+	return strings.Join(parts, " ")
+}
+
+// ReplayPool is a ConnPool replying to the commands with the replies of a recording, without connecting to redis.
+// The commands must be run in the recorded order, the connections of the pool share the position in the recording.
+type ReplayPool struct {
+	recording  *Recording
+	mode       ReplayMode
+	mu         sync.Mutex
+	pos        int
+	mismatches []*ReplayMismatch
+}
+
+// NewReplayPool creates a pool replaying the given recording
+func NewReplayPool(recording *Recording, mode ReplayMode) *ReplayPool {
+	return &ReplayPool{recording: recording, mode: mode, mismatches: make([]*ReplayMismatch, 0)}
+}
+
+func (p *ReplayPool) Get() redis.Conn {
+	return &replayConn{pool: p}
+}
+
+func (p *ReplayPool) Close() error {
+	return nil
+}
+
+// Mismatches returns the commands which differed from the recording
+func (p *ReplayPool) Mismatches() []*ReplayMismatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*ReplayMismatch(nil), p.mismatches...)
+}
+
+// Remaining returns the recorded interactions which were not replayed yet
+func (p *ReplayPool) Remaining() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Interaction(nil), p.recording.Interactions[p.pos:]...)
+}
+
+// Diff returns a report of the commands which differed from the recording and of the recorded commands which were
+// not run, one per line, or an empty string if the replay matched the recording
+func (p *ReplayPool) Diff() string {
+	lines := make([]string, 0)
+	for _, m := range p.Mismatches() {
+		lines = append(lines, m.Diff())
+	}
+	p.mu.Lock()
+	for ii := p.pos; ii < len(p.recording.Interactions); ii++ {
+		i := p.recording.Interactions[ii]
+		lines = append(lines, fmt.Sprintf("%s #%d: [-%s]", i.Command, ii, strings.Join(append([]string{i.Command}, i.Args...), " ")))
+	}
+	p.mu.Unlock()
+	return strings.Join(lines, "\n")
+}
+
+// next returns the recorded reply of a command
+func (p *ReplayPool) next(commandName string, args []interface{}) (interface{}, error) {
+	cmd, formatted := strings.ToUpper(commandName), formatArgs(args)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pos >= len(p.recording.Interactions) {
+		m := &ReplayMismatch{Index: p.pos, Command: cmd, Args: formatted}
+		p.mismatches = append(p.mismatches, m)
+		return nil, m
+	}
+	expected := p.recording.Interactions[p.pos]
+	if expected.Command != cmd || !equalArgs(expected.Args, formatted) {
+		m := &ReplayMismatch{Index: p.pos, Expected: &expected, Command: cmd, Args: formatted}
+		p.mismatches = append(p.mismatches, m)
+		if p.mode != ReplayDiff || expected.Command != cmd {
+			return nil, m
+		}
+	}
+	p.pos++
+	if expected.Err != "" {
+		return nil, errors.New(expected.Err)
+	}
+	if err, ok := expected.Reply.(redis.Error); ok {
+		return nil, err
+	}
+	return expected.Reply, nil
+}
+
+func equalArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for ii := range a {
+		if a[ii] != b[ii] {
+			return false
+		}
+	}
+	return true
+}
+
+// internal struct
+// replayConn replies to its commands from the recording of its pool. The replies of the pipelined commands are
+// looked up when they are sent.
+type replayConn struct {
+	pool    *ReplayPool
+	pending []Reply
+	closed  bool
+}
+
+func (c *replayConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *replayConn) Err() error {
+	if c.closed {
+		return errors.New("redisearch: closed connection")
+	}
+	return nil
+}
+
+func (c *replayConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if len(c.pending) > 0 || commandName == "" {
+		return doPending(c, commandName, args)
+	}
+	return c.pool.next(commandName, args)
+}
+
+func (c *replayConn) Send(commandName string, args ...interface{}) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	reply, err := c.pool.next(commandName, args)
+	c.pending = append(c.pending, Reply{Value: reply, Err: err})
+	return nil
+}
+
+func (c *replayConn) Flush() error {
+	return c.Err()
+}
+
+func (c *replayConn) Receive() (interface{}, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if len(c.pending) == 0 {
+		return nil, errors.New("redisearch: Receive without a pending command")
+	}
+	reply := c.pending[0]
+	c.pending = c.pending[1:]
+	return reply.Value, reply.Err
+}
+
+func (c *replayConn) pendingCount() int {
+	return len(c.pending)
+}
@@ -0,0 +1,152 @@
+package redisearch
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RediSearch/redisearch-go/v2/redisearch/redisearchtest"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordProducts(t *testing.T) *Recording {
+	srv := redisearchtest.NewServer()
+	defer srv.Close()
+	pool := NewRecordingPool(NewSingleHostPool(srv.Addr))
+	c := NewClientFromExecutor(NewRedigoExecutor(pool), "products")
+
+	assert.Nil(t, c.CreateIndex(NewSchema(DefaultOptions).
+		AddField(NewTextField("name")).
+		AddField(NewSortableNumericField("price"))))
+	assert.Nil(t, c.Index(
+		NewDocument("p1", 1).Set("name", "red shoes").Set("price", 80),
+		NewDocument("p2", 1).Set("name", "blue shoes").Set("price", 120)))
+	docs, total, err := c.Search(NewQuery("shoes").SetSortBy("price", true))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "p1", docs[0].Id)
+	_, err = c.Info()
+	assert.Nil(t, err)
+	_, _, err = c.Search(NewQuery("@unknown:foo"))
+	assert.NotNil(t, err)
+	return pool.Recording()
+}
+
+func TestRecordingPool(t *testing.T) {
+	rec := recordProducts(t)
+	assert.Equal(t, "FT.CREATE", rec.Interactions[0].Command)
+	assert.Equal(t, []string{"products", "shoes", "SORTBY", "price", "ASC"},
+		rec.Interactions[len(rec.Interactions)-3].Args)
+	_, ok := rec.Interactions[len(rec.Interactions)-1].Reply.(redis.Error)
+	assert.True(t, ok)
+
+	path := filepath.Join(t.TempDir(), "golden", "products.jsonl")
+	assert.Nil(t, rec.Save(path))
+	loaded, err := LoadRecording(path)
+	assert.Nil(t, err)
+	assert.Equal(t, rec.Interactions, loaded.Interactions)
+}
+
+func TestRecording_encoding(t *testing.T) {
+	rec := &Recording{Interactions: []Interaction{
+		{Command: "FT.SEARCH", Args: []string{"idx", "*", "PARAMS", "2", "vec", "\xff\x00\x01"}, Reply: []interface{}{
+			int64(1), []byte("doc1"), nil, "OK", redis.Error("ERR bad"), []byte("\xfe"),
+			RESP3Map{[]byte("key"), []interface{}{int64(-2)}},
+		}},
+		{Command: "PING", Args: []string{}, Err: "EOF"},
+	}}
+	var buf bytes.Buffer
+	_, err := rec.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), `"$doc1"`)
+	assert.Contains(t, buf.String(), `{"map":["$key",[":-2"]]}`)
+
+	loaded, err := ReadRecording(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, rec.Interactions, loaded.Interactions)
+
+	_, err = ReadRecording(strings.NewReader(`{"cmd":"PING","args":[],"reply":"PONG"}`))
+	assert.NotNil(t, err)
+}
+
+func TestReplayPool(t *testing.T) {
+	rec := recordProducts(t)
+
+	// the commands are replayed without a server
+	pool := NewReplayPool(rec, ReplayStrict)
+	c := NewClientFromExecutor(NewRedigoExecutor(pool), "products")
+	assert.Nil(t, c.CreateIndex(NewSchema(DefaultOptions).
+		AddField(NewTextField("name")).
+		AddField(NewSortableNumericField("price"))))
+	assert.Nil(t, c.Index(
+		NewDocument("p1", 1).Set("name", "red shoes").Set("price", 80),
+		NewDocument("p2", 1).Set("name", "blue shoes").Set("price", 120)))
+	docs, total, err := c.Search(NewQuery("shoes").SetSortBy("price", true))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "p1", docs[0].Id)
+	info, err := c.Info()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), info.DocCount)
+	_, _, err = c.Search(NewQuery("@unknown:foo"))
+	assert.IsType(t, redis.Error(""), err)
+
+	assert.Empty(t, pool.Mismatches())
+	assert.Empty(t, pool.Remaining())
+	assert.Equal(t, "", pool.Diff())
+
+	// the commands after the end of the recording fail
+	_, err = c.Info()
+	assert.IsType(t, &ReplayMismatch{}, err)
+}
+
+func TestReplayPool_strict(t *testing.T) {
+	rec := recordProducts(t)
+	pool := NewReplayPool(rec, ReplayStrict)
+	c := NewClientFromExecutor(NewRedigoExecutor(pool), "products")
+
+	err := c.CreateIndex(NewSchema(DefaultOptions).AddField(NewTextField("name")))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "command #0 is FT.CREATE products SCHEMA name TEXT")
+	assert.Len(t, pool.Mismatches(), 1)
+	assert.Len(t, pool.Remaining(), len(rec.Interactions))
+}
+
+func TestReplayPool_diff(t *testing.T) {
+	rec := recordProducts(t)
+	pool := NewReplayPool(rec, ReplayDiff)
+	c := NewClientFromExecutor(NewRedigoExecutor(pool), "products")
+
+	assert.Nil(t, c.CreateIndex(NewSchema(DefaultOptions).
+		AddField(NewTextField("name")).
+		AddField(NewSortableNumericField("price"))))
+	assert.Nil(t, c.Index(
+		NewDocument("p1", 1).Set("name", "red shoes").Set("price", 80),
+		NewDocument("p2", 1).Set("name", "blue shoes").Set("price", 120)))
+	// the serialization of the query changed, the recorded reply is still served
+	docs, _, err := c.Search(NewQuery("shoes").SetSortBy("price", true).SetDialect(2))
+	assert.Nil(t, err)
+	assert.Equal(t, "p1", docs[0].Id)
+	// a command missing from the recording fails
+	_, err = c.List()
+	assert.NotNil(t, err)
+
+	assert.Len(t, pool.Mismatches(), 2)
+	diff := strings.Split(pool.Diff(), "\n")
+	assert.Equal(t, []string{
+		"FT.SEARCH #3: FT.SEARCH products shoes SORTBY price ASC [+DIALECT 2]",
+		"#4: [+FT._LIST]",
+		"FT.INFO #4: [-FT.INFO products]",
+		"FT.SEARCH #5: [-FT.SEARCH products @unknown:foo]",
+	}, diff)
+}
+
+func Test_diffArgs(t *testing.T) {
+	assert.Equal(t, "a [-b] [+x y] c", diffArgs([]string{"a", "b", "c"}, []string{"a", "x", "y", "c"}))
+	assert.Equal(t, "a b", diffArgs([]string{"a", "b"}, []string{"a", "b"}))
+	assert.Equal(t, "[-a b]", diffArgs([]string{"a", "b"}, []string{}))
+	assert.Equal(t, "[+a]", diffArgs([]string{}, []string{"a"}))
+}
@@ -0,0 +1,438 @@
+package redisearch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RESP3Map is a RESP3 map reply, holding alternating keys and values in the order sent by the server.
+// The parsers of this package accept it wherever RESP2 returns a flat key/value array.
+type RESP3Map []interface{}
+
+// Get returns the value of the given key, and whether the key was found
+func (m RESP3Map) Get(key string) (interface{}, bool) {
+	for i := 0; i+1 < len(m); i += 2 {
+		if k, err := redis.String(m[i], nil); err == nil && k == key {
+			return m[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// resp3Options are the options of DialRESP3
+type resp3Options struct {
+	username     string
+	password     string
+	dialTimeout  time.Duration
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+// RESP3Option configures a connection opened by DialRESP3
+type RESP3Option func(*resp3Options)
+
+// RESP3Auth authenticates the connection with HELLO 3 AUTH. The username can be "default" for
+// servers without ACL users.
+func RESP3Auth(username, password string) RESP3Option {
+	return func(o *resp3Options) {
+		if username == "" {
+			username = "default"
+		}
+		o.username = username
+		o.password = password
+	}
+}
+
+// RESP3Timeouts sets the connect, read and write timeouts of the connection. Zero means no timeout.
+func RESP3Timeouts(dial, read, write time.Duration) RESP3Option {
+	return func(o *resp3Options) {
+		o.dialTimeout = dial
+		o.readTimeout = read
+		o.writeTimeout = write
+	}
+}
+
+// DialRESP3 connects to the redis server and switches the connection to the RESP3 protocol with HELLO 3.
+// The returned connection can be used by a redis.Pool, or by a Client through NewSingleHostPoolRESP3.
+//
+// Replies are decoded as with RESP2, so that the redigo reply helpers keep working: maps are returned as
+// RESP3Map, sets as []interface{}, doubles and big numbers as []byte, booleans as int64, nulls as nil,
+// and verbatim strings as []byte without their format prefix. Attributes and push messages are discarded.
+func DialRESP3(network, address string, options ...RESP3Option) (redis.Conn, error) {
+	opts := resp3Options{}
+	for _, option := range options {
+		option(&opts)
+	}
+	netConn, err := net.DialTimeout(network, address, opts.dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &resp3Conn{
+		conn:         netConn,
+		br:           bufio.NewReader(netConn),
+		bw:           bufio.NewWriter(netConn),
+		readTimeout:  opts.readTimeout,
+		writeTimeout: opts.writeTimeout,
+	}
+	args := redis.Args{3}
+	if opts.password != "" {
+		args = args.Add("AUTH", opts.username, opts.password)
+	}
+	if _, err := c.Do("HELLO", args...); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewSingleHostPoolRESP3 creates a pool of RESP3 connections to the redis host
+func NewSingleHostPoolRESP3(host string, options ...RESP3Option) *SingleHostPool {
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return DialRESP3("tcp", host, options...)
+	}, MaxIdle: maxConns}
+	pool.TestOnBorrow = func(c redis.Conn, t time.Time) (err error) {
+		if time.Since(t) > time.Second {
+			_, err = c.Do("PING")
+		}
+		return err
+	}
+	return &SingleHostPool{pool}
+}
+
+// resp3Conn is a redis.Conn speaking the RESP3 protocol
+type resp3Conn struct {
+	mu      sync.Mutex
+	pending int
+	err     error
+	conn    net.Conn
+
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	br *bufio.Reader
+	bw *bufio.Writer
+}
+
+func (c *resp3Conn) Close() error {
+	c.mu.Lock()
+	err := c.err
+	if c.err == nil {
+		c.err = errors.New("redisearch: closed")
+		err = c.conn.Close()
+	}
+	c.mu.Unlock()
+	return err
+}
+
+func (c *resp3Conn) Err() error {
+	c.mu.Lock()
+	err := c.err
+	c.mu.Unlock()
+	return err
+}
+
+func (c *resp3Conn) fatal(err error) error {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+		c.conn.Close()
+	}
+	c.mu.Unlock()
+	return err
+}
+
+func (c *resp3Conn) Send(cmd string, args ...interface{}) error {
+	c.mu.Lock()
+	c.pending++
+	c.mu.Unlock()
+	if c.writeTimeout != 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return c.fatal(err)
+		}
+	}
+	if err := c.writeCommand(cmd, args); err != nil {
+		return c.fatal(err)
+	}
+	return nil
+}
+
+func (c *resp3Conn) Flush() error {
+	if c.writeTimeout != 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return c.fatal(err)
+		}
+	}
+	if err := c.bw.Flush(); err != nil {
+		return c.fatal(err)
+	}
+	return nil
+}
+
+func (c *resp3Conn) Receive() (interface{}, error) {
+	var deadline time.Time
+	if c.readTimeout != 0 {
+		deadline = time.Now().Add(c.readTimeout)
+	}
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return nil, c.fatal(err)
+	}
+	reply, err := readRESP3Reply(c.br)
+	if err != nil {
+		return nil, c.fatal(err)
+	}
+	c.mu.Lock()
+	if c.pending > 0 {
+		c.pending--
+	}
+	c.mu.Unlock()
+	if err, ok := reply.(redis.Error); ok {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *resp3Conn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	if cmd != "" {
+		if err := c.Send(cmd, args...); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	pending := c.pending
+	c.mu.Unlock()
+	var reply interface{}
+	var err error
+	for ; pending > 0; pending-- {
+		var e error
+		if reply, e = c.Receive(); e != nil {
+			if _, ok := e.(redis.Error); !ok {
+				return nil, e
+			}
+			reply, err = nil, e
+			continue
+		}
+		err = nil
+	}
+	return reply, err
+}
+
+func (c *resp3Conn) writeCommand(cmd string, args []interface{}) error {
+	c.bw.WriteString("*" + strconv.Itoa(1+len(args)) + "\r\n")
+	writeRESP3Bulk(c.bw, []byte(cmd))
+	for _, arg := range args {
+		writeRESP3Bulk(c.bw, formatRESP3Arg(arg, true))
+	}
+	return nil
+}
+
+func writeRESP3Bulk(w *bufio.Writer, b []byte) {
+	w.WriteString("$" + strconv.Itoa(len(b)) + "\r\n")
+	w.Write(b)
+	w.WriteString("\r\n")
+}
+
+// formatRESP3Arg formats a command argument the same way as redigo
+func formatRESP3Arg(arg interface{}, argumentTypeOK bool) []byte {
+	switch arg := arg.(type) {
+	case string:
+		return []byte(arg)
+	case []byte:
+		return arg
+	case int:
+		return strconv.AppendInt(nil, int64(arg), 10)
+	case int64:
+		return strconv.AppendInt(nil, arg, 10)
+	case float64:
+		return strconv.AppendFloat(nil, arg, 'g', -1, 64)
+	case bool:
+		if arg {
+			return []byte("1")
+		}
+		return []byte("0")
+	case nil:
+		return []byte{}
+	case redis.Argument:
+		if argumentTypeOK {
+			return formatRESP3Arg(arg.RedisArg(), false)
+		}
+	}
+	var buf bytes.Buffer
+	fmt.Fprint(&buf, arg)
+	return buf.Bytes()
+}
+
+type resp3ProtocolError string
+
+func (pe resp3ProtocolError) Error() string {
+	return fmt.Sprintf("redisearch: %s (possible server error or unsupported concurrent read by application)", string(pe))
+}
+
+func readRESP3Line(br *bufio.Reader) ([]byte, error) {
+	line, err := br.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		buf := append([]byte{}, line...)
+		for err == bufio.ErrBufferFull {
+			line, err = br.ReadSlice('\n')
+			buf = append(buf, line...)
+		}
+		line = buf
+	}
+	if err != nil {
+		return nil, err
+	}
+	i := len(line) - 2
+	if i < 0 || line[i] != '\r' {
+		return nil, resp3ProtocolError("bad response line terminator")
+	}
+	return line[:i], nil
+}
+
+func readRESP3Blob(br *bufio.Reader, n int) ([]byte, error) {
+	p := make([]byte, n)
+	if _, err := io.ReadFull(br, p); err != nil {
+		return nil, err
+	}
+	if line, err := readRESP3Line(br); err != nil {
+		return nil, err
+	} else if len(line) != 0 {
+		return nil, resp3ProtocolError("bad bulk string format")
+	}
+	return p, nil
+}
+
+func readRESP3Elements(br *bufio.Reader, n int) ([]interface{}, error) {
+	elems := make([]interface{}, n)
+	for i := range elems {
+		var err error
+		if elems[i], err = readRESP3Reply(br); err != nil {
+			return nil, err
+		}
+	}
+	return elems, nil
+}
+
+// internal function
+// readRESP3Reply reads a single RESP2 or RESP3 reply
+func readRESP3Reply(br *bufio.Reader) (interface{}, error) {
+	line, err := readRESP3Line(br)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, resp3ProtocolError("short response line")
+	}
+	body := line[1:]
+	switch line[0] {
+	case '+':
+		return string(body), nil
+	case '-':
+		return redis.Error(body), nil
+	case ':':
+		return strconv.ParseInt(string(body), 10, 64)
+	case ',', '(':
+		// doubles and big numbers are kept as text, like RESP2 numeric bulk strings
+		return append([]byte{}, body...), nil
+	case '#':
+		if string(body) == "t" {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case '_':
+		return nil, nil
+	case '$', '=', '!':
+		n, err := strconv.Atoi(string(body))
+		if err != nil {
+			return nil, resp3ProtocolError("bad blob length")
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		p, err := readRESP3Blob(br, n)
+		if err != nil {
+			return nil, err
+		}
+		switch line[0] {
+		case '!':
+			return redis.Error(p), nil
+		case '=':
+			// verbatim strings are prefixed by their 3 characters format and a colon
+			if len(p) >= 4 && p[3] == ':' {
+				return p[4:], nil
+			}
+		}
+		return p, nil
+	case '*', '~', '%', '|', '>':
+		n, err := strconv.Atoi(string(body))
+		if err != nil {
+			return nil, resp3ProtocolError("bad aggregate length")
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		kind := line[0]
+		if kind == '%' || kind == '|' {
+			n *= 2
+		}
+		elems, err := readRESP3Elements(br, n)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case '%':
+			return RESP3Map(elems), nil
+		case '|', '>':
+			// attributes precede the reply they describe, push messages are out of band
+			return readRESP3Reply(br)
+		}
+		return elems, nil
+	}
+	return nil, resp3ProtocolError("unexpected response line")
+}
+
+// internal function
+// replyValues converts an array or a map reply to a flat array
+func replyValues(reply interface{}, err error) ([]interface{}, error) {
+	if m, ok := reply.(RESP3Map); ok && err == nil {
+		return []interface{}(m), nil
+	}
+	return redis.Values(reply, err)
+}
+
+// internal function
+// replyWarnings returns the warnings of a RESP3 search or aggregate reply, e.g. on timeouts or partial results.
+// RESP2 replies have no warnings.
+func replyWarnings(reply interface{}) []string {
+	if values, ok := reply.([]interface{}); ok && len(values) == 2 {
+		// cursor reply
+		reply = values[0]
+	}
+	m, ok := reply.(RESP3Map)
+	if !ok {
+		return nil
+	}
+	value, ok := m.Get("warning")
+	if !ok {
+		return nil
+	}
+	warnings, _ := redis.Strings(value, nil)
+	if len(warnings) == 0 {
+		return nil
+	}
+	return warnings
+}
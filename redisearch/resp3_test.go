@@ -0,0 +1,153 @@
+package redisearch
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readRESP3Reply(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{"simple-string", "+OK\r\n", "OK"},
+		{"error", "-ERR bad\r\n", redis.Error("ERR bad")},
+		{"integer", ":42\r\n", int64(42)},
+		{"bulk", "$3\r\nfoo\r\n", []byte("foo")},
+		{"nil-bulk", "$-1\r\n", nil},
+		{"null", "_\r\n", nil},
+		{"double", ",1.5\r\n", []byte("1.5")},
+		{"big-number", "(3492890328409238509324850943850943825024385\r\n", []byte("3492890328409238509324850943850943825024385")},
+		{"boolean", "#t\r\n", int64(1)},
+		{"blob-error", "!7\r\nERR bad\r\n", redis.Error("ERR bad")},
+		{"verbatim", "=7\r\ntxt:foo\r\n", []byte("foo")},
+		{"array", "*2\r\n:1\r\n$1\r\na\r\n", []interface{}{int64(1), []byte("a")}},
+		{"set", "~1\r\n+a\r\n", []interface{}{"a"}},
+		{"map", "%2\r\n+a\r\n:1\r\n+b\r\n*0\r\n", RESP3Map{"a", int64(1), "b", []interface{}{}}},
+		{"attribute", "|1\r\n+ttl\r\n:3\r\n+OK\r\n", "OK"},
+		{"push", ">2\r\n+invalidate\r\n*0\r\n:7\r\n", int64(7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readRESP3Reply(bufio.NewReader(strings.NewReader(tt.input)))
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := readRESP3Reply(bufio.NewReader(strings.NewReader("?\r\n")))
+	assert.NotNil(t, err)
+}
+
+func Test_resp3Conn(t *testing.T) {
+	client, server := net.Pipe()
+	c := &resp3Conn{conn: client, br: bufio.NewReader(client), bw: bufio.NewWriter(client)}
+	defer c.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		br := bufio.NewReader(server)
+		var sb strings.Builder
+		for i := 0; i < 10; i++ {
+			line, _ := br.ReadString('\n')
+			sb.WriteString(line)
+		}
+		received <- sb.String()
+		server.Write([]byte("%1\r\n+server\r\n+redis\r\n-ERR unknown\r\n"))
+	}()
+
+	assert.Nil(t, c.Send("HELLO", 3))
+	reply, err := c.Do("FT.INFO", "idx")
+	assert.Equal(t, "*2\r\n$5\r\nHELLO\r\n$1\r\n3\r\n*2\r\n$7\r\nFT.INFO\r\n$3\r\nidx\r\n", <-received)
+	assert.Nil(t, reply)
+	assert.Equal(t, redis.Error("ERR unknown"), err)
+	assert.Nil(t, c.Err())
+}
+
+func Test_processSearchReply_RESP3(t *testing.T) {
+	reply := RESP3Map{
+		"attributes", []interface{}{},
+		"format", "STRING",
+		"results", []interface{}{
+			RESP3Map{"id", []byte("doc1"), "score", []byte("1.5"), "extra_attributes", RESP3Map{[]byte("foo"), []byte("bar")}, "values", []interface{}{}},
+			RESP3Map{"id", []byte("doc2")},
+		},
+		"total_results", int64(5),
+		"warning", []interface{}{[]byte("Timeout limit was reached")},
+	}
+	docs, total, err := processSearchReply(NewQuery("foo"), reply)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, []Document{
+		{Id: "doc1", Score: 1.5, Properties: map[string]interface{}{"foo": "bar"}},
+		{Id: "doc2", Score: 1, Properties: map[string]interface{}{}},
+	}, docs)
+	assert.Equal(t, []string{"Timeout limit was reached"}, replyWarnings(reply))
+	assert.Nil(t, replyWarnings([]interface{}{int64(0)}))
+}
+
+func Test_processAggQueryReply_RESP3(t *testing.T) {
+	reply := RESP3Map{
+		"results", []interface{}{
+			RESP3Map{"extra_attributes", RESP3Map{[]byte("brand"), []byte("a"), []byte("count"), []byte("3")}, "values", []interface{}{}},
+		},
+		"total_results", int64(1),
+		"warning", []interface{}{},
+	}
+	total, rows, err := processAggQueryReply(reply)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []map[string]interface{}{{"brand": "a", "count": "3"}}, rows)
+
+	q := NewAggregateQuery().SetCursor(NewCursor())
+	total, rows, err = q.processReply([]interface{}{reply, int64(12)})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 12, q.Cursor.Id)
+
+	_, legacy, err := processAggReply(reply)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"brand", "a", "count", "3"}}, legacy)
+	assert.Nil(t, replyWarnings(reply))
+}
+
+func Test_processSpellCheckReply_RESP3(t *testing.T) {
+	reply := RESP3Map{
+		"results", RESP3Map{
+			[]byte("helo"), []interface{}{RESP3Map{[]byte("hello"), []byte("0.6")}, RESP3Map{[]byte("help"), []byte("0.2")}},
+			[]byte("wrld"), []interface{}{},
+		},
+	}
+	suggs, total, err := processSpellCheckReply(reply)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 2, len(suggs))
+	assert.Equal(t, "helo", suggs[0].Term)
+	assert.Equal(t, []MisspelledSuggestion{NewMisspelledSuggestion("hello", 0.6), NewMisspelledSuggestion("help", 0.2)}, suggs[0].MisspelledSuggestionList)
+}
+
+func Test_loadIndexInfo_RESP3(t *testing.T) {
+	reply := RESP3Map{
+		"index_name", []byte("idx"),
+		"index_options", []interface{}{},
+		"attributes", []interface{}{
+			RESP3Map{"identifier", []byte("title"), "attribute", []byte("title"), "type", "TEXT", "WEIGHT", []byte("1"), "flags", []interface{}{"SORTABLE"}},
+		},
+		"num_docs", int64(3),
+		"gc_stats", RESP3Map{"bytes_collected", int64(12)},
+		"index_definition", RESP3Map{"key_type", "HASH"},
+	}
+	info, err := loadIndexInfo([]interface{}(reply))
+	assert.Nil(t, err)
+	assert.Equal(t, "idx", info.Name)
+	assert.Equal(t, uint64(3), info.DocCount)
+	assert.Equal(t, uint64(12), info.GCStats.BytesCollected)
+	assert.Equal(t, []string{"SORTABLE"}, info.Attributes[0].Flags)
+	assert.True(t, info.Schema.Fields[0].Sortable)
+	assert.Equal(t, map[string]interface{}{"key_type": "HASH"}, info.Raw["index_definition"])
+}
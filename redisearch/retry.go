@@ -0,0 +1,250 @@
+package redisearch
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RetryPolicy configures the retries of the commands failing with transient errors, e.g. LOADING replies
+// during a failover or broken pooled connections
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a command, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+	// Multiplier is the factor applied to the delay after each retry
+	Multiplier float64
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1
+	Jitter float64
+	// MaxElapsed caps the total time spent retrying a command, 0 meaning no limit
+	MaxElapsed time.Duration
+	// Retryable returns whether an error is transient. Defaults to IsRetryableError
+	Retryable func(err error) bool
+	// Idempotent returns whether a command can be run several times. Defaults to IsIdempotentCommand
+	Idempotent func(cmd string, args []interface{}) bool
+	// RetryNonIdempotent retries all the commands, including the non-idempotent ones
+	RetryNonIdempotent bool
+
+	sleep func(time.Duration)
+}
+
+// DefaultRetryPolicy returns a policy running up to 3 attempts, with an exponential backoff from 50ms to 1s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     5 * time.Second,
+	}
+}
+
+// SetRetryPolicy retries the commands of the client failing with transient errors, according to the policy.
+// Pipelined commands (e.g. IndexOptions or AddTerms) are never retried, as they may have been partially applied.
+// A nil policy disables the retries. SetRetryPolicy must not be called concurrently with other methods.
+func (i *Client) SetRetryPolicy(policy *RetryPolicy) {
+	i.pool = withRetryPolicy(i.pool, policy)
+}
+
+// SetRetryPolicy retries the commands of the autocompleter failing with transient errors, according to the policy.
+// See Client.SetRetryPolicy
+func (a *Autocompleter) SetRetryPolicy(policy *RetryPolicy) {
+	a.pool = withRetryPolicy(a.pool, policy)
+}
+
+// internal function
+// withRetryPolicy wraps the pool so that its connections retry the commands, replacing the previous policy if any,
+// even when hooks were added after it
+func withRetryPolicy(pool ConnPool, policy *RetryPolicy) ConnPool {
+	replaced, ok := replaceWrapper(pool, func(p ConnPool) (ConnPool, bool) {
+		rp, ok := p.(*retryPool)
+		if !ok {
+			return p, false
+		}
+		if policy == nil {
+			return rp.pool, true
+		}
+		return &retryPool{pool: rp.pool, policy: policy}, true
+	})
+	if ok {
+		return replaced
+	}
+	if policy == nil {
+		return pool
+	}
+	return &retryPool{pool: pool, policy: policy}
+}
+
+// transientErrorPrefixes are the prefixes of the server errors worth a retry
+var transientErrorPrefixes = []string{"LOADING", "TRYAGAIN", "BUSY ", "CLUSTERDOWN", "MASTERDOWN", "TIMEOUT"}
+
+// IsRetryableError returns whether the error is transient: a LOADING, TRYAGAIN, BUSY, CLUSTERDOWN, MASTERDOWN
+// or query Timeout reply, or a network error
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if redisErr, ok := err.(redis.Error); ok {
+		msg := strings.ToUpper(string(redisErr))
+		for _, prefix := range transientErrorPrefixes {
+			if strings.HasPrefix(msg, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
+
+// idempotentCommands are the commands that can be safely run several times
+var idempotentCommands = map[string]bool{
+	"FT.SEARCH":      true,
+	"FT.AGGREGATE":   true,
+	"FT.PROFILE":     true,
+	"FT.EXPLAIN":     true,
+	"FT.INFO":        true,
+	"FT.SPELLCHECK":  true,
+	"FT.SUGGET":      true,
+	"FT.SUGLEN":      true,
+	"FT.TAGVALS":     true,
+	"FT.SYNDUMP":     true,
+	"FT.SYNUPDATE":   true,
+	"FT.DICTADD":     true,
+	"FT.DICTDEL":     true,
+	"FT.DICTDUMP":    true,
+	"FT._LIST":       true,
+	"FT.ALIASUPDATE": true,
+	"FT.CONFIG":      true,
+	"FT.GET":         true,
+	"FT.MGET":        true,
+	"HGET":           true,
+	"HGETALL":        true,
+	"HMGET":          true,
+	"HSET":           true,
+	"PING":           true,
+}
+
+// IsIdempotentCommand returns whether the command can be safely run several times: reads, and writes after
+// which the stored state converges to the same value however many times they run, such as HSET, FT.DICTADD,
+// FT.SYNUPDATE, FT.ALIASUPDATE or FT.SUGADD without INCR. The reply of a repeated write may differ (e.g. HSET
+// and FT.DICTADD return the number of added fields or terms). Cursor reads and counters are not idempotent.
+func IsIdempotentCommand(cmd string, args []interface{}) bool {
+	name := strings.ToUpper(cmd)
+	if name == "FT.SUGADD" {
+		return !hasArg(args, "INCR")
+	}
+	return idempotentCommands[name]
+}
+
+// internal method
+// backoff returns the delay before the given retry, starting at 1
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// internal method
+// retryable returns whether the failed command can be retried
+func (p *RetryPolicy) retryable(cmd string, args []interface{}, err error) bool {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+	if !retryable(err) {
+		return false
+	}
+	if p.RetryNonIdempotent {
+		return true
+	}
+	idempotent := p.Idempotent
+	if idempotent == nil {
+		idempotent = IsIdempotentCommand
+	}
+	return idempotent(cmd, args)
+}
+
+// internal struct
+// retryPool is a ConnPool returning connections that retry the commands
+type retryPool struct {
+	pool   ConnPool
+	policy *RetryPolicy
+}
+
+func (p *retryPool) Get() redis.Conn {
+	return &retryConn{Conn: p.pool.Get(), pool: p.pool, policy: p.policy}
+}
+
+func (p *retryPool) Close() error {
+	return p.pool.Close()
+}
+
+// internal struct
+// retryConn retries the commands run with Do. It replaces the underlying connection when it is broken.
+type retryConn struct {
+	redis.Conn
+	pool    ConnPool
+	policy  *RetryPolicy
+	pending int
+}
+
+func (c *retryConn) Send(commandName string, args ...interface{}) error {
+	c.pending++
+	return c.Conn.Send(commandName, args...)
+}
+
+func (c *retryConn) Receive() (interface{}, error) {
+	if c.pending > 0 {
+		c.pending--
+	}
+	return c.Conn.Receive()
+}
+
+func (c *retryConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	// MaxElapsed includes the first attempt
+	start := time.Now()
+	reply, err := c.Conn.Do(commandName, args...)
+	if commandName == "" || c.pending > 0 {
+		c.pending = 0
+		return reply, err
+	}
+	sleep := c.policy.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	for retry := 1; err != nil && retry < c.policy.MaxAttempts && c.policy.retryable(commandName, args, err); retry++ {
+		delay := c.policy.backoff(retry)
+		if c.policy.MaxElapsed > 0 && time.Since(start)+delay > c.policy.MaxElapsed {
+			break
+		}
+		sleep(delay)
+		if _, isServerErr := err.(redis.Error); !isServerErr {
+			// the connection is broken, retry on a fresh one
+			c.Conn.Close()
+			c.Conn = c.pool.Get()
+		}
+		reply, err = c.Conn.Do(commandName, args...)
+	}
+	return reply, err
+}
@@ -0,0 +1,163 @@
+package redisearch
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy(sleeps *[]time.Duration) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	policy.sleep = func(d time.Duration) { *sleeps = append(*sleeps, d) }
+	return policy
+}
+
+func TestClient_SetRetryPolicy(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{
+		{Err: redis.Error("LOADING Redis is loading the dataset in memory")},
+		{Err: io.EOF},
+		{Value: []interface{}{int64(0)}},
+	}}
+	var sleeps []time.Duration
+	c := NewClientFromExecutor(exec, "idx")
+	c.SetRetryPolicy(testRetryPolicy(&sleeps))
+
+	_, total, err := c.Search(NewQuery("hello"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, total)
+	assert.Len(t, exec.cmds, 3)
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}, sleeps)
+
+	// attempts are capped
+	exec.replies = []Reply{{Err: io.EOF}, {Err: io.EOF}, {Err: io.EOF}, {Value: []interface{}{int64(0)}}}
+	_, _, err = c.Search(NewQuery("hello"))
+	assert.Equal(t, io.EOF, err)
+	assert.Len(t, exec.replies, 1)
+
+	// disabled retries
+	c.SetRetryPolicy(nil)
+	exec.replies = []Reply{{Err: io.EOF}}
+	_, _, err = c.Search(NewQuery("hello"))
+	assert.Equal(t, io.EOF, err)
+}
+
+// slowHook delays the first command it sees
+type slowHook struct {
+	delay time.Duration
+	calls int
+}
+
+func (h *slowHook) BeforeCommand(cmd *CommandInfo) error {
+	if h.calls == 0 {
+		time.Sleep(h.delay)
+	}
+	h.calls++
+	return nil
+}
+
+func (h *slowHook) AfterCommand(cmd *CommandInfo) {}
+
+func TestClient_SetRetryPolicy_hooks(t *testing.T) {
+	exec := &fakeExecutor{}
+	var sleeps []time.Duration
+	hook := &recordingHook{}
+	c := NewClientFromExecutor(exec, "idx")
+	c.SetRetryPolicy(testRetryPolicy(&sleeps))
+	c.AddHook(hook)
+
+	// the policy is replaced under the hooks, not stacked
+	policy := testRetryPolicy(&sleeps)
+	policy.MaxAttempts = 2
+	c.SetRetryPolicy(policy)
+	exec.replies = []Reply{{Err: io.EOF}, {Err: io.EOF}, {Value: []interface{}{int64(0)}}}
+	_, _, err := c.Search(NewQuery("hello"))
+	assert.Equal(t, io.EOF, err)
+	assert.Len(t, exec.cmds, 2)
+	assert.Len(t, sleeps, 1)
+
+	// the retries are disabled under the hooks
+	c.SetRetryPolicy(nil)
+	exec.replies = []Reply{{Err: io.EOF}, {Value: []interface{}{int64(0)}}}
+	_, _, err = c.Search(NewQuery("hello"))
+	assert.Equal(t, io.EOF, err)
+	assert.Len(t, exec.cmds, 3)
+	assert.Len(t, sleeps, 1)
+	assert.Equal(t, &hookPool{pool: &executorPool{exec: exec}, hooks: []Hook{hook}}, c.pool)
+
+	// the hooks are appended to the existing ones, under the retries
+	second := &recordingHook{}
+	c.SetRetryPolicy(policy)
+	c.AddHook(second)
+	assert.Equal(t, &retryPool{pool: &hookPool{pool: &executorPool{exec: exec}, hooks: []Hook{hook, second}}, policy: policy}, c.pool)
+}
+
+func TestClient_SetRetryPolicy_maxElapsed(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Err: io.EOF}, {Value: []interface{}{int64(0)}}}}
+	var sleeps []time.Duration
+	policy := testRetryPolicy(&sleeps)
+	policy.MaxElapsed = 60 * time.Millisecond
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(&slowHook{delay: 20 * time.Millisecond})
+	c.SetRetryPolicy(policy)
+
+	// the first attempt and the 50ms backoff exceed MaxElapsed
+	_, _, err := c.Search(NewQuery("hello"))
+	assert.Equal(t, io.EOF, err)
+	assert.Empty(t, sleeps)
+}
+
+func TestAutocompleter_SetRetryPolicy(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Err: redis.Error("TRYAGAIN")}, {Value: int64(1)}}}
+	var sleeps []time.Duration
+	a := NewAutocompleterFromExecutor(exec, "ac")
+	a.SetRetryPolicy(testRetryPolicy(&sleeps))
+
+	// FT.SUGADD INCR is not idempotent
+	_, err := a.pool.Get().Do("FT.SUGADD", "ac", "term", 1, "INCR")
+	assert.Equal(t, redis.Error("TRYAGAIN"), err)
+	assert.Empty(t, sleeps)
+
+	exec.replies = []Reply{{Err: redis.Error("TRYAGAIN")}, {Value: int64(1)}}
+	n, err := redis.Int(a.pool.Get().Do("FT.SUGADD", "ac", "term", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, sleeps, 1)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	assert.True(t, IsRetryableError(redis.Error("LOADING Redis is loading the dataset in memory")))
+	assert.True(t, IsRetryableError(redis.Error("Timeout limit was reached")))
+	assert.True(t, IsRetryableError(io.ErrUnexpectedEOF))
+	assert.False(t, IsRetryableError(redis.Error("Unknown index name")))
+	assert.False(t, IsRetryableError(errors.New("redigo: connection pool exhausted")))
+	assert.False(t, IsRetryableError(nil))
+}
+
+func TestIsIdempotentCommand(t *testing.T) {
+	assert.True(t, IsIdempotentCommand("FT.SEARCH", []interface{}{"idx", "*"}))
+	assert.True(t, IsIdempotentCommand("ft.info", []interface{}{"idx"}))
+	assert.False(t, IsIdempotentCommand("FT.CURSOR", []interface{}{"READ", "idx", 1}))
+	assert.True(t, IsIdempotentCommand("FT.DICTADD", []interface{}{"dict", "term"}))
+	assert.True(t, IsIdempotentCommand("HSET", []interface{}{"doc1", "f", "v"}))
+	assert.False(t, IsIdempotentCommand("HINCRBY", []interface{}{"doc1", "f", 1}))
+	assert.False(t, IsIdempotentCommand("FT.SUGADD", []interface{}{"ac", "term", 1, "INCR"}))
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+	assert.Equal(t, 50*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(4))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := policy.backoff(2)
+		assert.True(t, d >= 50*time.Millisecond && d <= 100*time.Millisecond, d)
+	}
+}
@@ -319,7 +319,7 @@ func (m *Schema) AddField(f Field) *Schema {
 	return m
 }
 
-func SerializeSchema(s *Schema, args redis.Args) (argsOut redis.Args, err error) {
+func SerializeSchema(s *Schema, args []interface{}) (argsOut []interface{}, err error) {
 	argsOut = args
 	if s.Options.MaxTextFieldsFlag {
 		argsOut = append(argsOut, "MAXTEXTFIELDS")
@@ -344,9 +344,9 @@ func SerializeSchema(s *Schema, args redis.Args) (argsOut redis.Args, err error)
 	}
 
 	if s.Options.Stopwords != nil {
-		argsOut = argsOut.Add("STOPWORDS", len(s.Options.Stopwords))
+		argsOut = redis.Args(argsOut).Add("STOPWORDS", len(s.Options.Stopwords))
 		if len(s.Options.Stopwords) > 0 {
-			argsOut = argsOut.AddFlat(s.Options.Stopwords)
+			argsOut = redis.Args(argsOut).AddFlat(s.Options.Stopwords)
 		}
 	}
 
@@ -360,7 +360,7 @@ func SerializeSchema(s *Schema, args redis.Args) (argsOut redis.Args, err error)
 	return
 }
 
-func serializeField(f Field, args redis.Args) (argsOut redis.Args, err error) {
+func serializeField(f Field, args []interface{}) (argsOut []interface{}, err error) {
 	argsOut = args
 	switch f.Type {
 	case TextField:
@@ -460,8 +460,8 @@ func serializeField(f Field, args redis.Args) (argsOut redis.Args, err error) {
 			}
 			if opts.Attributes != nil {
 				var flat []interface{}
-				for attrName, attrValue := range opts.Attributes {
-					flat = append(flat, attrName, attrValue)
+				for _, attrName := range sortedKeys(opts.Attributes) {
+					flat = append(flat, attrName, opts.Attributes[attrName])
 				}
 				argsOut = append(argsOut, len(flat))
 				argsOut = append(argsOut, flat...)
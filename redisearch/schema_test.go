@@ -1,7 +1,6 @@
 package redisearch
 
 import (
-	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
@@ -40,46 +39,46 @@ func TestNewSchema(t *testing.T) {
 func TestSerializeSchema(t *testing.T) {
 	type args struct {
 		s    *Schema
-		args redis.Args
+		args []interface{}
 	}
 	tests := []struct {
 		name    string
 		args    args
-		want    redis.Args
+		want    []interface{}
 		wantErr bool
 	}{
 
-		{"default-args", args{NewSchema(DefaultOptions), redis.Args{}}, redis.Args{"SCHEMA"}, false},
-		{"maxtextfields", args{NewSchema(Options{MaxTextFieldsFlag: true}), redis.Args{}}, redis.Args{"MAXTEXTFIELDS", "SCHEMA"}, false},
-		{"maxtextfields-with-different-consturctor", args{NewSchema(*NewOptions().SetMaxTextFieldsFlag(true)), redis.Args{}}, redis.Args{"MAXTEXTFIELDS", "SCHEMA"}, false},
-		{"default-args-with-different-constructor", args{NewSchema(*NewOptions()), redis.Args{}}, redis.Args{"SCHEMA"}, false},
-		{"temporary", args{NewSchema(*NewOptions().SetTemporary(true)), redis.Args{}}, redis.Args{"TEMPORARY", 0, "SCHEMA"}, false},
-		{"temporary-period", args{NewSchema(*NewOptions().SetTemporaryPeriod(60)), redis.Args{}}, redis.Args{"TEMPORARY", 60, "SCHEMA"}, false},
-		{"no-frequencies", args{NewSchema(Options{NoFrequencies: true}), redis.Args{}}, redis.Args{"NOFREQS", "SCHEMA"}, false},
-		{"no-hithlights", args{NewSchema(Options{NoHighlights: true}), redis.Args{}}, redis.Args{"NOHL", "SCHEMA"}, false},
-		{"no-hithlights-with-different-consturctor", args{NewSchema(*NewOptions().SetNoHighlight(true)), redis.Args{}}, redis.Args{"NOHL", "SCHEMA"}, false},
-		{"skip-inital-scan", args{NewSchema(Options{SkipInitialScan: true}), redis.Args{}}, redis.Args{"SKIPINITIALSCAN", "SCHEMA"}, false},
-		{"skipinitalscan-with-different-consturctor", args{NewSchema(*NewOptions().SetSkipInitialScan(true)), redis.Args{}}, redis.Args{"SKIPINITIALSCAN", "SCHEMA"}, false},
-		{"no-fields", args{NewSchema(Options{NoFieldFlags: true}), redis.Args{}}, redis.Args{"NOFIELDS", "SCHEMA"}, false},
-		{"custom-stopwords", args{NewSchema(Options{Stopwords: []string{"custom"}}), redis.Args{}}, redis.Args{"STOPWORDS", 1, "custom", "SCHEMA"}, false},
-		{"custom-stopwords-with-different-constructor", args{NewSchema(*NewOptions().SetStopWords([]string{"custom"})), redis.Args{}}, redis.Args{"STOPWORDS", 1, "custom", "SCHEMA"}, false},
-		{"no-offsets", args{NewSchema(Options{NoOffsetVectors: true}), redis.Args{}}, redis.Args{"NOOFFSETS", "SCHEMA"}, false},
-		{"default-and-numeric", args{NewSchema(DefaultOptions).AddField(NewNumericField("numeric-field")), redis.Args{}}, redis.Args{"SCHEMA", "numeric-field", "NUMERIC"}, false},
-		{"default-and-numeric-sortable", args{NewSchema(DefaultOptions).AddField(NewSortableNumericField("numeric-field")), redis.Args{}}, redis.Args{"SCHEMA", "numeric-field", "NUMERIC", "SORTABLE"}, false},
-		{"default-and-numeric-with-options-noindex", args{NewSchema(DefaultOptions).AddField(NewNumericFieldOptions("numeric-field", NumericFieldOptions{NoIndex: true, Sortable: false})), redis.Args{}}, redis.Args{"SCHEMA", "numeric-field", "NUMERIC", "NOINDEX"}, false},
-		{"default-and-text", args{NewSchema(DefaultOptions).AddField(NewTextField("text-field")), redis.Args{}}, redis.Args{"SCHEMA", "text-field", "TEXT"}, false},
-		{"default-and-sortable-text-field", args{NewSchema(DefaultOptions).AddField(NewSortableTextField("text-field", 10)), redis.Args{}}, redis.Args{"SCHEMA", "text-field", "TEXT", "WEIGHT", float32(10.0), "SORTABLE"}, false},
-		{"default-and-text-with-options", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{Weight: 5.0, Sortable: true, NoStem: false, NoIndex: false, As: "field"})), redis.Args{}}, redis.Args{"SCHEMA", "text-field", "AS", "field", "TEXT", "WEIGHT", float32(5.0), "SORTABLE"}, false},
-		{"default-and-text-with-phonetic-en", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{PhoneticMatcher: PhoneticDoubleMetaphoneEnglish})), redis.Args{}}, redis.Args{"SCHEMA", "text-field", "TEXT", "PHONETIC", "dm:en"}, false},
-		{"default-and-text-with-phonetic-pt", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{PhoneticMatcher: PhoneticDoubleMetaphonePortuguese})), redis.Args{}}, redis.Args{"SCHEMA", "text-field", "TEXT", "PHONETIC", "dm:pt"}, false},
-		{"default-and-tag", args{NewSchema(DefaultOptions).AddField(NewTagField("tag-field")), redis.Args{}}, redis.Args{"SCHEMA", "tag-field", "TAG", "SEPARATOR", ","}, false},
-		{"default-and-tag-with-options", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Sortable: true, NoIndex: false, Separator: byte(','), As: "field"})), redis.Args{}}, redis.Args{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "SORTABLE"}, false},
-		{"default-and-tag-with-options_2", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Sortable: true, NoIndex: true, Separator: byte(','), As: "field"})), redis.Args{}}, redis.Args{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "SORTABLE", "NOINDEX"}, false},
-		{"default-and-tag-with-options_3", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Separator: byte(','), CaseSensitive: true, As: "field"})), redis.Args{}}, redis.Args{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "CASESENSITIVE"}, false},
-		{"default-geo-with-options", args{NewSchema(DefaultOptions).AddField(NewGeoFieldOptions("location", GeoFieldOptions{As: "loc"})), redis.Args{}}, redis.Args{"SCHEMA", "location", "AS", "loc", "GEO"}, false},
-		{"default-geo-with-options_2", args{NewSchema(DefaultOptions).AddField(NewGeoFieldOptions("location", GeoFieldOptions{As: "loc", NoIndex: true})), redis.Args{}}, redis.Args{"SCHEMA", "location", "AS", "loc", "GEO", "NOINDEX"}, false},
-		{"default-vector", args{NewSchema(DefaultOptions).AddField(NewVectorFieldOptions("vec", VectorFieldOptions{Algorithm: Flat, Attributes: map[string]interface{}{"DIM": 128}})), redis.Args{}}, redis.Args{"SCHEMA", "vec", "VECTOR", Flat, 2, "DIM", 128}, false},
-		{"error-unsupported", args{NewSchema(DefaultOptions).AddField(Field{Type: 10}), redis.Args{}}, nil, true},
+		{"default-args", args{NewSchema(DefaultOptions), []interface{}{}}, []interface{}{"SCHEMA"}, false},
+		{"maxtextfields", args{NewSchema(Options{MaxTextFieldsFlag: true}), []interface{}{}}, []interface{}{"MAXTEXTFIELDS", "SCHEMA"}, false},
+		{"maxtextfields-with-different-consturctor", args{NewSchema(*NewOptions().SetMaxTextFieldsFlag(true)), []interface{}{}}, []interface{}{"MAXTEXTFIELDS", "SCHEMA"}, false},
+		{"default-args-with-different-constructor", args{NewSchema(*NewOptions()), []interface{}{}}, []interface{}{"SCHEMA"}, false},
+		{"temporary", args{NewSchema(*NewOptions().SetTemporary(true)), []interface{}{}}, []interface{}{"TEMPORARY", 0, "SCHEMA"}, false},
+		{"temporary-period", args{NewSchema(*NewOptions().SetTemporaryPeriod(60)), []interface{}{}}, []interface{}{"TEMPORARY", 60, "SCHEMA"}, false},
+		{"no-frequencies", args{NewSchema(Options{NoFrequencies: true}), []interface{}{}}, []interface{}{"NOFREQS", "SCHEMA"}, false},
+		{"no-hithlights", args{NewSchema(Options{NoHighlights: true}), []interface{}{}}, []interface{}{"NOHL", "SCHEMA"}, false},
+		{"no-hithlights-with-different-consturctor", args{NewSchema(*NewOptions().SetNoHighlight(true)), []interface{}{}}, []interface{}{"NOHL", "SCHEMA"}, false},
+		{"skip-inital-scan", args{NewSchema(Options{SkipInitialScan: true}), []interface{}{}}, []interface{}{"SKIPINITIALSCAN", "SCHEMA"}, false},
+		{"skipinitalscan-with-different-consturctor", args{NewSchema(*NewOptions().SetSkipInitialScan(true)), []interface{}{}}, []interface{}{"SKIPINITIALSCAN", "SCHEMA"}, false},
+		{"no-fields", args{NewSchema(Options{NoFieldFlags: true}), []interface{}{}}, []interface{}{"NOFIELDS", "SCHEMA"}, false},
+		{"custom-stopwords", args{NewSchema(Options{Stopwords: []string{"custom"}}), []interface{}{}}, []interface{}{"STOPWORDS", 1, "custom", "SCHEMA"}, false},
+		{"custom-stopwords-with-different-constructor", args{NewSchema(*NewOptions().SetStopWords([]string{"custom"})), []interface{}{}}, []interface{}{"STOPWORDS", 1, "custom", "SCHEMA"}, false},
+		{"no-offsets", args{NewSchema(Options{NoOffsetVectors: true}), []interface{}{}}, []interface{}{"NOOFFSETS", "SCHEMA"}, false},
+		{"default-and-numeric", args{NewSchema(DefaultOptions).AddField(NewNumericField("numeric-field")), []interface{}{}}, []interface{}{"SCHEMA", "numeric-field", "NUMERIC"}, false},
+		{"default-and-numeric-sortable", args{NewSchema(DefaultOptions).AddField(NewSortableNumericField("numeric-field")), []interface{}{}}, []interface{}{"SCHEMA", "numeric-field", "NUMERIC", "SORTABLE"}, false},
+		{"default-and-numeric-with-options-noindex", args{NewSchema(DefaultOptions).AddField(NewNumericFieldOptions("numeric-field", NumericFieldOptions{NoIndex: true, Sortable: false})), []interface{}{}}, []interface{}{"SCHEMA", "numeric-field", "NUMERIC", "NOINDEX"}, false},
+		{"default-and-text", args{NewSchema(DefaultOptions).AddField(NewTextField("text-field")), []interface{}{}}, []interface{}{"SCHEMA", "text-field", "TEXT"}, false},
+		{"default-and-sortable-text-field", args{NewSchema(DefaultOptions).AddField(NewSortableTextField("text-field", 10)), []interface{}{}}, []interface{}{"SCHEMA", "text-field", "TEXT", "WEIGHT", float32(10.0), "SORTABLE"}, false},
+		{"default-and-text-with-options", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{Weight: 5.0, Sortable: true, NoStem: false, NoIndex: false, As: "field"})), []interface{}{}}, []interface{}{"SCHEMA", "text-field", "AS", "field", "TEXT", "WEIGHT", float32(5.0), "SORTABLE"}, false},
+		{"default-and-text-with-phonetic-en", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{PhoneticMatcher: PhoneticDoubleMetaphoneEnglish})), []interface{}{}}, []interface{}{"SCHEMA", "text-field", "TEXT", "PHONETIC", "dm:en"}, false},
+		{"default-and-text-with-phonetic-pt", args{NewSchema(DefaultOptions).AddField(NewTextFieldOptions("text-field", TextFieldOptions{PhoneticMatcher: PhoneticDoubleMetaphonePortuguese})), []interface{}{}}, []interface{}{"SCHEMA", "text-field", "TEXT", "PHONETIC", "dm:pt"}, false},
+		{"default-and-tag", args{NewSchema(DefaultOptions).AddField(NewTagField("tag-field")), []interface{}{}}, []interface{}{"SCHEMA", "tag-field", "TAG", "SEPARATOR", ","}, false},
+		{"default-and-tag-with-options", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Sortable: true, NoIndex: false, Separator: byte(','), As: "field"})), []interface{}{}}, []interface{}{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "SORTABLE"}, false},
+		{"default-and-tag-with-options_2", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Sortable: true, NoIndex: true, Separator: byte(','), As: "field"})), []interface{}{}}, []interface{}{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "SORTABLE", "NOINDEX"}, false},
+		{"default-and-tag-with-options_3", args{NewSchema(DefaultOptions).AddField(NewTagFieldOptions("tag-field", TagFieldOptions{Separator: byte(','), CaseSensitive: true, As: "field"})), []interface{}{}}, []interface{}{"SCHEMA", "tag-field", "AS", "field", "TAG", "SEPARATOR", ",", "CASESENSITIVE"}, false},
+		{"default-geo-with-options", args{NewSchema(DefaultOptions).AddField(NewGeoFieldOptions("location", GeoFieldOptions{As: "loc"})), []interface{}{}}, []interface{}{"SCHEMA", "location", "AS", "loc", "GEO"}, false},
+		{"default-geo-with-options_2", args{NewSchema(DefaultOptions).AddField(NewGeoFieldOptions("location", GeoFieldOptions{As: "loc", NoIndex: true})), []interface{}{}}, []interface{}{"SCHEMA", "location", "AS", "loc", "GEO", "NOINDEX"}, false},
+		{"default-vector", args{NewSchema(DefaultOptions).AddField(NewVectorFieldOptions("vec", VectorFieldOptions{Algorithm: Flat, Attributes: map[string]interface{}{"DIM": 128}})), []interface{}{}}, []interface{}{"SCHEMA", "vec", "VECTOR", Flat, 2, "DIM", 128}, false},
+		{"error-unsupported", args{NewSchema(DefaultOptions).AddField(Field{Type: 10}), []interface{}{}}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
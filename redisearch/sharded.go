@@ -0,0 +1,489 @@
+package redisearch
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// shardReplicas is the number of points of each shard on the consistent hash ring
+const shardReplicas = 160
+
+// ShardedClient is an interface to an index split over several independent redis instances, each holding
+// a shard of the documents. Documents are placed on a shard by a consistent hash of their id; searches and
+// aggregations are sent to all the shards and their results are merged.
+//
+// Note that document scores are computed by each shard on its own documents, so that merging by score is
+// only an approximation of the ranking of a single index.
+type ShardedClient struct {
+	name   string
+	shards []string
+	pools  map[string]ConnPool
+	ring   []uint32
+	owners map[uint32]string
+}
+
+// NewShardedClient creates a sharded client over the given redis hosts, one shard per host:port pair,
+// using the given name as the index name on every shard
+func NewShardedClient(addrs []string, name string) *ShardedClient {
+	pools := make(map[string]ConnPool, len(addrs))
+	for _, addr := range addrs {
+		pools[addr] = NewSingleHostPool(addr)
+	}
+	return NewShardedClientFromPools(pools, name)
+}
+
+// NewShardedClientFromPools creates a sharded client with a pool per shard. The placement of the documents
+// only depends on the shard names, not on the order or on the addresses of the pools.
+func NewShardedClientFromPools(pools map[string]ConnPool, name string) *ShardedClient {
+	c := &ShardedClient{
+		name:   name,
+		shards: make([]string, 0, len(pools)),
+		pools:  pools,
+		ring:   make([]uint32, 0, len(pools)*shardReplicas),
+		owners: make(map[uint32]string, len(pools)*shardReplicas),
+	}
+	for shard := range pools {
+		c.shards = append(c.shards, shard)
+	}
+	sort.Strings(c.shards)
+	for _, shard := range c.shards {
+		for r := 0; r < shardReplicas; r++ {
+			point := crc32.ChecksumIEEE([]byte(shard + "#" + strconv.Itoa(r)))
+			if _, taken := c.owners[point]; taken {
+				continue
+			}
+			c.owners[point] = shard
+			c.ring = append(c.ring, point)
+		}
+	}
+	sort.Slice(c.ring, func(i, j int) bool { return c.ring[i] < c.ring[j] })
+	return c
+}
+
+// Shards returns the names of the shards, sorted
+func (c *ShardedClient) Shards() []string {
+	return append([]string(nil), c.shards...)
+}
+
+// ShardName returns the name of the shard holding the given document id
+func (c *ShardedClient) ShardName(docId string) string {
+	if len(c.ring) == 0 {
+		return ""
+	}
+	h := crc32.ChecksumIEEE([]byte(docId))
+	idx := sort.Search(len(c.ring), func(i int) bool { return c.ring[i] >= h })
+	if idx == len(c.ring) {
+		idx = 0
+	}
+	return c.owners[c.ring[idx]]
+}
+
+// Shard returns a Client bound to the index on the shard holding the given document id,
+// e.g. to write the document hash directly
+func (c *ShardedClient) Shard(docId string) *Client {
+	return c.client(c.ShardName(docId))
+}
+
+func (c *ShardedClient) client(shard string) *Client {
+	return &Client{pool: c.pools[shard], name: c.name}
+}
+
+// internal method
+// each runs fn concurrently on every shard, returning the errors aligned with Shards()
+func (c *ShardedClient) each(fn func(ii int, client *Client) error) error {
+	var merr MultiError
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for ii, shard := range c.shards {
+		wg.Add(1)
+		go func(ii int, client *Client) {
+			defer wg.Done()
+			if err := fn(ii, client); err != nil {
+				mu.Lock()
+				if merr == nil {
+					merr = NewMultiError(len(c.shards))
+				}
+				merr[ii] = fmt.Errorf("shard %s: %v", c.shards[ii], err)
+				mu.Unlock()
+			}
+		}(ii, c.client(shard))
+	}
+	wg.Wait()
+	if merr != nil {
+		return merr
+	}
+	return nil
+}
+
+// CreateIndex creates the index on every shard
+func (c *ShardedClient) CreateIndex(schema *Schema) error {
+	return c.CreateIndexWithIndexDefinition(schema, nil)
+}
+
+// CreateIndexWithIndexDefinition creates the index on every shard, with the given index definition
+func (c *ShardedClient) CreateIndexWithIndexDefinition(schema *Schema, definition *IndexDefinition) error {
+	return c.each(func(_ int, client *Client) error {
+		return client.indexWithDefinition(c.name, schema, definition)
+	})
+}
+
+// DropIndex deletes the index on every shard, and optionally the associated documents
+func (c *ShardedClient) DropIndex(deleteDocuments bool) error {
+	return c.each(func(_ int, client *Client) error {
+		return client.DropIndex(deleteDocuments)
+	})
+}
+
+// Index indexes the documents, each on the shard selected by its id.
+// If some documents failed, the error is a MultiError aligned with docs.
+func (c *ShardedClient) Index(docs ...Document) error {
+	return c.IndexOptions(DefaultIndexingOptions, docs...)
+}
+
+// IndexOptions indexes the documents with the given options, each on the shard selected by its id
+func (c *ShardedClient) IndexOptions(opts IndexingOptions, docs ...Document) error {
+	positions := make(map[string][]int, len(c.shards))
+	for ii, doc := range docs {
+		shard := c.ShardName(doc.Id)
+		positions[shard] = append(positions[shard], ii)
+	}
+	var merr MultiError
+	for shard, idx := range positions {
+		shardDocs := make([]Document, len(idx))
+		for j, ii := range idx {
+			shardDocs[j] = docs[ii]
+		}
+		err := c.client(shard).IndexOptions(opts, shardDocs...)
+		if err == nil {
+			continue
+		}
+		if merr == nil {
+			merr = NewMultiError(len(docs))
+		}
+		shardErrs, isMulti := err.(MultiError)
+		for j, ii := range idx {
+			if !isMulti {
+				merr[ii] = err
+			} else if j < len(shardErrs) {
+				merr[ii] = shardErrs[j]
+			}
+		}
+	}
+	if merr != nil {
+		return merr
+	}
+	return nil
+}
+
+// Get returns the full contents of a document, from the shard holding it
+func (c *ShardedClient) Get(docId string) (*Document, error) {
+	return c.Shard(docId).Get(docId)
+}
+
+// DeleteDocument deletes the document from the shard holding it
+func (c *ShardedClient) DeleteDocument(docId string) error {
+	return c.Shard(docId).DeleteDocument(docId)
+}
+
+// Search sends the query to every shard and merges the results, by the SORTBY key of the query if it is set,
+// by score otherwise. Each shard is asked for the first offset+num results, so that the global LIMIT offset/num
+// of the query is respected. The total is the sum of the totals of the shards.
+func (c *ShardedClient) Search(q *Query) (docs []Document, total int, err error) {
+	shardQuery := *q
+	shardQuery.Paging = Paging{Offset: 0, Num: q.Paging.Offset + q.Paging.Num}
+	if q.SortBy != nil {
+		if q.Flags&QueryNoContent != 0 {
+			return nil, 0, errors.New("ShardedClient: cannot merge NOCONTENT results by sort key")
+		}
+		field := strings.TrimPrefix(q.SortBy.Field, "@")
+		if q.ReturnFields != nil && sliceIndex(q.ReturnFields, field) == -1 {
+			shardQuery.ReturnFields = append(append([]string(nil), q.ReturnFields...), field)
+		}
+	} else {
+		shardQuery.Flags |= QueryWithScores
+	}
+
+	shardDocs := make([][]Document, len(c.shards))
+	totals := make([]int, len(c.shards))
+	err = c.each(func(ii int, client *Client) (err error) {
+		shardDocs[ii], totals[ii], err = client.Search(&shardQuery)
+		return
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for ii := range c.shards {
+		total += totals[ii]
+		docs = append(docs, shardDocs[ii]...)
+	}
+	if q.SortBy != nil {
+		field := strings.TrimPrefix(q.SortBy.Field, "@")
+		sort.SliceStable(docs, func(i, j int) bool {
+			cmp := compareValues(docs[i].Properties[field], docs[j].Properties[field])
+			if q.SortBy.Ascending {
+				return cmp < 0
+			}
+			return cmp > 0
+		})
+	} else {
+		sort.Stable(DocumentList(docs))
+	}
+	docs = pageDocuments(docs, q.Paging)
+	return docs, total, nil
+}
+
+func pageDocuments(docs []Document, p Paging) []Document {
+	if p.Offset >= len(docs) {
+		return []Document{}
+	}
+	end := p.Offset + p.Num
+	if end > len(docs) {
+		end = len(docs)
+	}
+	return docs[p.Offset:end]
+}
+
+// ShardedAggregation is an aggregation whose last step is a GROUPBY, run on every shard and merged.
+// Only the COUNT, SUM, MIN, MAX and AVG reducers can be merged exactly, and each of them must have an alias.
+type ShardedAggregation struct {
+	// Query holds the query and the steps of the pipeline before the GROUPBY (LOAD, APPLY, FILTER...).
+	// It must not have a LIMIT or a cursor, these are applied on the merged groups.
+	Query   *AggregateQuery
+	GroupBy GroupBy
+	// SortBy sorts the merged groups, by group field or reducer alias
+	SortBy []SortingKey
+	// Paging limits the merged groups
+	Paging *Paging
+}
+
+// internal struct
+// shardedReducer links a reducer of the merged GROUPBY to the partial reducers computed by the shards
+type shardedReducer struct {
+	name    GroupByReducers
+	alias   string
+	partial []string
+}
+
+// AggregateQuery runs the aggregation on every shard and merges the groups. It returns the number of merged
+// groups and the merged rows, with the group fields and the reducer aliases as keys.
+// AVG reducers are computed by the shards as the SUM of the property and the number of documents having it,
+// so that the merged average is exact, documents missing the property being left out as by AVG.
+func (c *ShardedClient) AggregateQuery(a ShardedAggregation) (total int, rows []map[string]interface{}, err error) {
+	shardQuery, reducers, err := a.shardQuery()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	shardRows := make([][]map[string]interface{}, len(c.shards))
+	err = c.each(func(ii int, client *Client) (err error) {
+		q := *shardQuery
+		_, shardRows[ii], err = client.AggregateQuery(&q)
+		return
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rows, err = mergeGroups(a.GroupBy.Fields, reducers, shardRows)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(a.SortBy) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, key := range a.SortBy {
+				field := strings.TrimPrefix(key.Field, "@")
+				cmp := compareValues(rows[i][field], rows[j][field])
+				if cmp == 0 {
+					continue
+				}
+				if key.Ascending {
+					return cmp < 0
+				}
+				return cmp > 0
+			}
+			return false
+		})
+	}
+	total = len(rows)
+	if a.Paging != nil {
+		if a.Paging.Offset >= len(rows) {
+			rows = rows[:0]
+		} else {
+			end := a.Paging.Offset + a.Paging.Num
+			if end > len(rows) {
+				end = len(rows)
+			}
+			rows = rows[a.Paging.Offset:end]
+		}
+	}
+	return total, rows, nil
+}
+
+// internal method
+// shardQuery returns the aggregation sent to the shards, with the GROUPBY rewritten into partial reducers
+func (a ShardedAggregation) shardQuery() (*AggregateQuery, []shardedReducer, error) {
+	q := NewAggregateQuery()
+	if a.Query != nil {
+		if a.Query.Paging != nil || a.Query.WithCursor {
+			return nil, nil, errors.New("ShardedClient: the aggregation query must not have a LIMIT or a cursor")
+		}
+		*q = *a.Query
+		q.AggregatePlan = redisArgsCopy(a.Query.AggregatePlan)
+	}
+	if len(a.GroupBy.Fields) == 0 {
+		return nil, nil, errors.New("ShardedClient: the GROUPBY has no fields")
+	}
+	if a.GroupBy.Paging != nil {
+		return nil, nil, errors.New("ShardedClient: the GROUPBY must not have a LIMIT")
+	}
+
+	group := NewGroupBy().AddFields(a.GroupBy.Fields)
+	reducers := make([]shardedReducer, 0, len(a.GroupBy.Reducers))
+	for _, r := range a.GroupBy.Reducers {
+		if r.Alias == "" {
+			return nil, nil, fmt.Errorf("ShardedClient: reducer %s requires an alias to be merged", r.Name)
+		}
+		sr := shardedReducer{name: r.Name, alias: r.Alias}
+		switch r.Name {
+		case GroupByReducerCount, GroupByReducerSum, GroupByReducerMin, GroupByReducerMax:
+			sr.partial = []string{r.Alias}
+			group.Reduce(r)
+		case GroupByReducerAvg:
+			if len(r.Args) != 1 {
+				return nil, nil, fmt.Errorf("ShardedClient: reducer %s requires a single property", r.Name)
+			}
+			// the documents missing the property are left out of the average: COUNT would include them,
+			// so the shards count the documents having it instead
+			sum, count, has := "__sum_"+r.Alias, "__count_"+r.Alias, "__has_"+r.Alias
+			sr.partial = []string{sum, count}
+			q.Apply(*NewProjection("exists("+r.Args[0]+")", has))
+			group.Reduce(*NewReducerAlias(GroupByReducerSum, r.Args, sum)).
+				Reduce(*NewReducerAlias(GroupByReducerSum, []string{"@" + has}, count))
+		default:
+			return nil, nil, fmt.Errorf("ShardedClient: reducer %s cannot be merged across shards", r.Name)
+		}
+		reducers = append(reducers, sr)
+	}
+	q.GroupBy(*group)
+	return q, reducers, nil
+}
+
+func redisArgsCopy(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	return append(make([]interface{}, 0, len(args)), args...)
+}
+
+// internal struct
+// mergedGroup accumulates the partial reducers of the same group
+type mergedGroup struct {
+	row    map[string]interface{}
+	values map[string]float64
+}
+
+// internal function
+// mergeGroups merges the groups returned by the shards, in order of first appearance
+func mergeGroups(fields []string, reducers []shardedReducer, shardRows [][]map[string]interface{}) ([]map[string]interface{}, error) {
+	keys := make([]string, len(fields))
+	for ii, field := range fields {
+		keys[ii] = strings.TrimPrefix(field, "@")
+	}
+
+	groups := make(map[string]*mergedGroup)
+	order := make([]string, 0)
+	for _, rows := range shardRows {
+		for _, row := range rows {
+			parts := make([]string, len(keys))
+			for ii, key := range keys {
+				parts[ii] = fmt.Sprint(row[key])
+			}
+			id := strings.Join(parts, "\x00")
+			g, found := groups[id]
+			if !found {
+				g = &mergedGroup{row: make(map[string]interface{}, len(keys)+len(reducers)), values: make(map[string]float64)}
+				for _, key := range keys {
+					if v, ok := row[key]; ok {
+						g.row[key] = v
+					}
+				}
+				groups[id] = g
+				order = append(order, id)
+			}
+			for _, r := range reducers {
+				for _, partial := range r.partial {
+					s, _ := row[partial].(string)
+					v, err := strconv.ParseFloat(s, 64)
+					if err != nil {
+						return nil, fmt.Errorf("ShardedClient: could not parse %s of group %s: %v", partial, id, err)
+					}
+					current, seen := g.values[partial]
+					switch {
+					case !seen:
+						g.values[partial] = v
+					case r.name == GroupByReducerMin:
+						g.values[partial] = math.Min(current, v)
+					case r.name == GroupByReducerMax:
+						g.values[partial] = math.Max(current, v)
+					default:
+						g.values[partial] = current + v
+					}
+				}
+			}
+		}
+	}
+
+	merged := make([]map[string]interface{}, len(order))
+	for ii, id := range order {
+		g := groups[id]
+		for _, r := range reducers {
+			v := g.values[r.partial[0]]
+			if r.name == GroupByReducerAvg {
+				if count := g.values[r.partial[1]]; count > 0 {
+					v = v / count
+				} else {
+					v = 0
+				}
+			}
+			g.row[r.alias] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		merged[ii] = g.row
+	}
+	return merged, nil
+}
+
+// internal function
+// compareValues compares two reply values, numerically if both are numbers, as strings otherwise.
+// Missing values are sorted first.
+func compareValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	as, bs := fmt.Sprint(a), fmt.Sprint(b)
+	af, aerr := strconv.ParseFloat(as, 64)
+	bf, berr := strconv.ParseFloat(bs, 64)
+	if aerr == nil && berr == nil {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(as, bs)
+}
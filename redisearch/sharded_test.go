@@ -0,0 +1,133 @@
+package redisearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedClient_ShardName(t *testing.T) {
+	pools := map[string]ConnPool{"a": nil, "b": nil, "c": nil}
+	c := NewShardedClientFromPools(pools, "idx")
+	assert.Equal(t, []string{"a", "b", "c"}, c.Shards())
+
+	counts := make(map[string]int)
+	placement := make(map[string]string)
+	for i := 0; i < 3000; i++ {
+		id := fmt.Sprintf("doc%d", i)
+		shard := c.ShardName(id)
+		counts[shard]++
+		placement[id] = shard
+	}
+	for _, shard := range c.Shards() {
+		assert.True(t, counts[shard] > 500, "shard %s holds %d documents", shard, counts[shard])
+	}
+
+	// adding a shard only moves documents to the new shard
+	pools["d"] = nil
+	c = NewShardedClientFromPools(pools, "idx")
+	for id, shard := range placement {
+		if moved := c.ShardName(id); moved != shard {
+			assert.Equal(t, "d", moved)
+		}
+	}
+}
+
+func TestShardedAggregation_shardQuery(t *testing.T) {
+	a := ShardedAggregation{
+		Query: NewAggregateQuery().SetQuery(NewQuery("*")).Filter("@price > 0"),
+		GroupBy: *NewGroupBy().AddFields("@brand").
+			Reduce(*Count()).
+			Reduce(*NewReducerAlias(GroupByReducerAvg, []string{"@price"}, "avg_price")),
+	}
+	q, reducers, err := a.shardQuery()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"*", "FILTER", "@price > 0",
+		"APPLY", "exists(@price)", "AS", "__has_avg_price", "GROUPBY", 1, "@brand",
+		"REDUCE", "COUNT", 0, "AS", "count",
+		"REDUCE", "SUM", 1, "@price", "AS", "__sum_avg_price",
+		"REDUCE", "SUM", 1, "@__has_avg_price", "AS", "__count_avg_price"}, q.Serialize())
+	assert.Equal(t, []shardedReducer{
+		{name: GroupByReducerCount, alias: "count", partial: []string{"count"}},
+		{name: GroupByReducerAvg, alias: "avg_price", partial: []string{"__sum_avg_price", "__count_avg_price"}},
+	}, reducers)
+	assert.Equal(t, []interface{}{"FILTER", "@price > 0"}, a.Query.AggregatePlan)
+
+	invalid := []ShardedAggregation{
+		{GroupBy: *NewGroupBy().AddFields("@brand").Reduce(*NewReducer(GroupByReducerCount, nil))},
+		{GroupBy: *NewGroupBy().AddFields("@brand").Reduce(*NewReducerAlias(GroupByReducerQuantile, []string{"@price", "0.5"}, "q"))},
+		{GroupBy: *NewGroupBy().Reduce(*Count())},
+		{Query: NewAggregateQuery().Limit(0, 10), GroupBy: *NewGroupBy().AddFields("@brand").Reduce(*Count())},
+		{GroupBy: *NewGroupBy().AddFields("@brand").Reduce(*NewReducerAlias(GroupByReducerAvg, nil, "avg"))},
+	}
+	for _, inv := range invalid {
+		_, _, err := inv.shardQuery()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestShardedClient_AggregateQuery_sparse(t *testing.T) {
+	skipInMemory(t, "FT.AGGREGATE")
+	c := createClient("sharded-sparse")
+	flush(c)
+	defer teardown(c)
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTagField("brand")).
+		AddField(NewNumericFieldOptions("price", NumericFieldOptions{Sortable: true}))
+	assert.Nil(t, c.CreateIndex(sc))
+	docs := []Document{
+		NewDocument("sparse:1", 1).Set("brand", "a").Set("price", 10),
+		NewDocument("sparse:2", 1).Set("brand", "a").Set("price", 20),
+		NewDocument("sparse:3", 1).Set("brand", "a"),
+		NewDocument("sparse:4", 1).Set("brand", "a"),
+	}
+	assert.Nil(t, c.Index(docs...))
+
+	// both shards hold the same documents: their partial sums and counts double, but not the average
+	sharded := NewShardedClientFromPools(map[string]ConnPool{"s1": c.pool, "s2": c.pool}, c.name)
+	_, rows, err := sharded.AggregateQuery(ShardedAggregation{
+		Query: NewAggregateQuery().SetQuery(NewQuery("*")),
+		GroupBy: *NewGroupBy().AddFields("@brand").
+			Reduce(*Count()).
+			Reduce(*NewReducerAlias(GroupByReducerAvg, []string{"@price"}, "avg_price")),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{{"brand": "a", "count": "8", "avg_price": "15"}}, rows)
+}
+
+func Test_mergeGroups(t *testing.T) {
+	reducers := []shardedReducer{
+		{name: GroupByReducerCount, alias: "count", partial: []string{"count"}},
+		{name: GroupByReducerMin, alias: "min_price", partial: []string{"min_price"}},
+		{name: GroupByReducerAvg, alias: "avg_price", partial: []string{"__sum_avg_price", "__count_avg_price"}},
+	}
+	shardRows := [][]map[string]interface{}{
+		{
+			{"brand": "a", "count": "2", "min_price": "5", "__sum_avg_price": "30", "__count_avg_price": "2"},
+			{"brand": "b", "count": "1", "min_price": "7", "__sum_avg_price": "7", "__count_avg_price": "1"},
+		},
+		{
+			{"brand": "a", "count": "1", "min_price": "3", "__sum_avg_price": "3", "__count_avg_price": "1"},
+			// a sparse price: only one of the three documents has it
+			{"brand": "c", "count": "3", "min_price": "4", "__sum_avg_price": "4", "__count_avg_price": "1"},
+		},
+	}
+	rows, err := mergeGroups([]string{"@brand"}, reducers, shardRows)
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"brand": "a", "count": "3", "min_price": "3", "avg_price": "11"},
+		{"brand": "b", "count": "1", "min_price": "7", "avg_price": "7"},
+		{"brand": "c", "count": "3", "min_price": "4", "avg_price": "4"},
+	}, rows)
+
+	_, err = mergeGroups([]string{"@brand"}, reducers[:1], [][]map[string]interface{}{{{"brand": "a", "count": "x"}}})
+	assert.NotNil(t, err)
+}
+
+func Test_compareValues(t *testing.T) {
+	assert.Equal(t, -1, compareValues("9", "10"))
+	assert.Equal(t, 1, compareValues("b", "a"))
+	assert.Equal(t, 0, compareValues("1.0", "1"))
+	assert.Equal(t, -1, compareValues(nil, "a"))
+}
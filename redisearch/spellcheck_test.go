@@ -3,8 +3,6 @@ package redisearch
 import (
 	"reflect"
 	"testing"
-
-	"github.com/gomodule/redigo/redis"
 )
 
 func TestMisspelledTerm_Len(t *testing.T) {
@@ -331,12 +329,12 @@ func TestSpellCheckOptions_serialize(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   redis.Args
+		want   []interface{}
 	}{
-		{"empty", fields{1, []string{}, []string{}}, redis.Args{}},
-		{"exclude", fields{1, []string{"dict1"}, []string{}}, redis.Args{"TERMS", "EXCLUDE", "dict1"}},
-		{"include", fields{1, []string{}, []string{"dict1"}}, redis.Args{"TERMS", "INCLUDE", "dict1"}},
-		{"all", fields{2, []string{"dict1"}, []string{"dict2"}}, redis.Args{"DISTANCE", 2, "TERMS", "EXCLUDE", "dict1", "TERMS", "INCLUDE", "dict2"}},
+		{"empty", fields{1, []string{}, []string{}}, []interface{}{}},
+		{"exclude", fields{1, []string{"dict1"}, []string{}}, []interface{}{"TERMS", "EXCLUDE", "dict1"}},
+		{"include", fields{1, []string{}, []string{"dict1"}}, []interface{}{"TERMS", "INCLUDE", "dict1"}},
+		{"all", fields{2, []string{"dict1"}, []string{"dict2"}}, []interface{}{"DISTANCE", 2, "TERMS", "EXCLUDE", "dict1", "TERMS", "INCLUDE", "dict2"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
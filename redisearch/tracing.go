@@ -0,0 +1,478 @@
+package redisearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Tracer starts the spans of the traced commands. It is small enough to be implemented on top of
+// an OpenTelemetry tracer, e.g. with trace.Tracer.Start and trace.Span.SetAttributes.
+type Tracer interface {
+	// StartSpan starts a span in the given context, the CommandInfo.Context of the traced command,
+	// which holds the parent span if any
+	StartSpan(ctx context.Context, name string, start time.Time) Span
+}
+
+// Span is a traced operation
+type Span interface {
+	SetAttribute(key string, value interface{})
+	AddEvent(name string, attributes map[string]interface{})
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// Span attribute keys
+const (
+	AttrDBSystem   = "db.system"
+	AttrOperation  = "db.operation"
+	AttrIndex      = "redisearch.index"
+	AttrKey        = "redisearch.key"
+	AttrQuery      = "redisearch.query"
+	AttrDialect    = "redisearch.dialect"
+	AttrResults    = "redisearch.results"
+	AttrTotal      = "redisearch.total"
+	AttrCommands   = "redisearch.commands"
+	AttrDurationMs = "redisearch.duration_ms"
+)
+
+// QueryRedaction is how the query strings are recorded in the spans
+type QueryRedaction int
+
+const (
+	// QueryRedactValues records the query structure, replacing the terms and values with "?", see RedactQuery
+	QueryRedactValues QueryRedaction = iota
+	// QueryFull records the query strings as is
+	QueryFull
+	// QueryOmit does not record the query strings
+	QueryOmit
+)
+
+// TracingOptions are the options of NewTracingHook
+type TracingOptions struct {
+	// Query is how the query strings are recorded, by default their values are redacted
+	Query QueryRedaction
+}
+
+// NewTracingHook creates a Hook producing a span per command with the given tracer, e.g.
+//
+//	c.AddHook(redisearch.NewTracingHook(tracer, nil))
+//
+// The spans are named after the command (e.g. FT.SEARCH or FT.CURSOR READ), and carry the index name, the query,
+// the dialect, the number of results and the total of search and aggregate replies, and the error if any.
+// Pipelined commands produce a single "pipeline" span with an event per command, ended with an error if the
+// pipeline is abandoned before all its replies were received.
+func NewTracingHook(tracer Tracer, opts *TracingOptions) Hook {
+	if opts == nil {
+		opts = &TracingOptions{}
+	}
+	return &tracingHook{
+		tracer:    tracer,
+		opts:      *opts,
+		commands:  make(map[*CommandInfo]Span),
+		pipelines: make(map[*PipelineInfo]Span),
+	}
+}
+
+// internal struct
+// tracingHook keeps the spans of the in-flight commands and pipelines
+type tracingHook struct {
+	tracer    Tracer
+	opts      TracingOptions
+	mu        sync.Mutex
+	commands  map[*CommandInfo]Span
+	pipelines map[*PipelineInfo]Span
+}
+
+func (h *tracingHook) BeforeCommand(cmd *CommandInfo) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if cmd.Pipeline != nil {
+		if _, ok := h.pipelines[cmd.Pipeline]; !ok {
+			span := h.tracer.StartSpan(commandContext(cmd), "pipeline", cmd.Pipeline.Start)
+			span.SetAttribute(AttrDBSystem, "redis")
+			h.pipelines[cmd.Pipeline] = span
+		}
+		return nil
+	}
+	span := h.tracer.StartSpan(commandContext(cmd), operationName(cmd), cmd.Start)
+	for key, value := range h.commandAttributes(cmd) {
+		span.SetAttribute(key, value)
+	}
+	h.commands[cmd] = span
+	return nil
+}
+
+func (h *tracingHook) AfterCommand(cmd *CommandInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if cmd.Pipeline != nil {
+		span, ok := h.pipelines[cmd.Pipeline]
+		if !ok {
+			return
+		}
+		attributes := h.commandAttributes(cmd)
+		attributes[AttrDurationMs] = float64(cmd.Duration) / float64(time.Millisecond)
+		addReplyAttributes(attributes, cmd)
+		if cmd.Err != nil {
+			attributes["error"] = cmd.Err.Error()
+			span.RecordError(cmd.Err)
+		}
+		span.AddEvent(operationName(cmd), attributes)
+		if cmd.Pipeline.Done() {
+			span.SetAttribute(AttrCommands, cmd.Pipeline.Commands)
+			span.End(cmd.Start.Add(cmd.Duration))
+			delete(h.pipelines, cmd.Pipeline)
+		}
+		return
+	}
+	span, ok := h.commands[cmd]
+	if !ok {
+		return
+	}
+	delete(h.commands, cmd)
+	attributes := make(map[string]interface{})
+	addReplyAttributes(attributes, cmd)
+	for key, value := range attributes {
+		span.SetAttribute(key, value)
+	}
+	if cmd.Err != nil {
+		span.RecordError(cmd.Err)
+	}
+	span.End(cmd.Start.Add(cmd.Duration))
+}
+
+// AbortPipeline ends the span of a pipeline abandoned before all its replies were received
+func (h *tracingHook) AbortPipeline(pipeline *PipelineInfo, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	span, ok := h.pipelines[pipeline]
+	if !ok {
+		return
+	}
+	delete(h.pipelines, pipeline)
+	span.RecordError(err)
+	span.SetAttribute(AttrCommands, pipeline.Commands)
+	span.End(time.Now())
+}
+
+// internal function
+// commandContext returns the context of the command, or context.Background() if a hook cleared it
+func commandContext(cmd *CommandInfo) context.Context {
+	if cmd.Context == nil {
+		return context.Background()
+	}
+	return cmd.Context
+}
+
+// internal function
+// operationName returns the command name, with the subcommand for the commands that have one
+func operationName(cmd *CommandInfo) string {
+	switch strings.ToUpper(cmd.Name) {
+	case "FT.CURSOR", "FT.CONFIG":
+		if len(cmd.Args) > 0 {
+			return cmd.Name + " " + strings.ToUpper(argString(cmd.Args[0]))
+		}
+	}
+	return cmd.Name
+}
+
+// internal method
+// commandAttributes returns the span attributes known before the command is run
+func (h *tracingHook) commandAttributes(cmd *CommandInfo) map[string]interface{} {
+	attributes := map[string]interface{}{
+		AttrDBSystem:  "redis",
+		AttrOperation: operationName(cmd),
+	}
+	index, key := commandTarget(cmd)
+	if index != "" {
+		attributes[AttrIndex] = index
+	}
+	if key != "" {
+		attributes[AttrKey] = key
+	}
+	qi := queryArgIndex(cmd)
+	if qi < 0 {
+		return attributes
+	}
+	if h.opts.Query != QueryOmit {
+		query := argString(cmd.Args[qi])
+		if h.opts.Query == QueryRedactValues {
+			query = RedactQuery(query)
+		}
+		attributes[AttrQuery] = query
+	}
+	// the options follow the query, which may itself contain the DIALECT word
+	for ii := qi + 1; ii+1 < len(cmd.Args); ii++ {
+		if strings.EqualFold(argString(cmd.Args[ii]), "DIALECT") {
+			attributes[AttrDialect] = argString(cmd.Args[ii+1])
+		}
+	}
+	return attributes
+}
+
+// internal function
+// queryArgIndex returns the index of the query string in the arguments of the command, or -1 if it has none.
+// The query follows the index name, except for FT.PROFILE where it follows the QUERY token:
+// FT.PROFILE idx SEARCH|AGGREGATE [LIMITED] QUERY query ...
+func queryArgIndex(cmd *CommandInfo) int {
+	switch strings.ToUpper(cmd.Name) {
+	case "FT.SEARCH", "FT.AGGREGATE", "FT.EXPLAIN", "FT.SPELLCHECK":
+		if len(cmd.Args) > 1 {
+			return 1
+		}
+	case "FT.PROFILE":
+		for ii := 2; ii+1 < len(cmd.Args); ii++ {
+			if strings.EqualFold(argString(cmd.Args[ii]), "QUERY") {
+				return ii + 1
+			}
+		}
+	}
+	return -1
+}
+
+// internal function
+// commandTarget returns the index a command applies to, or the key for the non-index commands
+func commandTarget(cmd *CommandInfo) (index, key string) {
+	name := strings.ToUpper(cmd.Name)
+	switch {
+	case name == "FT.CURSOR":
+		if len(cmd.Args) > 1 {
+			index = argString(cmd.Args[1])
+		}
+	case name == "FT.CONFIG" || name == "FT._LIST" || strings.HasPrefix(name, "FT.DICT"):
+	case strings.HasPrefix(name, "FT.") && !strings.HasPrefix(name, "FT.SUG"):
+		if len(cmd.Args) > 0 {
+			index = argString(cmd.Args[0])
+		}
+	default:
+		if len(cmd.Args) > 0 {
+			key = argString(cmd.Args[0])
+		}
+	}
+	return
+}
+
+// internal function
+// addReplyAttributes adds the number of results and the total of search and aggregate replies
+func addReplyAttributes(attributes map[string]interface{}, cmd *CommandInfo) {
+	if results, total, ok := replyCounts(cmd); ok {
+		attributes[AttrResults] = results
+		attributes[AttrTotal] = total
+	}
+}
+
+// internal function
+// replyCounts returns the number of results and the total of search and aggregate replies
+func replyCounts(cmd *CommandInfo) (results int, total int64, ok bool) {
+	if cmd.Err != nil || cmd.Reply == nil {
+		return
+	}
+	name := strings.ToUpper(cmd.Name)
+	reply := cmd.Reply
+	switch name {
+	case "FT.SEARCH", "FT.AGGREGATE", "FT.CURSOR":
+	default:
+		return
+	}
+	// the flags follow the index name and the query string
+	flags := cmd.Args
+	if len(flags) > 2 {
+		flags = flags[2:]
+	}
+	if name == "FT.CURSOR" || (name == "FT.AGGREGATE" && hasArg(flags, "WITHCURSOR")) {
+		values, isArray := reply.([]interface{})
+		if !isArray || len(values) != 2 {
+			return
+		}
+		reply = values[0]
+	}
+	if m, isMap := reply.(RESP3Map); isMap {
+		value, _ := m.Get("total_results")
+		total, _ = redis.Int64(value, nil)
+		value, _ = m.Get("results")
+		values, _ := value.([]interface{})
+		return len(values), total, true
+	}
+	values, isArray := reply.([]interface{})
+	if !isArray || len(values) == 0 {
+		return
+	}
+	total, _ = redis.Int64(values[0], nil)
+	stride := 1
+	if name == "FT.SEARCH" {
+		stride = 2
+		for _, flag := range []string{"WITHSCORES", "WITHPAYLOADS", "WITHSORTKEYS"} {
+			if hasArg(flags, flag) {
+				stride++
+			}
+		}
+		if hasArg(flags, "NOCONTENT") {
+			stride--
+		}
+	}
+	return (len(values) - 1) / stride, total, true
+}
+
+func hasArg(args []interface{}, flag string) bool {
+	for _, arg := range args {
+		if strings.EqualFold(argString(arg), flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func argString(arg interface{}) string {
+	switch arg := arg.(type) {
+	case string:
+		return arg
+	case []byte:
+		return string(arg)
+	}
+	return fmt.Sprint(arg)
+}
+
+// RedactQuery returns the structure of a query string, replacing its terms, phrases, tags and numbers with "?".
+// Field names, parameter references ($name), operators and attributes are kept, e.g.
+// `@title:hello @price:[10 20] -"big deal"` becomes `@title:? @price:[? ?] -?`.
+func RedactQuery(query string) string {
+	var sb strings.Builder
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '@' || r == '$':
+			// field name or parameter reference
+			j := i + 1
+			for j < len(runes) && isQueryWordRune(runes[j]) {
+				j++
+			}
+			sb.WriteString(string(runes[i:j]))
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			sb.WriteRune('?')
+			i = j + 1
+		case r == '\\' || isQueryWordRune(r) || r == '*' || r == '%':
+			j := i
+			for j < len(runes) && (runes[j] == '\\' || isQueryWordRune(runes[j]) || runes[j] == '*' || runes[j] == '%') {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j > len(runes) {
+				j = len(runes)
+			}
+			if strings.EqualFold(string(runes[i:j]), "inf") {
+				sb.WriteString(string(runes[i:j]))
+			} else {
+				sb.WriteRune('?')
+			}
+			i = j
+		default:
+			sb.WriteRune(r)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func isQueryWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// RecordingTracer is an in-memory Tracer, recording the spans for tests
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span recorded by a RecordingTracer
+type RecordedSpan struct {
+	tracer *RecordingTracer
+	// Context is the context the span was started in
+	Context    context.Context
+	Name       string
+	Start      time.Time
+	EndTime    time.Time
+	Attributes map[string]interface{}
+	Events     []RecordedEvent
+	Errors     []error
+	Ended      bool
+}
+
+// RecordedEvent is an event of a RecordedSpan
+type RecordedEvent struct {
+	Name       string
+	Attributes map[string]interface{}
+}
+
+// NewRecordingTracer creates an empty RecordingTracer
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+// StartSpan implements Tracer
+func (t *RecordingTracer) StartSpan(ctx context.Context, name string, start time.Time) Span {
+	span := &RecordedSpan{tracer: t, Context: ctx, Name: name, Start: start, Attributes: make(map[string]interface{})}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return span
+}
+
+// Spans returns the spans recorded so far, in the order they were started
+func (t *RecordingTracer) Spans() []*RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*RecordedSpan{}, t.spans...)
+}
+
+// Reset forgets the recorded spans
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	t.spans = nil
+	t.mu.Unlock()
+}
+
+// SetAttribute implements Span
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mu.Lock()
+	s.Attributes[key] = value
+	s.tracer.mu.Unlock()
+}
+
+// AddEvent implements Span
+func (s *RecordedSpan) AddEvent(name string, attributes map[string]interface{}) {
+	s.tracer.mu.Lock()
+	s.Events = append(s.Events, RecordedEvent{Name: name, Attributes: attributes})
+	s.tracer.mu.Unlock()
+}
+
+// RecordError implements Span
+func (s *RecordedSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	s.Errors = append(s.Errors, err)
+	s.tracer.mu.Unlock()
+}
+
+// End implements Span
+func (s *RecordedSpan) End(end time.Time) {
+	s.tracer.mu.Lock()
+	s.EndTime = end
+	s.Ended = true
+	s.tracer.mu.Unlock()
+}
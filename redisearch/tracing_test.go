@@ -0,0 +1,165 @@
+package redisearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"hello world", "? ?"},
+		{`@title:hello @price:[10 20] -"big deal"`, `@title:? @price:[? ?] -?`},
+		{"@tags:{foo | bar\\ baz}", "@tags:{? | ?}"},
+		{"@price:[-inf (100]", "@price:[-inf (?]"},
+		{"@title:$term hel*", "@title:$term ?"},
+		{"*", "?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactQuery(tt.query))
+		})
+	}
+}
+
+func TestNewTracingHook(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{
+		{Value: []interface{}{int64(5), []byte("doc1"), []byte("1.5"), []interface{}{}, []byte("doc2"), []byte("1"), []interface{}{}}},
+		{Err: redis.Error("Unknown index name")},
+	}}
+	tracer := NewRecordingTracer()
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(NewTracingHook(tracer, nil))
+
+	q := NewQuery("@title:secret").SetFlags(QueryWithScores)
+	q.Dialect = 2
+	_, _, err := c.Search(q)
+	assert.Nil(t, err)
+	_, err = c.Info()
+	assert.NotNil(t, err)
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "FT.SEARCH", spans[0].Name)
+	assert.True(t, spans[0].Ended)
+	assert.Equal(t, "idx", spans[0].Attributes[AttrIndex])
+	assert.Equal(t, "@title:?", spans[0].Attributes[AttrQuery])
+	assert.Equal(t, "2", spans[0].Attributes[AttrDialect])
+	assert.Equal(t, int64(5), spans[0].Attributes[AttrTotal])
+	assert.Equal(t, 2, spans[0].Attributes[AttrResults])
+	assert.Equal(t, "FT.INFO", spans[1].Name)
+	assert.Equal(t, []error{redis.Error("Unknown index name")}, spans[1].Errors)
+}
+
+func TestNewTracingHook_pipeline(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Value: int64(1)}, {Err: redis.Error("ERR bad")}}}
+	tracer := NewRecordingTracer()
+	a := NewAutocompleterFromExecutor(exec, "ac")
+	a.AddHook(NewTracingHook(tracer, &TracingOptions{Query: QueryFull}))
+
+	err := a.AddTerms(Suggestion{Term: "a", Score: 1}, Suggestion{Term: "b", Score: 1})
+	assert.NotNil(t, err)
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "pipeline", spans[0].Name)
+	assert.True(t, spans[0].Ended)
+	assert.Equal(t, 2, spans[0].Attributes[AttrCommands])
+	assert.Len(t, spans[0].Events, 2)
+	assert.Equal(t, "FT.SUGADD", spans[0].Events[0].Name)
+	assert.Equal(t, "ac", spans[0].Events[0].Attributes[AttrKey])
+	assert.Equal(t, "ERR bad", spans[0].Events[1].Attributes["error"])
+}
+
+func TestNewTracingHook_pipelineClosed(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Value: int64(1)}}}
+	tracer := NewRecordingTracer()
+	hook := NewTracingHook(tracer, nil)
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(hook)
+
+	// the pipeline is closed without receiving its replies, e.g. after an early return
+	conn := c.pool.Get()
+	assert.Nil(t, conn.Send("HSET", "doc1", "foo", "bar"))
+	assert.Nil(t, conn.Send("HSET", "doc2", "foo", "baz"))
+	assert.Nil(t, conn.Close())
+
+	conn = c.pool.Get()
+	assert.Nil(t, conn.Send("HSET", "doc3", "foo", "bar"))
+	assert.Nil(t, conn.Flush())
+	assert.Nil(t, conn.Close())
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "pipeline", span.Name)
+		assert.True(t, span.Ended)
+		assert.Equal(t, []error{ErrPipelineClosed}, span.Errors)
+		assert.Empty(t, span.Events)
+	}
+	assert.Equal(t, 2, spans[0].Attributes[AttrCommands])
+	assert.Equal(t, 1, spans[1].Attributes[AttrCommands])
+	assert.Empty(t, hook.(*tracingHook).pipelines)
+}
+
+// contextHook sets the context of the commands
+type contextHook struct {
+	ctx context.Context
+}
+
+func (h contextHook) BeforeCommand(cmd *CommandInfo) error {
+	cmd.Context = h.ctx
+	return nil
+}
+
+func (h contextHook) AfterCommand(*CommandInfo) {}
+
+type contextKey struct{}
+
+func TestNewTracingHook_context(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Value: "OK"}, {Value: "OK"}}}
+	tracer := NewRecordingTracer()
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(NewTracingHook(tracer, nil))
+	_, err := c.Info()
+	assert.NotNil(t, err)
+
+	// the context set by a previous hook is the parent of the span
+	ctx := context.WithValue(context.Background(), contextKey{}, "parent")
+	c = NewClientFromExecutor(exec, "idx")
+	c.AddHook(contextHook{ctx}, NewTracingHook(tracer, nil))
+	_, err = c.Info()
+	assert.NotNil(t, err)
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, context.Background(), spans[0].Context)
+	assert.Equal(t, ctx, spans[1].Context)
+}
+
+func TestNewTracingHook_profile(t *testing.T) {
+	exec := &fakeExecutor{replies: []Reply{{Err: redis.Error("ERR")}, {Err: redis.Error("ERR")}}}
+	tracer := NewRecordingTracer()
+	c := NewClientFromExecutor(exec, "idx")
+	c.AddHook(NewTracingHook(tracer, &TracingOptions{Query: QueryFull}))
+
+	// the query follows the QUERY token, after the optional LIMITED
+	_, _, _, err := c.Profile(NewQuery("hello").SetDialect(3), true)
+	assert.NotNil(t, err)
+	// a query containing DIALECT does not hide the dialect option
+	_, _, err = c.Search(NewQuery("DIALECT"))
+	assert.NotNil(t, err)
+
+	spans := tracer.Spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, []interface{}{"idx", "SEARCH", "LIMITED", "QUERY", "hello", "DIALECT", 3}, exec.cmds[0].Args)
+	assert.Equal(t, "hello", spans[0].Attributes[AttrQuery])
+	assert.Equal(t, "3", spans[0].Attributes[AttrDialect])
+	assert.Equal(t, "DIALECT", spans[1].Attributes[AttrQuery])
+	assert.NotContains(t, spans[1].Attributes, AttrDialect)
+}
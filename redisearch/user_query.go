@@ -0,0 +1,92 @@
+package redisearch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserQuery builds a query from user input, e.g. the text of a search box, without writing the input into the
+// query string: each value is bound as a $param of a DIALECT 2 query, so that no input can change the meaning
+// of the query. The clauses of a UserQuery are intersected, e.g.
+//
+//	q := redisearch.NewUserQuery().
+//		Match("title", r.FormValue("q")).
+//		Tags("category", r.Form["category"]...).
+//		Query().Limit(0, 10)
+type UserQuery struct {
+	clauses []string
+	params  map[string]interface{}
+}
+
+// NewUserQuery creates an empty user query, matching all the documents
+func NewUserQuery() *UserQuery {
+	return &UserQuery{
+		clauses: make([]string, 0),
+		params:  make(map[string]interface{}),
+	}
+}
+
+// internal function
+// bind binds a value to a new parameter and returns its reference in the query string
+func (u *UserQuery) bind(value interface{}) string {
+	name := fmt.Sprintf("input%d", len(u.params))
+	u.params[name] = value
+	return "$" + name
+}
+
+// internal function
+// field returns the field modifier of a clause, or "" for all the text fields
+func (u *UserQuery) field(field string) string {
+	if field == "" {
+		return ""
+	}
+	return "@" + EscapeFieldName(field) + ":"
+}
+
+// Match adds a clause matching all the words of the input in the field, or in all the text fields if field is "".
+// The input is split into words like the documents are tokenized, an input without words adds no clause.
+func (u *UserQuery) Match(field string, input string) *UserQuery {
+	words := strings.FieldsFunc(input, isQuerySeparator)
+	if len(words) == 0 {
+		return u
+	}
+	refs := make([]string, len(words))
+	for ii, word := range words {
+		refs[ii] = u.bind(word)
+	}
+	u.clauses = append(u.clauses, fmt.Sprintf("%s(%s)", u.field(field), strings.Join(refs, " ")))
+	return u
+}
+
+// Tags adds a clause matching any of the values in the tag field. No values adds no clause.
+func (u *UserQuery) Tags(field string, values ...string) *UserQuery {
+	if len(values) == 0 {
+		return u
+	}
+	refs := make([]string, len(values))
+	for ii, value := range values {
+		refs[ii] = u.bind(value)
+	}
+	u.clauses = append(u.clauses, fmt.Sprintf("%s{%s}", u.field(field), strings.Join(refs, " | ")))
+	return u
+}
+
+// Range adds a clause matching the values of the numeric field between min and max inclusive. min and max can be
+// numbers, or strings such as "-inf" and "+inf".
+func (u *UserQuery) Range(field string, min, max interface{}) *UserQuery {
+	u.clauses = append(u.clauses, fmt.Sprintf("%s[%s %s]", u.field(field), u.bind(min), u.bind(max)))
+	return u
+}
+
+// Query returns the DIALECT 2 query of the clauses, with the input bound as its params
+func (u *UserQuery) Query() *Query {
+	raw := "*"
+	if len(u.clauses) > 0 {
+		raw = strings.Join(u.clauses, " ")
+	}
+	params := make(map[string]interface{}, len(u.params))
+	for name, value := range u.params {
+		params[name] = value
+	}
+	return NewQuery(raw).SetParams(params).SetDialect(2)
+}
@@ -0,0 +1,72 @@
+package redisearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserQuery_Query(t *testing.T) {
+	q := NewUserQuery().Query()
+	assert.Equal(t, "*", q.Raw)
+	assert.Equal(t, 2, q.Dialect)
+
+	q = NewUserQuery().
+		Match("", "hello, world!").
+		Match("title", "   ").
+		Match("$.title", `-foo | @bar:{x} "baz`).
+		Tags("tags", "new york", "}").
+		Range("price", 10, "+inf").
+		Query()
+	assert.Equal(t, `($input0 $input1) @\$\.title:($input2 $input3 $input4 $input5) `+
+		`@tags:{$input6 | $input7} @price:[$input8 $input9]`, q.Raw)
+	assert.Equal(t, map[string]interface{}{
+		"input0": "hello", "input1": "world",
+		"input2": "foo", "input3": "bar", "input4": "x", "input5": "baz",
+		"input6": "new york", "input7": "}",
+		"input8": 10, "input9": "+inf",
+	}, q.Params)
+
+	// the query string is valid whatever the input
+	_, err := q.Parse()
+	assert.Nil(t, err)
+}
+
+func TestUserQuery_search(t *testing.T) {
+	c := createClient("TestUserQuery")
+	version, _ := c.getRediSearchVersion()
+	if version < 20430 {
+		// params are available for RediSearch 2.4.3+
+		return
+	}
+	sc := NewSchema(DefaultOptions).
+		AddField(NewTextField("title")).
+		AddField(NewTagField("city")).
+		AddField(NewNumericField("price"))
+	c.Drop()
+	assert.Nil(t, c.CreateIndex(sc))
+	assert.Nil(t, c.Index(
+		NewDocument("uq1", 1).Set("title", "hello world").Set("city", "new york").Set("price", 10),
+		NewDocument("uq2", 1).Set("title", "hello there").Set("city", "paris").Set("price", 20),
+	))
+
+	_, total, err := c.Search(NewUserQuery().Match("title", "Hello, world!").Query())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+
+	// the syntax of the input is not interpreted
+	_, total, err = c.Search(NewUserQuery().Match("title", "hello -world").Query())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	_, total, err = c.Search(NewUserQuery().Tags("city", "new york", "paris} | @title:(hello").Query())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+
+	docs, total, err := c.Search(NewUserQuery().Match("", "hello").Range("price", 15, "+inf").Query())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, docs, 1) {
+		assert.Equal(t, "uq2", docs[0].Id)
+	}
+	teardown(c)
+}